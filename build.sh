@@ -34,6 +34,14 @@ then
     exit 1
 fi
 
+echo "start to get compress"
+go get -d github.com/klauspost/compress@v1.18.0
+if [ $? -ne 0 ]
+then
+    echo "fail to get compress"
+    exit 1
+fi
+
 echo "start to get go-jmespath"
 go get -d github.com/jmespath/go-jmespath@c2b33e8
 if [ $? -ne 0 ]
@@ -46,9 +54,11 @@ chmod +w ./pkg -R
 mkdir -p ./src/github.com/baidubce
 mkdir -p ./src/github.com/aws
 mkdir -p ./src/github.com/jmespath
+mkdir -p ./src/github.com/klauspost
 mv pkg/mod/github.com/baidubce/bce-sdk-go* ./src/github.com/baidubce/bce-sdk-go
 mv pkg/mod/github.com/aws/aws-sdk-go* ./src/github.com/aws/aws-sdk-go
 mv pkg/mod/github.com/jmespath/go-jmespath* ./src/github.com/jmespath/go-jmespath
+mv pkg/mod/github.com/klauspost/compress* ./src/github.com/klauspost/compress
 
 go env -w GO111MODULE=off
 
@@ -12,10 +12,6 @@
 
 package argparser
 
-import (
-	"fmt"
-)
-
 import (
 	"github.com/alecthomas/kingpin"
 )
@@ -43,7 +39,20 @@ type BosApiArgs struct {
 	srcPath       string
 	storageClass  string
 	template      bool
+	all           bool
+	policyTmpl    string
 	canned        string
+	tags          string
+	versionId     string
+	status        string
+	sse           string
+	sseKmsKeyId   string
+	prefix        string
+	keyMarker     string
+	uploadId      string
+	uploadIdMark  string
+	partMarker    int64
+	maxKeys       int
 }
 
 // Put ACL
@@ -60,46 +69,165 @@ func (b *BosApiArgs) getBucketAcl(context *kingpin.ParseContext) error {
 	return nil
 }
 
+// Put object ACL
+func (b *BosApiArgs) putObjectAcl(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutObjectAcl(b.srcPath, b.srcBosPath, b.srcBosKeyPath, b.canned)
+	return nil
+}
+
+// Get object ACL
+func (b *BosApiArgs) getObjectAcl(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetObjectAcl(b.srcBosPath, b.srcBosKeyPath)
+	return nil
+}
+
+// Put bucket policy
+func (b *BosApiArgs) putBucketPolicy(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutBucketPolicy(b.srcPath, b.srcBosPath, b.policyTmpl)
+	return nil
+}
+
+// Get bucket policy
+func (b *BosApiArgs) getBucketPolicy(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetBucketPolicy(b.srcBosPath)
+	return nil
+}
+
+// Delete bucket policy
+func (b *BosApiArgs) deleteBucketPolicy(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.DeleteBucketPolicy(b.srcBosPath)
+	return nil
+}
+
+// Put bucket CORS
+func (b *BosApiArgs) putBucketCors(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutBucketCors(b.srcPath, b.srcBosPath)
+	return nil
+}
+
+// Get bucket CORS
+func (b *BosApiArgs) getBucketCors(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetBucketCors(b.srcBosPath)
+	return nil
+}
+
+// Delete bucket CORS
+func (b *BosApiArgs) deleteBucketCors(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.DeleteBucketCors(b.srcBosPath)
+	return nil
+}
+
+// Put bucket tagging
+func (b *BosApiArgs) putBucketTagging(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutBucketTagging(b.srcBosPath, b.tags)
+	return nil
+}
+
+// Get bucket tagging
+func (b *BosApiArgs) getBucketTagging(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetBucketTagging(b.srcBosPath)
+	return nil
+}
+
+// Delete bucket tagging
+func (b *BosApiArgs) deleteBucketTagging(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.DeleteBucketTagging(b.srcBosPath)
+	return nil
+}
+
+// Put object tagging
+func (b *BosApiArgs) putObjectTagging(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutObjectTagging(b.srcBosPath, b.srcBosKeyPath, b.tags)
+	return nil
+}
+
+// Get object tagging
+func (b *BosApiArgs) getObjectTagging(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetObjectTagging(b.srcBosPath, b.srcBosKeyPath)
+	return nil
+}
+
+// Put bucket versioning
+func (b *BosApiArgs) putBucketVersioning(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutBucketVersioning(b.srcBosPath, b.status)
+	return nil
+}
+
+// Get bucket versioning
+func (b *BosApiArgs) getBucketVersioning(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetBucketVersioning(b.srcBosPath)
+	return nil
+}
+
+// Put bucket encryption
+func (b *BosApiArgs) putBucketEncryption(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.PutBucketEncryption(b.srcBosPath, b.sse, b.sseKmsKeyId)
+	return nil
+}
+
+// Get bucket encryption
+func (b *BosApiArgs) getBucketEncryption(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.GetBucketEncryption(b.srcBosPath)
+	return nil
+}
+
 // Put lifecycle
 func (b *BosApiArgs) putLifecycle(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	//bosapiClient.PutLifecycle(b.srcPath, b.srcBosPath, b.template)
-	return fmt.Errorf("not support!")
+	bosapiClient.PutLifecycle(b.srcPath, b.srcBosPath, b.template)
+	return nil
 }
 
 // Get lifecycle
 func (b *BosApiArgs) getLifecycle(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	//bosapiClient.GetLifecycle(b.srcBosPath)
-	return fmt.Errorf("not support!")
+	bosapiClient.GetLifecycle(b.srcBosPath)
+	return nil
 }
 
 // Delete lifecycle
 func (b *BosApiArgs) deleteLifecycle(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	//bosapiClient.DeleteLifecycle(b.srcBosPath)
-	return fmt.Errorf("not support!")
+	bosapiClient.DeleteLifecycle(b.srcBosPath)
+	return nil
 }
 
 // Put logging
 func (b *BosApiArgs) putLogging(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	//bosapiClient.PutLogging(b.srcBosPath, b.srcBosKeyPath, b.dstBosPath)
-	return fmt.Errorf("not support!")
+	bosapiClient.PutLogging(b.srcBosPath, b.srcBosKeyPath, b.dstBosPath)
+	return nil
 }
 
 // Get logging
 func (b *BosApiArgs) getLogging(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	//bosapiClient.GetLogging(b.srcBosPath)
-	return fmt.Errorf("not support!")
+	bosapiClient.GetLogging(b.srcBosPath, b.all)
+	return nil
 }
 
 // Delete logging
 func (b *BosApiArgs) deleteLogging(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	//bosapiClient.DeleteLogging(b.srcBosPath)
-	return fmt.Errorf("not support!")
+	bosapiClient.DeleteLogging(b.srcBosPath)
+	return nil
 }
 
 // Put storage class
@@ -118,17 +246,40 @@ func (b *BosApiArgs) getBucketStorageClass(context *kingpin.ParseContext) error
 
 func (b *BosApiArgs) getObjectMeta(context *kingpin.ParseContext) error {
 	initBosapiClient()
-	bosapiClient.HeadObject(b.srcBosPath, b.srcBosKeyPath)
+	bosapiClient.HeadObject(b.srcBosPath, b.srcBosKeyPath, b.versionId)
+	return nil
+}
+
+// List multipart uploads
+func (b *BosApiArgs) listMultipartUploads(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.ListMultipartUploads(b.srcBosPath, b.prefix, b.keyMarker, b.uploadIdMark,
+		b.maxKeys)
+	return nil
+}
+
+// List parts
+func (b *BosApiArgs) listParts(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.ListParts(b.srcBosPath, b.srcBosKeyPath, b.uploadId, b.partMarker, b.maxKeys)
+	return nil
+}
+
+// Abort multipart upload
+func (b *BosApiArgs) abortMultipartUpload(context *kingpin.ParseContext) error {
+	initBosapiClient()
+	bosapiClient.AbortMultipartUpload(b.srcBosPath, b.srcBosKeyPath, b.uploadId)
 	return nil
 }
 
 // build parser for put acl
 func buildPutBucketAclParser(putBucketAclCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
 	putBucketAclCmd.Action(bosApiArgsValue.putBucketAcl)
-	//putBucketAclCmd.Flag(
-	//	"acl-config-file",
-	//	"path to acl file in json format.").
-	//	StringVar(&bosApiArgsValue.srcPath)
+	putBucketAclCmd.Flag(
+		"acl-config-file",
+		"path to acl file in json format, e.g. {\"Grants\": [{\"Grantee\": {\"ID\": \"id\"}, "+
+			"\"Permission\": \"READ\"}]}, grantee can be ID, EmailAddress or URI.").
+		StringVar(&bosApiArgsValue.srcPath)
 	putBucketAclCmd.Flag(
 		"bucket-name",
 		"bucket you want to put acl for.").
@@ -151,6 +302,258 @@ func buildGetBucketAclParser(getBucketAclCmd *kingpin.CmdClause, bosApiArgsValue
 		StringVar(&bosApiArgsValue.srcBosPath)
 }
 
+// build parser for put object acl
+func buildPutObjectAclParser(putObjectAclCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
+	putObjectAclCmd.Action(bosApiArgsValue.putObjectAcl)
+	putObjectAclCmd.Flag(
+		"acl-config-file",
+		"path to acl file in json format, the format is the same as put-bucket-acl.").
+		StringVar(&bosApiArgsValue.srcPath)
+	putObjectAclCmd.Flag(
+		"bucket-name",
+		"bucket of the object.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	putObjectAclCmd.Flag(
+		"object-name",
+		"object you want to put acl for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosKeyPath)
+	putObjectAclCmd.Flag(
+		"canned",
+		"set the canned acl of the given object, it can be: 'private', 'public-read', "+
+			"'public-read-write', 'authenticated-read', 'bucket-owner-read' or "+
+			"'bucket-owner-full-control'").
+		StringVar(&bosApiArgsValue.canned)
+}
+
+// build parser for get object acl
+func buildGetObjectAclParser(getObjectAclCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
+	getObjectAclCmd.Action(bosApiArgsValue.getObjectAcl)
+	getObjectAclCmd.Flag(
+		"bucket-name",
+		"bucket of the object.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	getObjectAclCmd.Flag(
+		"object-name",
+		"object you want to get acl for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosKeyPath)
+}
+
+// build parser for put bucket policy
+func buildPutBucketPolicyParser(putBucketPolicyCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	putBucketPolicyCmd.Action(bosApiArgsValue.putBucketPolicy)
+	putBucketPolicyCmd.Flag(
+		"policy-config-file",
+		"path to policy file in json format, use --template to get a template of the file.").
+		StringVar(&bosApiArgsValue.srcPath)
+	putBucketPolicyCmd.Flag(
+		"bucket-name",
+		"bucket you want to put policy for.").
+		StringVar(&bosApiArgsValue.srcBosPath)
+	putBucketPolicyCmd.Flag(
+		"template",
+		"print a policy template for the given bucket, it can be: 'read-only' (anyone can "+
+			"read) or 'cross-account' (another account can read and write).").
+		EnumVar(&bosApiArgsValue.policyTmpl, "read-only", "cross-account")
+}
+
+// build parser for get bucket policy
+func buildGetBucketPolicyParser(getBucketPolicyCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	getBucketPolicyCmd.Action(bosApiArgsValue.getBucketPolicy)
+	getBucketPolicyCmd.Flag(
+		"bucket-name",
+		"bucket you want to get policy for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for delete bucket policy
+func buildDelBucketPolicyParser(delBucketPolicyCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	delBucketPolicyCmd.Action(bosApiArgsValue.deleteBucketPolicy)
+	delBucketPolicyCmd.Flag(
+		"bucket-name",
+		"bucket you want to delete policy.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for put bucket cors
+func buildPutBucketCorsParser(putBucketCorsCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
+	putBucketCorsCmd.Action(bosApiArgsValue.putBucketCors)
+	putBucketCorsCmd.Flag(
+		"cors-config-file",
+		"path to CORS rule file in json or xml format.").
+		Required().
+		StringVar(&bosApiArgsValue.srcPath)
+	putBucketCorsCmd.Flag(
+		"bucket-name",
+		"bucket you want to put CORS rules for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for get bucket cors
+func buildGetBucketCorsParser(getBucketCorsCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
+	getBucketCorsCmd.Action(bosApiArgsValue.getBucketCors)
+	getBucketCorsCmd.Flag(
+		"bucket-name",
+		"bucket you want to get CORS rules for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for delete bucket cors
+func buildDelBucketCorsParser(delBucketCorsCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
+	delBucketCorsCmd.Action(bosApiArgsValue.deleteBucketCors)
+	delBucketCorsCmd.Flag(
+		"bucket-name",
+		"bucket you want to delete CORS rules.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for put bucket tagging
+func buildPutBucketTaggingParser(putBucketTaggingCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	putBucketTaggingCmd.Action(bosApiArgsValue.putBucketTagging)
+	putBucketTaggingCmd.Flag(
+		"bucket-name",
+		"bucket you want to put tags for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	putBucketTaggingCmd.Flag(
+		"tags",
+		"tags of bucket, e.g: --tags project=web,env=prod; all the tags of bucket are "+
+			"replaced.").
+		Required().
+		StringVar(&bosApiArgsValue.tags)
+}
+
+// build parser for get bucket tagging
+func buildGetBucketTaggingParser(getBucketTaggingCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	getBucketTaggingCmd.Action(bosApiArgsValue.getBucketTagging)
+	getBucketTaggingCmd.Flag(
+		"bucket-name",
+		"bucket you want to get tags for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for delete bucket tagging
+func buildDelBucketTaggingParser(delBucketTaggingCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	delBucketTaggingCmd.Action(bosApiArgsValue.deleteBucketTagging)
+	delBucketTaggingCmd.Flag(
+		"bucket-name",
+		"bucket you want to delete tags.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for put object tagging
+func buildPutObjectTaggingParser(putObjectTaggingCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	putObjectTaggingCmd.Action(bosApiArgsValue.putObjectTagging)
+	putObjectTaggingCmd.Flag(
+		"bucket-name",
+		"bucket of the object.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	putObjectTaggingCmd.Flag(
+		"object-name",
+		"object you want to put tags for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosKeyPath)
+	putObjectTaggingCmd.Flag(
+		"tags",
+		"tags of object, e.g: --tags project=web,env=prod; all the tags of object are "+
+			"replaced.").
+		Required().
+		StringVar(&bosApiArgsValue.tags)
+}
+
+// build parser for get object tagging
+func buildGetObjectTaggingParser(getObjectTaggingCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	getObjectTaggingCmd.Action(bosApiArgsValue.getObjectTagging)
+	getObjectTaggingCmd.Flag(
+		"bucket-name",
+		"bucket of the object.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	getObjectTaggingCmd.Flag(
+		"object-name",
+		"object you want to get tags for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosKeyPath)
+}
+
+// build parser for put bucket versioning
+func buildPutBucketVersioningParser(putBucketVersioningCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	putBucketVersioningCmd.Action(bosApiArgsValue.putBucketVersioning)
+	putBucketVersioningCmd.Flag(
+		"bucket-name",
+		"bucket you want to put versioning for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	putBucketVersioningCmd.Flag(
+		"status",
+		"versioning status, it can be: 'Enabled' or 'Suspended'; versioning can't be disabled "+
+			"once it is enabled.").
+		Required().
+		EnumVar(&bosApiArgsValue.status, "Enabled", "Suspended")
+}
+
+// build parser for get bucket versioning
+func buildGetBucketVersioningParser(getBucketVersioningCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	getBucketVersioningCmd.Action(bosApiArgsValue.getBucketVersioning)
+	getBucketVersioningCmd.Flag(
+		"bucket-name",
+		"bucket you want to get versioning for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
+// build parser for put bucket encryption
+func buildPutBucketEncryptionParser(putBucketEncryptionCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	putBucketEncryptionCmd.Action(bosApiArgsValue.putBucketEncryption)
+	putBucketEncryptionCmd.Flag(
+		"bucket-name",
+		"bucket you want to put default encryption for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	putBucketEncryptionCmd.Flag(
+		"sse",
+		"server-side encryption, it can be: 'AES256' or 'aws:kms'.").
+		Required().
+		EnumVar(&bosApiArgsValue.sse, "AES256", "aws:kms")
+	putBucketEncryptionCmd.Flag(
+		"sse-kms-key-id",
+		"KMS key id, only used with '--sse aws:kms'.").
+		StringVar(&bosApiArgsValue.sseKmsKeyId)
+}
+
+// build parser for get bucket encryption
+func buildGetBucketEncryptionParser(getBucketEncryptionCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	getBucketEncryptionCmd.Action(bosApiArgsValue.getBucketEncryption)
+	getBucketEncryptionCmd.Flag(
+		"bucket-name",
+		"bucket you want to get default encryption for.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+}
+
 // build parser for put lifecycle
 func buildPutLifecycleParser(putLifecycleCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
 	putLifecycleCmd.Action(bosApiArgsValue.putLifecycle)
@@ -164,7 +567,7 @@ func buildPutLifecycleParser(putLifecycleCmd *kingpin.CmdClause, bosApiArgsValue
 		StringVar(&bosApiArgsValue.srcBosPath)
 	putLifecycleCmd.Flag(
 		"template",
-		"generates a lifeycle template for the given bucket.").
+		"print a template of lifecycle configuration.").
 		BoolVar(&bosApiArgsValue.template)
 }
 
@@ -193,7 +596,8 @@ func putLoggingParser(putLoggingCmd *kingpin.CmdClause, bosApiArgsValue *BosApiA
 	putLoggingCmd.Action(bosApiArgsValue.putLogging)
 	putLoggingCmd.Flag(
 		"target-bucket",
-		"which bucket will the log put to.").
+		"which bucket will the log put to, it must exist.").
+		Required().
 		StringVar(&bosApiArgsValue.srcBosPath)
 	putLoggingCmd.Flag(
 		"target-prefix",
@@ -212,8 +616,12 @@ func getLoggingParser(getLoggingCmd *kingpin.CmdClause, bosApiArgsValue *BosApiA
 	getLoggingCmd.Flag(
 		"bucket-name",
 		"bucket you want to list logging for.").
-		Required().
 		StringVar(&bosApiArgsValue.srcBosPath)
+	getLoggingCmd.Flag(
+		"all",
+		"show whether logging is enabled of all buckets, exit with error when logging of any "+
+			"bucket is disabled.").
+		BoolVar(&bosApiArgsValue.all)
 }
 
 // build parser for delete logging
@@ -264,6 +672,88 @@ func getObjectMetaParser(getObjectMetaCmd *kingpin.CmdClause,
 	getObjectMetaCmd.Flag(
 		"object-name",
 		"object name you want to get").Required().StringVar(&bosApiArgsValue.srcBosKeyPath)
+	getObjectMetaCmd.Flag(
+		"version-id",
+		"version of the object, the current version is got when it is not set.").
+		StringVar(&bosApiArgsValue.versionId)
+}
+
+// build parser for list multipart uploads
+func listMultipartUploadsParser(listMultipartUploadsCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	listMultipartUploadsCmd.Action(bosApiArgsValue.listMultipartUploads)
+	listMultipartUploadsCmd.Flag(
+		"bucket-name",
+		"bucket you want to list multipart uploads of.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	listMultipartUploadsCmd.Flag(
+		"prefix",
+		"only list uploads of objects with this prefix.").
+		StringVar(&bosApiArgsValue.prefix)
+	listMultipartUploadsCmd.Flag(
+		"key-marker",
+		"list uploads after this object key.").
+		StringVar(&bosApiArgsValue.keyMarker)
+	listMultipartUploadsCmd.Flag(
+		"upload-id-marker",
+		"list uploads after this upload id, used together with --key-marker.").
+		StringVar(&bosApiArgsValue.uploadIdMark)
+	listMultipartUploadsCmd.Flag(
+		"max-uploads",
+		"the maximum number of uploads to list, max value is 1000.").
+		Default("1000").
+		IntVar(&bosApiArgsValue.maxKeys)
+}
+
+// build parser for list parts
+func listPartsParser(listPartsCmd *kingpin.CmdClause, bosApiArgsValue *BosApiArgs) {
+	listPartsCmd.Action(bosApiArgsValue.listParts)
+	listPartsCmd.Flag(
+		"bucket-name",
+		"bucket of the multipart upload.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	listPartsCmd.Flag(
+		"object-name",
+		"object of the multipart upload.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosKeyPath)
+	listPartsCmd.Flag(
+		"upload-id",
+		"id of the multipart upload.").
+		Required().
+		StringVar(&bosApiArgsValue.uploadId)
+	listPartsCmd.Flag(
+		"part-number-marker",
+		"list parts after this part number.").
+		Int64Var(&bosApiArgsValue.partMarker)
+	listPartsCmd.Flag(
+		"max-parts",
+		"the maximum number of parts to list, max value is 1000.").
+		Default("1000").
+		IntVar(&bosApiArgsValue.maxKeys)
+}
+
+// build parser for abort multipart upload
+func abortMultipartUploadParser(abortMultipartUploadCmd *kingpin.CmdClause,
+	bosApiArgsValue *BosApiArgs) {
+	abortMultipartUploadCmd.Action(bosApiArgsValue.abortMultipartUpload)
+	abortMultipartUploadCmd.Flag(
+		"bucket-name",
+		"bucket of the multipart upload.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosPath)
+	abortMultipartUploadCmd.Flag(
+		"object-name",
+		"object of the multipart upload.").
+		Required().
+		StringVar(&bosApiArgsValue.srcBosKeyPath)
+	abortMultipartUploadCmd.Flag(
+		"upload-id",
+		"id of the multipart upload.").
+		Required().
+		StringVar(&bosApiArgsValue.uploadId)
 }
 
 // BOS API argparser builder
@@ -276,23 +766,78 @@ func BuildBosApi(bosApi *kingpin.CmdClause) {
 	getBucketAclCmd := bosApi.Command("get-bucket-acl", "get bucket ACL.")
 	buildGetBucketAclParser(getBucketAclCmd, bosApiArgsValue)
 
-	//putLifecycleCmd := bosApi.Command("put-lifecycle", "put lifecycle.")
-	//buildPutLifecycleParser(putLifecycleCmd, bosApiArgsValue)
+	putObjectAclCmd := bosApi.Command("put-object-acl", "put object ACL.")
+	buildPutObjectAclParser(putObjectAclCmd, bosApiArgsValue)
+
+	getObjectAclCmd := bosApi.Command("get-object-acl", "get object ACL.")
+	buildGetObjectAclParser(getObjectAclCmd, bosApiArgsValue)
+
+	putBucketPolicyCmd := bosApi.Command("put-bucket-policy", "put bucket policy.")
+	buildPutBucketPolicyParser(putBucketPolicyCmd, bosApiArgsValue)
+
+	getBucketPolicyCmd := bosApi.Command("get-bucket-policy", "get bucket policy.")
+	buildGetBucketPolicyParser(getBucketPolicyCmd, bosApiArgsValue)
+
+	delBucketPolicyCmd := bosApi.Command("delete-bucket-policy", "delete bucket policy.")
+	buildDelBucketPolicyParser(delBucketPolicyCmd, bosApiArgsValue)
 
-	//getLifecycleCmd := bosApi.Command("get-lifecycle", "get lifecycle.")
-	//buildGetLifecycleParser(getLifecycleCmd, bosApiArgsValue)
+	putBucketCorsCmd := bosApi.Command("put-bucket-cors", "put bucket CORS rules.")
+	buildPutBucketCorsParser(putBucketCorsCmd, bosApiArgsValue)
 
-	//delLifecycleCmd := bosApi.Command("delete-lifecycle", "delete lifecycle.")
-	//buildDelLifecycleParser(delLifecycleCmd, bosApiArgsValue)
+	getBucketCorsCmd := bosApi.Command("get-bucket-cors", "get bucket CORS rules.")
+	buildGetBucketCorsParser(getBucketCorsCmd, bosApiArgsValue)
 
-	//putLoggingCmd := bosApi.Command("put-logging", "put logging.")
-	//putLoggingParser(putLoggingCmd, bosApiArgsValue)
+	delBucketCorsCmd := bosApi.Command("delete-bucket-cors", "delete bucket CORS rules.")
+	buildDelBucketCorsParser(delBucketCorsCmd, bosApiArgsValue)
 
-	//getLoggingCmd := bosApi.Command("get-logging", "get logging.")
-	//getLoggingParser(getLoggingCmd, bosApiArgsValue)
+	putBucketTaggingCmd := bosApi.Command("put-bucket-tagging", "put bucket tags.")
+	buildPutBucketTaggingParser(putBucketTaggingCmd, bosApiArgsValue)
 
-	//delLoggingCmd := bosApi.Command("delete-logging", "delete logging.")
-	//delLoggingParser(delLoggingCmd, bosApiArgsValue)
+	getBucketTaggingCmd := bosApi.Command("get-bucket-tagging", "get bucket tags.")
+	buildGetBucketTaggingParser(getBucketTaggingCmd, bosApiArgsValue)
+
+	delBucketTaggingCmd := bosApi.Command("delete-bucket-tagging", "delete bucket tags.")
+	buildDelBucketTaggingParser(delBucketTaggingCmd, bosApiArgsValue)
+
+	putObjectTaggingCmd := bosApi.Command("put-object-tagging", "put object tags.")
+	buildPutObjectTaggingParser(putObjectTaggingCmd, bosApiArgsValue)
+
+	getObjectTaggingCmd := bosApi.Command("get-object-tagging", "get object tags.")
+	buildGetObjectTaggingParser(getObjectTaggingCmd, bosApiArgsValue)
+
+	putBucketVersioningCmd := bosApi.Command("put-bucket-versioning",
+		"put bucket versioning status.")
+	buildPutBucketVersioningParser(putBucketVersioningCmd, bosApiArgsValue)
+
+	getBucketVersioningCmd := bosApi.Command("get-bucket-versioning",
+		"get bucket versioning status.")
+	buildGetBucketVersioningParser(getBucketVersioningCmd, bosApiArgsValue)
+
+	putBucketEncryptionCmd := bosApi.Command("put-bucket-encryption",
+		"put bucket default encryption.")
+	buildPutBucketEncryptionParser(putBucketEncryptionCmd, bosApiArgsValue)
+
+	getBucketEncryptionCmd := bosApi.Command("get-bucket-encryption",
+		"get bucket default encryption.")
+	buildGetBucketEncryptionParser(getBucketEncryptionCmd, bosApiArgsValue)
+
+	putLifecycleCmd := bosApi.Command("put-lifecycle", "put lifecycle.")
+	buildPutLifecycleParser(putLifecycleCmd, bosApiArgsValue)
+
+	getLifecycleCmd := bosApi.Command("get-lifecycle", "get lifecycle.")
+	buildGetLifecycleParser(getLifecycleCmd, bosApiArgsValue)
+
+	delLifecycleCmd := bosApi.Command("delete-lifecycle", "delete lifecycle.")
+	buildDelLifecycleParser(delLifecycleCmd, bosApiArgsValue)
+
+	putLoggingCmd := bosApi.Command("put-logging", "put logging.")
+	putLoggingParser(putLoggingCmd, bosApiArgsValue)
+
+	getLoggingCmd := bosApi.Command("get-logging", "get logging.")
+	getLoggingParser(getLoggingCmd, bosApiArgsValue)
+
+	delLoggingCmd := bosApi.Command("delete-logging", "delete logging.")
+	delLoggingParser(delLoggingCmd, bosApiArgsValue)
 
 	//putBucketStorageClassCmd := bosApi.Command("put-bucket-storage-class",
 	//	"storage class configuration, should be STANDARD or STANDARD_IA or COLD.")
@@ -305,4 +850,15 @@ func BuildBosApi(bosApi *kingpin.CmdClause) {
 	getObjectMetaCmd := bosApi.Command("get-object-meta", "get object meta")
 	getObjectMetaParser(getObjectMetaCmd, bosApiArgsValue)
 
+	listMultipartUploadsCmd := bosApi.Command("list-multipart-uploads",
+		"list in-progress multipart uploads.")
+	listMultipartUploadsParser(listMultipartUploadsCmd, bosApiArgsValue)
+
+	listPartsCmd := bosApi.Command("list-parts", "list uploaded parts of a multipart upload.")
+	listPartsParser(listPartsCmd, bosApiArgsValue)
+
+	abortMultipartUploadCmd := bosApi.Command("abort-multipart-upload",
+		"abort a multipart upload.")
+	abortMultipartUploadParser(abortMultipartUploadCmd, bosApiArgsValue)
+
 }
@@ -14,6 +14,7 @@ package argparser
 
 import (
 	"github.com/alecthomas/kingpin"
+	"github.com/alecthomas/units"
 )
 
 import (
@@ -43,17 +44,43 @@ type BosArgs struct {
 	syncType      string
 	region        string
 	downLoadTmp   string
+	olderThan     string
+	acl           string
+	tags          string
+	sse           string
+	sseKmsKeyId   string
+	sseCKeyFile   string
+	sseCSrcKey    string
+	cseKeyFile    string
+	compress      string
+	versionId     string
 	exclude       []string
 	include       []string
 	excludeTime   []string
 	includeTime   []string
+	excludeFrom   []string
+	includeFrom   []string
+	regexExclude  []string
+	regexInclude  []string
+	tagFilter     []string
 	excludeDelete []string
+	resumeIds     []string
 	expires       int
 	concurrency   int
+	maxRequests   int
+	maxMemory     units.Base2Bytes
+	upload        multipartArgs
+	download      multipartArgs
+	copy          multipartArgs
+	autoTune      bool
 	all           bool
 	recursive     bool
 	summerize     bool
+	versions      bool
 	restart       bool
+	resumeServer  bool
+	noIgnore      bool
+	expired       bool
 	force         bool
 	yes           bool
 	dryrun        bool
@@ -62,6 +89,46 @@ type BosArgs struct {
 	disableBar    bool
 }
 
+// arguments of multipart upload, download or copy
+type multipartArgs struct {
+	threshold units.Base2Bytes
+	partSize  units.Base2Bytes
+	threadNum int
+}
+
+func (m *multipartArgs) tuning() boscli.MultipartTuning {
+	return boscli.MultipartTuning{
+		Threshold: int64(m.threshold),
+		PartSize:  int64(m.partSize),
+		ThreadNum: int64(m.threadNum),
+	}
+}
+
+// get the arguments of multipart transfers
+func (b *BosArgs) transferTuning() *boscli.TransferTuning {
+	return &boscli.TransferTuning{
+		Upload:   b.upload.tuning(),
+		Download: b.download.tuning(),
+		Copy:     b.copy.tuning(),
+		AutoTune: b.autoTune,
+	}
+}
+
+// get the arguments of path filter and time filter
+func (b *BosArgs) filterArgs() *boscli.FilterArgs {
+	return &boscli.FilterArgs{
+		Exclude:      b.exclude,
+		Include:      b.include,
+		ExcludeTime:  b.excludeTime,
+		IncludeTime:  b.includeTime,
+		ExcludeFrom:  b.excludeFrom,
+		IncludeFrom:  b.includeFrom,
+		RegexExclude: b.regexExclude,
+		RegexInclude: b.regexInclude,
+		TagFilter:    b.tagFilter,
+	}
+}
+
 // Gen signed url
 func (b *BosArgs) genSignedUrl(context *kingpin.ParseContext) error {
 	initBoscliClient()
@@ -77,7 +144,11 @@ func (b *BosArgs) genSignedUrl(context *kingpin.ParseContext) error {
 // list buckets or objects
 func (b *BosArgs) bosList(context *kingpin.ParseContext) error {
 	initBoscliClient()
-	boscliClient.List(b.bosPath, b.all, b.recursive, b.summerize)
+	if b.versions {
+		boscliClient.ListVersions(b.bosPath, b.all, b.recursive, b.summerize, b.filterArgs())
+		return nil
+	}
+	boscliClient.List(b.bosPath, b.all, b.recursive, b.summerize, b.filterArgs())
 	return nil
 }
 
@@ -98,27 +169,215 @@ func (b *BosArgs) rmoveBucket(context *kingpin.ParseContext) error {
 // remove objects
 func (b *BosArgs) rmoveObject(context *kingpin.ParseContext) error {
 	initBoscliClient()
-	boscliClient.RemoveObject(b.bosPath, b.yes, b.recursive, b.quiet)
+	boscliClient.SetVersionId(b.versionId)
+	boscliClient.RemoveObject(b.bosPath, b.yes, b.recursive, b.quiet, b.dryrun, b.filterArgs())
 	return nil
 }
 
 // upload, download or copy objects
 func (b *BosArgs) bosCopy(context *kingpin.ParseContext) error {
 	initBoscliClient()
+	boscliClient.SetTransferBudget(b.maxRequests, int64(b.maxMemory))
+	boscliClient.SetTransferTuning(b.transferTuning())
+	boscliClient.SetResumeFromServer(b.resumeServer)
+	boscliClient.SetUploadAcl(b.acl)
+	boscliClient.SetUploadTags(b.tags)
+	boscliClient.SetServerSideEncryption(b.sse, b.sseKmsKeyId, b.sseCKeyFile,
+		b.sseCSrcKey)
+	boscliClient.SetClientEncryptionKey(b.cseKeyFile)
+	boscliClient.SetUploadCompress(b.compress)
+	boscliClient.SetUseIgnoreFile(!b.noIgnore)
+	boscliClient.SetVersionId(b.versionId)
 	boscliClient.Copy(b.srcPath, b.dstPath, b.storageClass, b.downLoadTmp, b.recursive, b.restart, b.quiet,
-		b.yes, b.disableBar)
+		b.yes, b.disableBar, b.concurrency, b.filterArgs())
 	return nil
 }
 
 // sync
 func (b *BosArgs) bosSync(context *kingpin.ParseContext) error {
 	initBoscliClient()
-	boscliClient.Sync(b.srcPath, b.dstPath, b.storageClass, b.downLoadTmp, b.syncType, b.exclude, b.include,
-		b.excludeTime, b.includeTime, b.excludeDelete, b.concurrency, b.del, b.dryrun, b.yes, b.quiet, true,
-		b.restart)
+	boscliClient.SetTransferBudget(b.maxRequests, int64(b.maxMemory))
+	boscliClient.SetTransferTuning(b.transferTuning())
+	boscliClient.SetResumeFromServer(b.resumeServer)
+	boscliClient.SetUploadAcl(b.acl)
+	boscliClient.SetUploadTags(b.tags)
+	boscliClient.SetServerSideEncryption(b.sse, b.sseKmsKeyId, b.sseCKeyFile,
+		b.sseCSrcKey)
+	boscliClient.SetClientEncryptionKey(b.cseKeyFile)
+	boscliClient.SetUploadCompress(b.compress)
+	boscliClient.SetUseIgnoreFile(!b.noIgnore)
+	boscliClient.Sync(b.srcPath, b.dstPath, b.storageClass, b.downLoadTmp, b.syncType, b.filterArgs(),
+		b.excludeDelete, b.concurrency, b.del, b.dryrun, b.yes, b.quiet, true, b.restart)
+	return nil
+}
+
+// list breakpoint records
+func (b *BosArgs) resumeList(context *kingpin.ParseContext) error {
+	initBoscliClient()
+	boscliClient.ResumeList()
+	return nil
+}
+
+// show a breakpoint record
+func (b *BosArgs) resumeShow(context *kingpin.ParseContext) error {
+	initBoscliClient()
+	boscliClient.ResumeShow(b.resumeIds[0])
 	return nil
 }
 
+// purge breakpoint records
+func (b *BosArgs) resumePurge(context *kingpin.ParseContext) error {
+	initBoscliClient()
+	boscliClient.ResumePurge(b.resumeIds, b.expired, b.all, b.yes)
+	return nil
+}
+
+// abort old incomplete multipart uploads
+func (b *BosArgs) cleanupUploads(context *kingpin.ParseContext) error {
+	initBoscliClient()
+	boscliClient.CleanupUploads(b.bosPath, b.olderThan, b.yes, b.dryrun, b.quiet)
+	return nil
+}
+
+// remove the latest delete markers
+func (b *BosArgs) undelete(context *kingpin.ParseContext) error {
+	initBoscliClient()
+	boscliClient.Undelete(b.bosPath, b.recursive, b.yes, b.dryrun, b.quiet)
+	return nil
+}
+
+// build flags of the global budget shared by all object and part transfers
+func buildTransferBudgetFlags(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"max-requests",
+		"max number of in-flight requests of all objects and parts, default value 0 means no "+
+			"limit").
+		IntVar(&bosArgsValue.maxRequests)
+
+	cmd.Flag(
+		"max-memory",
+		"max bytes buffered by in-flight requests of all objects and parts, e.g. 512MiB, "+
+			"default value is multi upload max memory in config file").
+		Default("0").BytesVar(&bosArgsValue.maxMemory)
+}
+
+// build flags of threshold, part size and thread num of multipart upload, download and copy,
+// the values in config file are used when flags are not specified.
+func buildTransferTuningFlags(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	kinds := []struct {
+		name string
+		done string
+		args *multipartArgs
+	}{
+		{"upload", "uploaded", &bosArgsValue.upload},
+		{"download", "downloaded", &bosArgsValue.download},
+		{"copy", "copied", &bosArgsValue.copy},
+	}
+	for _, kind := range kinds {
+		cmd.Flag(
+			kind.name+"-threshold",
+			"objects larger than threshold are "+kind.done+" by multipart, e.g. 64MiB, "+
+				"default value is multi "+kind.name+" threshold in config file").
+			Default("0").BytesVar(&kind.args.threshold)
+
+		cmd.Flag(
+			kind.name+"-part-size",
+			"part size of multipart "+kind.name+", e.g. 16MiB, default value is multi "+
+				kind.name+" part size in config file").
+			Default("0").BytesVar(&kind.args.partSize)
+
+		cmd.Flag(
+			kind.name+"-thread-num",
+			"number of parts "+kind.done+" at the same time for each object, default value "+
+				"is multi "+kind.name+" thread num in config file").
+			IntVar(&kind.args.threadNum)
+	}
+
+	cmd.Flag(
+		"auto-tune",
+		"measure throughput of parts and adjust part size of new multipart transfers and "+
+			"number of parts transferred at the same time, decisions are logged with --debug").
+		BoolVar(&bosArgsValue.autoTune)
+}
+
+// build flags of path filter and time filter, which have the same
+// semantics in ls, cp, rm and sync.
+func buildFilterFlags(cmd *kingpin.CmdClause, bosArgsValue *BosArgs, opName string) {
+	cmd.Flag(
+		"exclude",
+		"multiple patterns to filter file when "+opName+"; the value should be quoted if it "+
+			"contains wildcard(*). e.g: \n"+
+			"--exclude './.svn/*'; \n"+
+			"--exclude ./path/to/file; \n"+
+			"--exclude '*/file'; \n"+
+			"--exclude '*.jpg';\n "+
+			"--exclude 'bos:/bucket/path/*'\n"+
+			"--exclude './**/test/*.jpg'\n"+
+			"--exclude './[!a-z]*.jpg'\n"+
+			"*NOTE:* In order to exclude an entire folder, the pattern must end with wildcard! "+
+			"Such as --exclude 'dir/*', or use 'dir/' in the file of --exclude-from. '**/' "+
+			"matches zero or more directories, and '[:digit:]' like POSIX character classes "+
+			"can be used in '[]'.").
+		StringsVar(&bosArgsValue.exclude)
+
+	cmd.Flag(
+		"include",
+		"multiple patterns to specify the files that needed to "+opName+"; the value should be "+
+			"quoted if it contains wildcard(*). e.g:\n "+
+			"--include './.svn/*';\n"+
+			"--include ./path/to/file;\n"+
+			"--include '*/file';\n"+
+			"--include '*.jpg';\n"+
+			"--include 'bos:/bucket/path/*'\n").
+		StringsVar(&bosArgsValue.include)
+
+	cmd.Flag(
+		"exclude-time",
+		"multiple time ranges 'start,end' to filter file by last modified time when "+opName+
+			"; start and end can be unix timestamp or UTC time, and can be empty. e.g:\n"+
+			"--exclude-time 1514736000,1546272000;\n"+
+			"--exclude-time 2018-01-01T00:00:00Z,;\n").
+		StringsVar(&bosArgsValue.excludeTime)
+
+	cmd.Flag(
+		"include-time",
+		"multiple time ranges 'start,end' to specify the files that needed to "+opName+
+			" by last modified time; start and end can be unix timestamp or UTC time, and can "+
+			"be empty. e.g:\n"+
+			"--include-time 1514736000,1546272000;\n"+
+			"--include-time ,2018-01-01T00:00:00Z;\n").
+		StringsVar(&bosArgsValue.includeTime)
+
+	cmd.Flag(
+		"exclude-from",
+		"read exclude patterns from files, the patterns have gitignore semantics and are "+
+			"relative to the source path: '!' negates a pattern, a pattern contains '/' is "+
+			"anchored, and a pattern ends with '/' only matches directories. When the source is "+
+			"a local directory, the files ignored by "+boscli.IGNORE_FILE_NAME+" in each "+
+			"directory are excluded too.").
+		StringsVar(&bosArgsValue.excludeFrom)
+
+	cmd.Flag(
+		"include-from",
+		"read include patterns from files, the patterns have the same semantics as "+
+			"--exclude-from, only the matched files are kept.").
+		StringsVar(&bosArgsValue.includeFrom)
+
+	cmd.Flag(
+		"regex-exclude",
+		"multiple regular expressions to filter file when "+opName+", the full path of file is "+
+			"matched (bos:/ prefix is removed for BOS path). e.g:\n"+
+			"--regex-exclude '\\.(log|tmp)$';\n").
+		StringsVar(&bosArgsValue.regexExclude)
+
+	cmd.Flag(
+		"regex-include",
+		"multiple regular expressions to specify the files that needed to "+opName+
+			", the full path of file is matched (bos:/ prefix is removed for BOS path). e.g:\n"+
+			"--regex-include '/20[0-9]{2}/.*\\.jpg$';\n").
+		StringsVar(&bosArgsValue.regexInclude)
+}
+
 // build parser for generate signed url
 func buildGenParser(genCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 	bosArgsValue.expires = EXPIRES_VAL_FOR_NOT_SET
@@ -140,7 +399,8 @@ func buildLsParser(lsCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 	lsCmd.Action(bosArgsValue.bosList)
 	lsCmd.Arg(
 		"BOS_PATH",
-		"BOS path start with \"bos:/\". only 1000 objects would be listed "+
+		"BOS path start with \"bos:/\", \"s3://\" or the name of a configured remote, "+
+			"e.g. \"remote:bucket/key\". only 1000 objects would be listed "+
 			"if there are more objects in a bucket. ").
 		Default("bos:/").StringVar(&bosArgsValue.bosPath)
 
@@ -158,6 +418,16 @@ func buildLsParser(lsCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 		"summerize",
 		"show summerization").
 		Short('s').BoolVar(&bosArgsValue.summerize)
+
+	lsCmd.Flag(
+		"versions",
+		"list all versions and delete markers of objects, the current version is marked "+
+			"with (latest).").
+		BoolVar(&bosArgsValue.versions)
+
+	buildFilterFlags(lsCmd, bosArgsValue, "list")
+
+	buildTagFilterFlag(lsCmd, bosArgsValue, "list")
 }
 
 // build parser for make bucket
@@ -203,7 +473,7 @@ func buildRmParser(rmCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 	rmCmd.Action(bosArgsValue.rmoveObject)
 	rmCmd.Arg(
 		"BOS_PATH",
-		"BOS path start with \"bos:/\"").
+		"BOS path start with \"bos:/\", \"s3://\" or \"remote:\"").
 		Required().StringVar(&bosArgsValue.bosPath)
 	rmCmd.Flag(
 		"recursive",
@@ -217,6 +487,19 @@ func buildRmParser(rmCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 		"quiet",
 		"do not display the operations performed from the specified command").
 		BoolVar(&bosArgsValue.quiet)
+	rmCmd.Flag(
+		"dryrun",
+		"list what will be deleted, but do not delete them").
+		BoolVar(&bosArgsValue.dryrun)
+	rmCmd.Flag(
+		"version-id",
+		"delete the version of a single object permanently, rather than adding a delete "+
+			"marker.").
+		StringVar(&bosArgsValue.versionId)
+
+	buildFilterFlags(rmCmd, bosArgsValue, "delete")
+
+	buildTagFilterFlag(rmCmd, bosArgsValue, "delete")
 }
 
 // build parser for copy
@@ -225,13 +508,14 @@ func buildCopyParser(cpCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 	cpCmd.Action(bosArgsValue.bosCopy)
 	cpCmd.Arg(
 		"SRC",
-		"source path, could be either local or BOS path. When source path, "+
-			"could be either local or BOS path.").
+		"source path, could be either local or BOS path. BOS path start with \"bos:/\", "+
+			"\"s3://\" or \"remote:\", where remote is configured in config file.").
 		Required().StringVar(&bosArgsValue.srcPath)
 
 	cpCmd.Arg(
 		"DST",
-		"destination path, could be either local or BOS path. ").
+		"destination path, could be either local or BOS path, the remote of destination "+
+			"could be different with source.").
 		Required().StringVar(&bosArgsValue.dstPath)
 
 	cpCmd.Flag(
@@ -244,6 +528,30 @@ func buildCopyParser(cpCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 		"restart upload object.").
 		BoolVar(&bosArgsValue.restart)
 
+	cpCmd.Flag(
+		"resume-from-server",
+		"resume the in-progress multipart upload of the same object on server when there is "+
+			"no breakpoint record; it is ignored with --restart, and for the uploads with acl, "+
+			"tags, server-side encryption or metadata, which can't be checked on server").
+		BoolVar(&bosArgsValue.resumeServer)
+
+	buildUploadAclFlag(cpCmd, bosArgsValue)
+
+	buildUploadTagsFlag(cpCmd, bosArgsValue)
+
+	buildSseFlags(cpCmd, bosArgsValue)
+
+	buildClientEncryptionFlag(cpCmd, bosArgsValue)
+	buildIgnoreFileFlag(cpCmd, bosArgsValue)
+
+	buildCompressFlag(cpCmd, bosArgsValue)
+
+	cpCmd.Flag(
+		"version-id",
+		"version of the source object to download or copy, the source must be a single "+
+			"object on BOS; an old version can be copied to the object itself to restore it.").
+		StringVar(&bosArgsValue.versionId)
+
 	cpCmd.Flag(
 		"storage-class",
 		"storage class configuration, should be STANDARD or STANDARD_IA or COLD").
@@ -251,7 +559,8 @@ func buildCopyParser(cpCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 
 	cpCmd.Flag(
 		"download-tmp-path",
-		"the path of temporary folder that stores temporary files for breakpoint downloading").
+		"the path of temporary folder that stores temporary files for breakpoint downloading"+
+			" and copying between remotes, and transformed files to be uploaded").
 		StringVar(&bosArgsValue.downLoadTmp)
 
 	cpCmd.Flag(
@@ -268,6 +577,18 @@ func buildCopyParser(cpCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 		"disable-bar",
 		"not display progress bar").
 		BoolVar(&bosArgsValue.disableBar)
+
+	cpCmd.Flag(
+		"concurrency",
+		"max number of objects copied at the same time when copy a directory with -r, default "+
+			"value is multi upload number").
+		IntVar(&bosArgsValue.concurrency)
+
+	buildTransferBudgetFlags(cpCmd, bosArgsValue)
+
+	buildTransferTuningFlags(cpCmd, bosArgsValue)
+
+	buildFilterFlags(cpCmd, bosArgsValue, "copy")
 }
 
 // build parser for sync
@@ -284,29 +605,9 @@ func buildSyncParser(syncCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 		"destination path, should be BOS path or local path.").
 		Required().StringVar(&bosArgsValue.dstPath)
 
-	syncCmd.Flag(
-		"exclude",
-		"multiple patterns to filter file when sync; the value should be quoted if it contains "+
-			"wildcard(*). e.g: \n"+
-			"--exclude './.svn/*'; \n"+
-			"--exclude ./path/to/file; \n"+
-			"--exclude '*/file'; \n"+
-			"--exclude '*.jpg';\n "+
-			"--exclude 'bos:/bucket/path/*'\n"+
-			"*NOTE:* In order to exclude an entire folder, the pattern must end with wildcard! "+
-			"Such as --exclude 'dir/*'.").
-		StringsVar(&bosArgsValue.exclude)
+	buildFilterFlags(syncCmd, bosArgsValue, "sync")
 
-	syncCmd.Flag(
-		"include",
-		"multiple patterns to specify the files that needed to synchronized; the value should be "+
-			"quoted if it contains. e.g:\n "+
-			"--include './.svn/*';\n"+
-			"--include ./path/to/file;\n"+
-			"--include '*/file';\n"+
-			"--include '*.jpg';\n"+
-			"--include 'bos:/bucket/path/*'\n").
-		StringsVar(&bosArgsValue.include)
+	buildTagFilterFlag(syncCmd, bosArgsValue, "sync")
 
 	syncCmd.Flag(
 		"delete",
@@ -365,7 +666,8 @@ func buildSyncParser(syncCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 
 	syncCmd.Flag(
 		"download-tmp-path",
-		"the path of temporary folder that stores temporary files for breakpoint downloading").
+		"the path of temporary folder that stores temporary files for breakpoint downloading"+
+			" and copying between remotes, and transformed files to be uploaded").
 		StringVar(&bosArgsValue.downLoadTmp)
 
 	syncCmd.Flag(
@@ -373,10 +675,200 @@ func buildSyncParser(syncCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
 		"max concurrency for sync, default value is multi upload number").
 		IntVar(&bosArgsValue.concurrency)
 
+	buildTransferBudgetFlags(syncCmd, bosArgsValue)
+
+	buildTransferTuningFlags(syncCmd, bosArgsValue)
+
 	syncCmd.Flag(
 		"restart",
 		"don't transfer from breakpoint.").
 		BoolVar(&bosArgsValue.restart)
+
+	syncCmd.Flag(
+		"resume-from-server",
+		"resume the in-progress multipart upload of the same object on server when there is "+
+			"no breakpoint record; it is ignored with --restart, and for the uploads with acl, "+
+			"tags, server-side encryption or metadata, which can't be checked on server").
+		BoolVar(&bosArgsValue.resumeServer)
+
+	buildUploadAclFlag(syncCmd, bosArgsValue)
+
+	buildUploadTagsFlag(syncCmd, bosArgsValue)
+
+	buildSseFlags(syncCmd, bosArgsValue)
+
+	buildClientEncryptionFlag(syncCmd, bosArgsValue)
+	buildIgnoreFileFlag(syncCmd, bosArgsValue)
+
+	buildCompressFlag(syncCmd, bosArgsValue)
+}
+
+// build flag of the canned ACL of objects created by cp and sync
+func buildUploadAclFlag(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"acl",
+		"canned ACL of uploaded or copied objects, it can be: 'private', 'public-read', "+
+			"'public-read-write', 'authenticated-read', 'bucket-owner-read' or "+
+			"'bucket-owner-full-control'").
+		StringVar(&bosArgsValue.acl)
+}
+
+// build flag of the tags of objects created by cp and sync
+func buildUploadTagsFlag(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"tags",
+		"tags of uploaded or copied objects, e.g: --tags project=web,env=prod; the tags of "+
+			"source object are replaced when copy objects in BOS").
+		StringVar(&bosArgsValue.tags)
+}
+
+// build flags of the server-side encryption of cp and sync
+func buildSseFlags(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"sse",
+		"server-side encryption of uploaded or copied objects, it can be: 'AES256' or "+
+			"'aws:kms'").
+		EnumVar(&bosArgsValue.sse, "AES256", "aws:kms")
+	cmd.Flag(
+		"sse-kms-key-id",
+		"KMS key id, only used with '--sse aws:kms'").
+		StringVar(&bosArgsValue.sseKmsKeyId)
+	cmd.Flag(
+		"sse-c-key-file",
+		"file of the SSE-C key, which contains 32 bytes key or the key encoded by base64; "+
+			"the key is used to write objects and read the objects uploaded or downloaded, "+
+			"and can't be used with '--sse'").
+		StringVar(&bosArgsValue.sseCKeyFile)
+	cmd.Flag(
+		"sse-c-copy-source-key-file",
+		"file of the SSE-C key of source objects when copy objects in BOS, the format is "+
+			"the same as '--sse-c-key-file'").
+		StringVar(&bosArgsValue.sseCSrcKey)
+}
+
+// build flag of the client-side encryption of cp and sync
+func buildClientEncryptionFlag(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"client-encryption-key-file",
+		"file of the master key of client-side encryption, which contains 32 bytes key or the "+
+			"key encoded by base64; files are encrypted before upload and objects encrypted by "+
+			"client are decrypted after download, the same key must be used for both").
+		StringVar(&bosArgsValue.cseKeyFile)
+}
+
+// build flag of the ignore file of local directories uploaded by cp and sync
+func buildIgnoreFileFlag(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"no-ignore-file",
+		"don't use "+boscli.IGNORE_FILE_NAME+" when upload a local directory, then all files "+
+			"including "+boscli.IGNORE_FILE_NAME+" itself are uploaded").
+		BoolVar(&bosArgsValue.noIgnore)
+}
+
+// build flag of the compression of files uploaded by cp and sync
+func buildCompressFlag(cmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cmd.Flag(
+		"compress",
+		"compress files before upload, it can be: 'gzip' or 'zstd'; the original size and last "+
+			"modified time are kept in metadata, and objects are decompressed after download").
+		EnumVar(&bosArgsValue.compress, "gzip", "zstd")
+}
+
+// build flag of tag filter, which only works for objects on BOS
+func buildTagFilterFlag(cmd *kingpin.CmdClause, bosArgsValue *BosArgs, opName string) {
+	cmd.Flag(
+		"tag-filter",
+		"multiple tags 'k1=v1,k2=v2' to specify the objects on BOS that needed to "+opName+
+			", only the objects having all the tags are kept; the tags are got by a request for "+
+			"every object kept by the other filters, so it is slow for many objects. e.g:\n"+
+			"--tag-filter env=prod --tag-filter retention=30d;\n").
+		StringsVar(&bosArgsValue.tagFilter)
+}
+
+// build parser for breakpoint records of multipart transfers
+func buildResumeParser(resumeCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	listCmd := resumeCmd.Command("list", "list breakpoint records with source, destination, "+
+		"progress and age.").Alias("ls")
+	listCmd.Action(bosArgsValue.resumeList)
+
+	showCmd := resumeCmd.Command("show", "show the detail of a breakpoint record.")
+	showCmd.Action(bosArgsValue.resumeShow)
+	showCmd.Arg(
+		"ID",
+		"id of breakpoint record, or a unique prefix of it.").
+		Required().StringsVar(&bosArgsValue.resumeIds)
+
+	purgeCmd := resumeCmd.Command("purge", "delete breakpoint records and temp files of "+
+		"downloads, and abort the multipart uploads on server.")
+	purgeCmd.Action(bosArgsValue.resumePurge)
+	purgeCmd.Arg(
+		"ID",
+		"ids of breakpoint records, or unique prefixes of them.").
+		StringsVar(&bosArgsValue.resumeIds)
+	purgeCmd.Flag(
+		"expired",
+		"purge all expired and corrupt records").
+		BoolVar(&bosArgsValue.expired)
+	purgeCmd.Flag(
+		"all",
+		"purge all records").
+		BoolVar(&bosArgsValue.all)
+	purgeCmd.Flag(
+		"yes",
+		"purge without any prompt").
+		Short('y').BoolVar(&bosArgsValue.yes)
+}
+
+// build parser for undelete
+func buildUndeleteParser(undeleteCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	undeleteCmd.Action(bosArgsValue.undelete)
+	undeleteCmd.Arg(
+		"BOS_PATH",
+		"BOS path start with \"bos:/\", \"s3://\" or \"remote:\", an object or a prefix "+
+			"with -r").
+		Required().StringVar(&bosArgsValue.bosPath)
+	undeleteCmd.Flag(
+		"recursive",
+		"undelete all objects under the prefix").
+		Short('r').BoolVar(&bosArgsValue.recursive)
+	undeleteCmd.Flag(
+		"yes",
+		"undelete objects without any prompt").
+		Short('y').BoolVar(&bosArgsValue.yes)
+	undeleteCmd.Flag(
+		"quiet",
+		"do not display the operations performed from the specified command").
+		BoolVar(&bosArgsValue.quiet)
+	undeleteCmd.Flag(
+		"dryrun",
+		"list what will be undeleted, but do not undelete them").
+		BoolVar(&bosArgsValue.dryrun)
+}
+
+// build parser for cleanup of incomplete multipart uploads
+func buildCleanupUploadsParser(cleanupCmd *kingpin.CmdClause, bosArgsValue *BosArgs) {
+	cleanupCmd.Action(bosArgsValue.cleanupUploads)
+	cleanupCmd.Arg(
+		"BOS_PATH",
+		"BOS path start with \"bos:/\", \"s3://\" or \"remote:\", only uploads of objects "+
+			"under the path are aborted").
+		Required().StringVar(&bosArgsValue.bosPath)
+	cleanupCmd.Flag(
+		"older-than",
+		"abort uploads initiated more than this long ago, e.g. 7d, 12h, 2w").
+		Default("7d").StringVar(&bosArgsValue.olderThan)
+	cleanupCmd.Flag(
+		"yes",
+		"abort uploads without any prompt").
+		Short('y').BoolVar(&bosArgsValue.yes)
+	cleanupCmd.Flag(
+		"quiet",
+		"do not display the operations performed from the specified command").
+		BoolVar(&bosArgsValue.quiet)
+	cleanupCmd.Flag(
+		"dryrun",
+		"list what will be aborted, but do not abort them").
+		BoolVar(&bosArgsValue.dryrun)
 }
 
 func BuildBosParser(bos *kingpin.CmdClause) {
@@ -403,4 +895,15 @@ func BuildBosParser(bos *kingpin.CmdClause) {
 	syncCmd := bos.Command("sync", "synchronize objects between local and BOS or between BOS and "+
 		"BOS.")
 	buildSyncParser(syncCmd, bosArgsValue)
+
+	resumeCmd := bos.Command("resume", "manage breakpoint records of multipart transfers.")
+	buildResumeParser(resumeCmd, bosArgsValue)
+
+	cleanupCmd := bos.Command("cleanup-uploads", "abort incomplete multipart uploads which "+
+		"were initiated long ago.")
+	buildCleanupUploadsParser(cleanupCmd, bosArgsValue)
+
+	undeleteCmd := bos.Command("undelete", "remove the latest delete markers of objects in a "+
+		"versioning bucket, so that the deleted objects are restored.")
+	buildUndeleteParser(undeleteCmd, bosArgsValue)
 }
@@ -51,6 +51,7 @@ const (
 	CODE_NO_SUCH_UPLOAD              = "NoSuchUpload"
 	CODE_INVALID_PART                = "InvalidPart"
 	CODE_INVALID_PART_ORDER          = "InvalidPartOrder"
+	CODE_PRECONDITION_FAILED         = "PreconditionFailed"
 )
 
 const (
@@ -0,0 +1,141 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the parsing and validation of ACL, and the ACL of uploaded objects.
+
+package boscli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var (
+	bucketCannedAcls = []string{
+		s3.BucketCannedACLPrivate,
+		s3.BucketCannedACLPublicRead,
+		s3.BucketCannedACLPublicReadWrite,
+	}
+	objectCannedAcls = []string{
+		s3.ObjectCannedACLPrivate,
+		s3.ObjectCannedACLPublicRead,
+		s3.ObjectCannedACLPublicReadWrite,
+		s3.ObjectCannedACLAuthenticatedRead,
+		s3.ObjectCannedACLBucketOwnerRead,
+		s3.ObjectCannedACLBucketOwnerFullControl,
+	}
+	aclPermissions = []string{
+		s3.PermissionFullControl,
+		s3.PermissionWrite,
+		s3.PermissionWriteAcp,
+		s3.PermissionRead,
+		s3.PermissionReadAcp,
+	}
+)
+
+// canned ACL of objects created by cp and sync, empty means the default ACL of bucket
+var uploadAcl string
+
+// SetUploadAcl - set the canned ACL of objects created by cp and sync
+func (b *BosCli) SetUploadAcl(acl string) {
+	if acl != "" && !isStringIn(acl, objectCannedAcls) {
+		bcecliAbnormalExistCodeErr(BOSCLI_PUT_ACL_CANNED_DONT_SUPPORT,
+			fmt.Errorf("unsupported canned ACL '%s'", acl))
+	}
+	uploadAcl = acl
+}
+
+func isStringIn(val string, list []string) bool {
+	for _, item := range list {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse ACL in json, the field names are the same as S3 ACL API, e.g.
+// {"Grants": [{"Grantee": {"ID": "..."}, "Permission": "READ"}]}
+// The type of grantee is inferred from ID, EmailAddress or URI when it is empty.
+func parseAclConfig(acl []byte) (*s3.AccessControlPolicy, error) {
+	policy := &s3.AccessControlPolicy{}
+	decoder := json.NewDecoder(bytes.NewReader(acl))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("invalid ACL: %s", err)
+	}
+	if len(policy.Grants) == 0 {
+		return nil, fmt.Errorf("ACL must have at least one grant")
+	}
+
+	for i, grant := range policy.Grants {
+		if grant == nil || grant.Grantee == nil {
+			return nil, fmt.Errorf("grant %d: Grantee is required", i+1)
+		}
+		if !isStringIn(aws.StringValue(grant.Permission), aclPermissions) {
+			return nil, fmt.Errorf("grant %d: Permission must be one of %v", i+1,
+				aclPermissions)
+		}
+		if err := validateGrantee(grant.Grantee); err != nil {
+			return nil, fmt.Errorf("grant %d: %s", i+1, err)
+		}
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ACL: %s", err)
+	}
+	return policy, nil
+}
+
+// validate grantee, the type is set when it is empty
+func validateGrantee(grantee *s3.Grantee) error {
+	// type of grantee => the field which identifies the grantee
+	fields := []struct {
+		granteeType string
+		name        string
+		value       *string
+	}{
+		{s3.TypeCanonicalUser, "ID", grantee.ID},
+		{s3.TypeAmazonCustomerByEmail, "EmailAddress", grantee.EmailAddress},
+		{s3.TypeGroup, "URI", grantee.URI},
+	}
+
+	if grantee.Type == nil {
+		for _, field := range fields {
+			if field.value == nil {
+				continue
+			} else if grantee.Type != nil {
+				return fmt.Errorf("Grantee can only have one of ID, EmailAddress and URI")
+			}
+			grantee.Type = aws.String(field.granteeType)
+		}
+		if grantee.Type == nil {
+			return fmt.Errorf("Grantee must have one of ID, EmailAddress and URI")
+		}
+	}
+	for _, field := range fields {
+		if field.granteeType != *grantee.Type {
+			continue
+		}
+		if aws.StringValue(field.value) == "" {
+			return fmt.Errorf("grantee of type %s must have %s", field.granteeType, field.name)
+		}
+		return nil
+	}
+	return fmt.Errorf("Type of grantee must be %s, %s or %s", s3.TypeCanonicalUser,
+		s3.TypeAmazonCustomerByEmail, s3.TypeGroup)
+}
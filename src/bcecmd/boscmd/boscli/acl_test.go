@@ -0,0 +1,85 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+import (
+	"utils/util"
+)
+
+type parseAclConfigType struct {
+	acl          string
+	granteeTypes []string
+	isSuc        bool
+}
+
+func TestParseAclConfig(t *testing.T) {
+	testCases := []parseAclConfigType{
+		//1 type of grantees are inferred
+		parseAclConfigType{
+			acl: `{"Grants": [
+				{"Grantee": {"ID": "user"}, "Permission": "FULL_CONTROL"},
+				{"Grantee": {"EmailAddress": "a@b.com"}, "Permission": "READ"},
+				{"Grantee": {"URI": "http://acs.amazonaws.com/groups/global/AllUsers"},
+				 "Permission": "READ_ACP"}]}`,
+			granteeTypes: []string{"CanonicalUser", "AmazonCustomerByEmail", "Group"},
+			isSuc:        true,
+		},
+		//2 type is given, owner is given
+		parseAclConfigType{
+			acl: `{"Owner": {"ID": "owner"}, "Grants": [
+				{"Grantee": {"Type": "CanonicalUser", "ID": "user"}, "Permission": "WRITE"}]}`,
+			granteeTypes: []string{"CanonicalUser"},
+			isSuc:        true,
+		},
+		//3 not json
+		parseAclConfigType{acl: "grants"},
+		//4 no grant
+		parseAclConfigType{acl: `{"Grants": []}`},
+		//5 invalid permission
+		parseAclConfigType{acl: `{"Grants": [{"Grantee": {"ID": "a"}, "Permission": "ALL"}]}`},
+		//6 no grantee
+		parseAclConfigType{acl: `{"Grants": [{"Permission": "READ"}]}`},
+		//7 grantee has both id and uri
+		parseAclConfigType{acl: `{"Grants": [{"Grantee": {"ID": "a", "URI": "b"},
+			"Permission": "READ"}]}`},
+		//8 type doesn't match
+		parseAclConfigType{acl: `{"Grants": [{"Grantee": {"Type": "Group", "ID": "a"},
+			"Permission": "READ"}]}`},
+		//9 unknown type
+		parseAclConfigType{acl: `{"Grants": [{"Grantee": {"Type": "User", "ID": "a"},
+			"Permission": "READ"}]}`},
+		//10 unknown field
+		parseAclConfigType{acl: `{"Grants": [{"Grantee": {"Id": "a", "Name": "b"},
+			"Permission": "READ"}]}`},
+	}
+	for i, tCase := range testCases {
+		policy, err := parseAclConfig([]byte(tCase.acl))
+		util.ExpectEqual("parse acl", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if err != nil {
+			continue
+		}
+		granteeTypes := []string{}
+		for _, grant := range policy.Grants {
+			granteeTypes = append(granteeTypes, aws.StringValue(grant.Grantee.Type))
+		}
+		util.ExpectEqual("parse acl", i+1, t.Errorf, tCase.granteeTypes, granteeTypes)
+	}
+}
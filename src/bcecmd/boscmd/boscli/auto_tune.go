@@ -0,0 +1,240 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the worker pool of parts of multipart transfer, and the auto tune of
+// part size and the number of in-flight parts from the observed throughput.
+
+package boscli
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/baidubce/bce-sdk-go/util/log"
+)
+
+const (
+	AUTO_TUNE_PART_SECONDS  = 4       // the expected time of transferring a part
+	AUTO_TUNE_MIN_PART_SIZE = 1 << 20 // 1M, part size is tuned in MB
+	AUTO_TUNE_MAX_WORKERS   = 64
+	AUTO_TUNE_WORKERS_TIMES = 4    // at most 4 times of thread num workers are used
+	AUTO_TUNE_GAIN          = 1.1  // the change of throughput less than 10% is ignored
+	AUTO_TUNE_EWMA_WEIGHT   = 0.25 // the weight of new sample of throughput
+)
+
+// the throughput of a single part stream of each kind of transfer, observed from all finished
+// parts, it is used to choose the part size of new transfers.
+var partThroughputs = map[string]*throughputStats{
+	"upload":   &throughputStats{},
+	"download": &throughputStats{},
+	"copy":     &throughputStats{},
+}
+
+// exponentially weighted moving average of throughput (bytes per second)
+type throughputStats struct {
+	lock       sync.Mutex
+	throughput float64
+}
+
+func (s *throughputStats) add(size int64, latency time.Duration) {
+	if size <= 0 || latency <= 0 {
+		return
+	}
+	sample := float64(size) / latency.Seconds()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.throughput == 0 {
+		s.throughput = sample
+	} else {
+		s.throughput += AUTO_TUNE_EWMA_WEIGHT * (sample - s.throughput)
+	}
+}
+
+// RETURN: the throughput, false when there is no sample
+func (s *throughputStats) get() (float64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.throughput, s.throughput > 0
+}
+
+// Choose the part size of a new transfer of fileSize bytes, so that a part is transferred in about
+// AUTO_TUNE_PART_SECONDS and the object has at most MAX_PARTS parts. partSize is used when auto
+// tune is disabled or there is no sample yet.
+func autoTunePartSize(kind string, fileSize, partSize int64, autoTune bool) int64 {
+	if !autoTune {
+		return partSize
+	}
+	throughput, ok := partThroughputs[kind].get()
+	if !ok {
+		return partSize
+	}
+	tuned := roundUpToMB(int64(throughput * AUTO_TUNE_PART_SECONDS))
+	minPartSize := roundUpToMB(minPartSizes[kind])
+	if tuned < minPartSize {
+		tuned = minPartSize
+	}
+	if tuned*MAX_PARTS < fileSize {
+		tuned = roundUpToMB((fileSize + MAX_PARTS - 1) / MAX_PARTS)
+	}
+	if tuned > MAX_PART_SIZE {
+		tuned = MAX_PART_SIZE
+	}
+	log.Debugf("auto tune %s: throughput of a part is %.2f MB/s, part size %d => %d", kind,
+		throughput/(1<<20), partSize, tuned)
+	return tuned
+}
+
+func roundUpToMB(size int64) int64 {
+	if size < AUTO_TUNE_MIN_PART_SIZE {
+		return AUTO_TUNE_MIN_PART_SIZE
+	}
+	return (size + AUTO_TUNE_MIN_PART_SIZE - 1) / AUTO_TUNE_MIN_PART_SIZE * AUTO_TUNE_MIN_PART_SIZE
+}
+
+// partWorkers is the pool of workers which transfer the parts of an object.
+// In auto tune mode, the number of workers is adjusted by the throughput of every window of
+// finished parts: keep moving in the same direction while throughput increases, turn back when
+// it decreases, and hold when it doesn't change much.
+type partWorkers struct {
+	pool     chan int64 // get a worker before transferring a part
+	kind     string
+	autoTune bool
+
+	lock           sync.Mutex
+	workers        int64
+	maxWorkers     int64
+	nextId         int64
+	removing       int64 // the number of workers to be removed when they are put back
+	growing        bool
+	windowStart    time.Time
+	windowBytes    int64
+	windowParts    int64
+	lastThroughput float64
+}
+
+func newPartWorkers(kind string, threadNum int64, autoTune bool) *partWorkers {
+	if threadNum < 1 {
+		threadNum = 1
+	}
+	maxWorkers := threadNum
+	if autoTune {
+		maxWorkers = threadNum * AUTO_TUNE_WORKERS_TIMES
+		if maxWorkers > AUTO_TUNE_MAX_WORKERS {
+			maxWorkers = AUTO_TUNE_MAX_WORKERS
+		}
+		if maxWorkers < threadNum {
+			maxWorkers = threadNum
+		}
+	}
+
+	w := &partWorkers{
+		pool:        make(chan int64, maxWorkers),
+		kind:        kind,
+		autoTune:    autoTune,
+		workers:     threadNum,
+		maxWorkers:  maxWorkers,
+		nextId:      threadNum,
+		growing:     true,
+		windowStart: time.Now(),
+	}
+	for i := int64(0); i < threadNum; i++ {
+		w.pool <- i
+	}
+	return w
+}
+
+// Put back the worker after transferring a part.
+// size is the size of transferred part which was started at start, 0 means the part is failed.
+func (w *partWorkers) put(id, size int64, start time.Time) {
+	if !w.autoTune {
+		w.pool <- id
+		return
+	}
+	partThroughputs[w.kind].add(size, time.Since(start))
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.windowBytes += size
+	w.windowParts++
+	if w.windowParts >= w.workers && w.windowParts > 1 {
+		w.adjust()
+	}
+
+	if w.removing > 0 {
+		w.removing--
+		return
+	}
+	// never block, as the number of workers never exceed the capacity of pool
+	w.pool <- id
+}
+
+// Adjust the number of workers at the end of a window, must be called with lock held
+func (w *partWorkers) adjust() {
+	elapsed := time.Since(w.windowStart).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	throughput := float64(w.windowBytes) / elapsed
+	oldWorkers := w.workers
+
+	if w.lastThroughput > 0 {
+		if throughput < w.lastThroughput/AUTO_TUNE_GAIN {
+			w.growing = !w.growing
+		} else if throughput < w.lastThroughput*AUTO_TUNE_GAIN {
+			log.Debugf("auto tune %s: throughput %.2f MB/s with %d workers, hold", w.kind,
+				throughput/(1<<20), w.workers)
+			w.resetWindow(throughput)
+			return
+		}
+	}
+
+	step := w.workers / 4
+	if step < 1 {
+		step = 1
+	}
+	if w.growing {
+		w.grow(step)
+	} else {
+		w.shrink(step)
+	}
+	log.Debugf("auto tune %s: throughput %.2f MB/s, workers %d => %d", w.kind,
+		throughput/(1<<20), oldWorkers, w.workers)
+	w.resetWindow(throughput)
+}
+
+func (w *partWorkers) grow(step int64) {
+	for ; step > 0 && w.workers < w.maxWorkers; step-- {
+		w.workers++
+		if w.removing > 0 {
+			w.removing--
+		} else {
+			w.pool <- w.nextId
+			w.nextId++
+		}
+	}
+}
+
+func (w *partWorkers) shrink(step int64) {
+	for ; step > 0 && w.workers > 1; step-- {
+		w.workers--
+		w.removing++
+	}
+}
+
+func (w *partWorkers) resetWindow(throughput float64) {
+	w.lastThroughput = throughput
+	w.windowStart = time.Now()
+	w.windowBytes = 0
+	w.windowParts = 0
+}
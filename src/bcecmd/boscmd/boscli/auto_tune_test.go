@@ -0,0 +1,176 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"utils/util"
+)
+
+type autoTunePartSizeType struct {
+	kind       string
+	throughput float64
+	fileSize   int64
+	partSize   int64
+	autoTune   bool
+	expected   int64
+}
+
+func TestAutoTunePartSize(t *testing.T) {
+	testCases := []autoTunePartSizeType{
+		//1 auto tune is disabled
+		autoTunePartSizeType{kind: "upload", throughput: 1 << 20, partSize: 8 << 20,
+			expected: 8 << 20},
+		//2 no sample
+		autoTunePartSizeType{kind: "upload", partSize: 8 << 20, autoTune: true,
+			expected: 8 << 20},
+		//3 tuned by throughput
+		autoTunePartSizeType{kind: "upload", throughput: 4 << 20, partSize: 8 << 20,
+			autoTune: true, expected: 16 << 20},
+		//4 parts of upload are at least MIN_PART_SIZE
+		autoTunePartSizeType{kind: "upload", throughput: 1000, partSize: 8 << 20,
+			autoTune: true, expected: MIN_PART_SIZE},
+		//5 parts of copy are at least MIN_PART_SIZE
+		autoTunePartSizeType{kind: "copy", throughput: 1000, partSize: 8 << 20,
+			autoTune: true, expected: MIN_PART_SIZE},
+		//6 parts of download are rounded up to MB
+		autoTunePartSizeType{kind: "download", throughput: 1000, partSize: 8 << 20,
+			autoTune: true, expected: AUTO_TUNE_MIN_PART_SIZE},
+		//7 not greater than max part size
+		autoTunePartSizeType{kind: "upload", throughput: 10 << 30, partSize: 8 << 20,
+			autoTune: true, expected: MAX_PART_SIZE},
+		//8 at most MAX_PARTS parts
+		autoTunePartSizeType{kind: "upload", throughput: 1 << 20, fileSize: 100 << 30,
+			partSize: 8 << 20, autoTune: true, expected: 11 << 20},
+	}
+
+	defer func() {
+		for kind := range partThroughputs {
+			partThroughputs[kind] = &throughputStats{}
+		}
+	}()
+	for i, tCase := range testCases {
+		partThroughputs[tCase.kind] = &throughputStats{throughput: tCase.throughput}
+		ret := autoTunePartSize(tCase.kind, tCase.fileSize, tCase.partSize, tCase.autoTune)
+		util.ExpectEqual("auto tune part size", i+1, t.Errorf, tCase.expected, ret)
+	}
+}
+
+func TestThroughputStats(t *testing.T) {
+	s := &throughputStats{}
+	_, ok := s.get()
+	util.ExpectEqual("throughput stats I", 1, t.Errorf, false, ok)
+
+	// failed part is ignored
+	s.add(0, time.Second)
+	_, ok = s.get()
+	util.ExpectEqual("throughput stats II", 1, t.Errorf, false, ok)
+
+	s.add(100, time.Second)
+	throughput, ok := s.get()
+	util.ExpectEqual("throughput stats III", 1, t.Errorf, true, ok)
+	util.ExpectEqual("throughput stats III", 2, t.Errorf, float64(100), throughput)
+
+	s.add(500, time.Second)
+	throughput, _ = s.get()
+	util.ExpectEqual("throughput stats IV", 1, t.Errorf, float64(200), throughput)
+}
+
+type newPartWorkersType struct {
+	threadNum  int64
+	autoTune   bool
+	workers    int64
+	maxWorkers int64
+}
+
+func TestNewPartWorkers(t *testing.T) {
+	testCases := []newPartWorkersType{
+		//1 auto tune is disabled
+		newPartWorkersType{threadNum: 10, workers: 10, maxWorkers: 10},
+		//2 auto tune
+		newPartWorkersType{threadNum: 10, autoTune: true, workers: 10, maxWorkers: 40},
+		//3 not greater than max workers
+		newPartWorkersType{threadNum: 20, autoTune: true, workers: 20,
+			maxWorkers: AUTO_TUNE_MAX_WORKERS},
+		//4 thread num is larger than max workers
+		newPartWorkersType{threadNum: 100, autoTune: true, workers: 100, maxWorkers: 100},
+		//5 at least one worker
+		newPartWorkersType{threadNum: 0, workers: 1, maxWorkers: 1},
+	}
+
+	for i, tCase := range testCases {
+		w := newPartWorkers("upload", tCase.threadNum, tCase.autoTune)
+		util.ExpectEqual("new part workers I", i+1, t.Errorf, tCase.workers, w.workers)
+		util.ExpectEqual("new part workers II", i+1, t.Errorf, tCase.maxWorkers, w.maxWorkers)
+		util.ExpectEqual("new part workers III", i+1, t.Errorf, int(tCase.workers), len(w.pool))
+		util.ExpectEqual("new part workers IV", i+1, t.Errorf, int(tCase.maxWorkers),
+			cap(w.pool))
+	}
+}
+
+func TestPartWorkersGrowAndShrink(t *testing.T) {
+	w := newPartWorkers("upload", 4, true)
+
+	w.grow(2)
+	util.ExpectEqual("part workers grow I", 1, t.Errorf, int64(6), w.workers)
+	util.ExpectEqual("part workers grow I", 2, t.Errorf, 6, len(w.pool))
+
+	// grow doesn't exceed max workers
+	w.grow(100)
+	util.ExpectEqual("part workers grow II", 1, t.Errorf, int64(16), w.workers)
+	util.ExpectEqual("part workers grow II", 2, t.Errorf, 16, len(w.pool))
+
+	// shrink keeps at least one worker, the removed workers are dropped when they are put back
+	w.shrink(100)
+	util.ExpectEqual("part workers shrink I", 1, t.Errorf, int64(1), w.workers)
+	util.ExpectEqual("part workers shrink I", 2, t.Errorf, int64(15), w.removing)
+
+	// grow cancels the pending removing first
+	w.grow(5)
+	util.ExpectEqual("part workers grow III", 1, t.Errorf, int64(6), w.workers)
+	util.ExpectEqual("part workers grow III", 2, t.Errorf, int64(10), w.removing)
+	util.ExpectEqual("part workers grow III", 3, t.Errorf, 16, len(w.pool))
+}
+
+func TestPartWorkersPut(t *testing.T) {
+	defer func() { partThroughputs["copy"] = &throughputStats{} }()
+
+	//1 auto tune is disabled, the worker is always put back
+	w := newPartWorkers("copy", 2, false)
+	id := <-w.pool
+	w.put(id, 0, time.Now())
+	util.ExpectEqual("part workers put", 1, t.Errorf, 2, len(w.pool))
+
+	//2 the worker is dropped when it is being removed
+	w = newPartWorkers("copy", 4, true)
+	w.shrink(1)
+	id = <-w.pool
+	w.put(id, 0, time.Now())
+	util.ExpectEqual("part workers put", 2, t.Errorf, 3, len(w.pool))
+	util.ExpectEqual("part workers put", 2, t.Errorf, int64(0), w.removing)
+
+	//3 the tokens in pool are the workers and the workers to be removed when all parts are
+	// finished
+	w = newPartWorkers("copy", 4, true)
+	for i := 0; i < 100; i++ {
+		id := <-w.pool
+		w.put(id, int64(1<<20*(i%7+1)), time.Now().Add(-time.Millisecond))
+	}
+	util.ExpectEqual("part workers put", 3, t.Errorf, int(w.workers+w.removing), len(w.pool))
+	_, ok := partThroughputs["copy"].get()
+	util.ExpectEqual("part workers put", 3, t.Errorf, true, ok)
+}
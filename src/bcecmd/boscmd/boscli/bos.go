@@ -48,12 +48,53 @@ func NewBosCli() *BosCli {
 		bcecliAbnormalExistErr(err)
 	}
 	boscliClient.handler = &cliHandler{}
+	boscliClient.remoteClients = make(map[string]bosClientInterface)
 	return boscliClient
 }
 
 type BosCli struct {
-	bosClient bosClientInterface
-	handler   handlerInterface
+	bosClient     bosClientInterface
+	handler       handlerInterface
+	remoteClients map[string]bosClientInterface // remote name => the client of remote
+}
+
+// Get the client of the remote which bosPath belongs to.
+// the client of a named remote is created when it is used for the first time.
+func (b *BosCli) getClientOfPath(bosPath string) (bosClientInterface, error) {
+	remoteName, _, _ := splitRemotePath(bosPath)
+	return b.getClientOfRemote(remoteName)
+}
+
+// Get the client of remote, the default client is returned when remoteName is empty.
+func (b *BosCli) getClientOfRemote(remoteName string) (bosClientInterface, error) {
+	if remoteName == "" {
+		return b.bosClient, nil
+	}
+	if client, ok := b.remoteClients[remoteName]; ok {
+		return client, nil
+	}
+	client, err := remoteClientInit(remoteName)
+	if err != nil {
+		return nil, err
+	}
+	if b.remoteClients == nil {
+		b.remoteClients = make(map[string]bosClientInterface)
+	}
+	b.remoteClients[remoteName] = client
+	return client, nil
+}
+
+// Get a BosCli which operates on the remote that bosPath belongs to
+func (b *BosCli) cliOfPath(bosPath string) *BosCli {
+	client, err := b.getClientOfPath(bosPath)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	return &BosCli{
+		bosClient:     client,
+		handler:       b.handler,
+		remoteClients: b.remoteClients,
+	}
 }
 
 type operateResult struct {
@@ -75,7 +116,7 @@ func (b *BosCli) GenSignedUrl(bosPath string, expires int, haveSetExpires bool)
 	}
 
 	// execute genSignedUrl
-	bosUrl := b.bosClient.BasicGeneratePresignedUrl(args.bucketName, args.objectKey, args.expires)
+	bosUrl := b.cliOfPath(bosPath).bosClient.BasicGeneratePresignedUrl(args.bucketName, args.objectKey, args.expires)
 	fmt.Println(bosUrl)
 }
 
@@ -111,17 +152,25 @@ func (b *BosCli) genSignedUrlPreProcess(bosPath string, expires int, haveSetExpi
 
 // List buckets or objects
 // param: must have BOS_PATH attribute.
-func (b *BosCli) List(bosPath string, all bool, recursive bool, summary bool) {
+func (b *BosCli) List(bosPath string, all bool, recursive bool, summary bool,
+	filterArgs *FilterArgs) {
+
 	retCode, err := checkBosPath(bosPath)
 	if err != nil {
 		bcecliAbnormalExistCodeErr(retCode, err)
 	}
 
+	filter, retCode, err := newFilterFromArgs(filterArgs, bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+
+	cli := b.cliOfPath(bosPath)
 	bucketName, objectKey := splitBosBucketKey(bosPath)
 	if bucketName == "" {
-		_, err = b.listBuckets(summary)
+		_, err = cli.listBuckets(summary)
 	} else {
-		err = b.listObjects(bucketName, objectKey, all, recursive, summary)
+		err = cli.listObjects(bucketName, objectKey, all, recursive, summary, filter)
 	}
 	if err != nil {
 		bcecliAbnormalExistErr(err)
@@ -151,14 +200,15 @@ func (b *BosCli) listBuckets(sum bool) (int, error) {
 }
 
 // implement list objects
-func (b *BosCli) listObjects(bucketName, objectKey string, all, recursive, summary bool) error {
+func (b *BosCli) listObjects(bucketName, objectKey string, all, recursive, summary bool,
+	filter *bosFilter) error {
 	var (
 		preNum     int64
 		objectNum  int64
 		objectSize int64
 	)
 
-	objectsList := NewObjectListIterator(b.bosClient, nil, bucketName, objectKey, "", all,
+	objectsList := NewObjectListIterator(b.bosClient, filter, bucketName, objectKey, "", all,
 		recursive, true, false, 1000)
 	for {
 		listResult, err := objectsList.next()
@@ -197,13 +247,14 @@ func (b *BosCli) listObjects(bucketName, objectKey string, all, recursive, summa
 // Make bucket
 func (b *BosCli) MakeBucket(bucketName, region string, quiet bool) {
 	// preprocessing
+	cli := b.cliOfPath(bucketName)
 	bucketName, retCode := b.makeBucketPreProcess(bucketName, region)
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCode(retCode)
 	}
 
 	// execute make bucket
-	location, err := b.bosClient.PutBucket(bucketName)
+	location, err := cli.bosClient.PutBucket(bucketName)
 	if err != nil {
 		bcecliAbnormalExistErr(err)
 	}
@@ -242,7 +293,7 @@ func (b *BosCli) RemoveBucket(bucketName string, force, yes, quiet bool) {
 	}
 
 	// execute remove bucket
-	retCode, err := b.removeBucketExecute(bucket, yes, force)
+	retCode, err := b.cliOfPath(bucketName).removeBucketExecute(bucket, yes, force)
 	if err != nil {
 		bcecliAbnormalExistCodeErr(retCode, err)
 	} else {
@@ -280,7 +331,7 @@ func (b *BosCli) removeBucketExecute(bucketName string, yes, force bool) (BosCli
 				"objects in it?", BOS_PATH_PREFIX, bucketName)
 		}
 		if confirmed {
-			_, err = b.handler.multiDeleteDir(b.bosClient, bucketName, "")
+			_, err = b.handler.multiDeleteDir(b.bosClient, nil, bucketName, "")
 			if err != nil {
 				return BOSCLI_EMPTY_CODE, err
 			}
@@ -313,6 +364,9 @@ type removeObjectArgs struct {
 	objectKey  string
 	bucketName string
 	isDir      bool
+	dryrun     bool
+	versionId  string
+	filter     *bosFilter
 }
 
 // rm: remove object from bucekt, must have bos_path
@@ -321,17 +375,34 @@ type removeObjectArgs struct {
 //   yes       : delete object without prompts.
 //   recursive : delete objects under subdirs.
 //   quit      : do not display the operations performed from the specified command
-func (b *BosCli) RemoveObject(bosPath string, yes, recursive, quiet bool) {
+//   dryrun    : only print the objects that would be deleted.
+//   filterArgs: the objects filtered out are not deleted.
+func (b *BosCli) RemoveObject(bosPath string, yes, recursive, quiet, dryrun bool,
+	filterArgs *FilterArgs) {
+
 	// preprocessing and check request
 	args, retCode := b.removeObjectPreProcess(bosPath, recursive)
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCode(retCode)
 	}
 
+	filter, retCode, err := newFilterFromArgs(filterArgs, bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	if retCode, err := bindVersionOfPath(bosPath, recursive); retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	args.filter = filter
+	args.dryrun = dryrun
+	args.versionId = targetVersion.versionId
+
 	// execute remove
-	removed, err := b.removeObjectExecute(args, yes)
+	removed, err := b.cliOfPath(bosPath).removeObjectExecute(args, yes)
 
-	if !(removed == 1 && IsConcurrentOperation) {
+	if dryrun {
+		printIfNotQuiet("[%d] objects would be removed on remote.\n", removed)
+	} else if !(removed == 1 && IsConcurrentOperation) {
 		printIfNotQuiet("[%d] objects removed on remote.\n", removed)
 	}
 	if err != nil {
@@ -376,6 +447,11 @@ func (b *BosCli) removeObjectExecute(args *removeObjectArgs, yes bool) (int, err
 		err     error
 	)
 
+	// only print the objects would be deleted
+	if args.dryrun {
+		return b.removeObjectDryrun(args)
+	}
+
 	// recursive delete
 	if args.isDir {
 		if !yes {
@@ -383,7 +459,8 @@ func (b *BosCli) removeObjectExecute(args *removeObjectArgs, yes bool) (int, err
 				BOS_PATH_PREFIX, args.bucketName, args.objectKey)
 		}
 		if yes {
-			deleted, err = b.handler.multiDeleteDir(b.bosClient, args.bucketName, args.objectKey)
+			deleted, err = b.handler.multiDeleteDir(b.bosClient, args.filter, args.bucketName,
+				args.objectKey)
 		}
 		goto END
 	}
@@ -393,7 +470,15 @@ func (b *BosCli) removeObjectExecute(args *removeObjectArgs, yes bool) (int, err
 		yes = util.PromptConfirm("Do you really want to DELETE object %s%s/%s?", BOS_PATH_PREFIX,
 			args.bucketName, args.objectKey)
 	}
-	if yes {
+	if yes && args.versionId != "" {
+		// the version is deleted permanently
+		if err = b.bosClient.DeleteObjectVersion(args.bucketName, args.objectKey,
+			args.versionId); err == nil {
+			deleted = 1
+			printIfNotQuiet("Delete object: %s%s/%s %s\n", BOS_PATH_PREFIX, args.bucketName,
+				args.objectKey, args.versionId)
+		}
+	} else if yes {
 		if err = b.handler.utilDeleteObject(b.bosClient, args.bucketName,
 			args.objectKey); err == nil {
 			deleted = 1
@@ -404,11 +489,41 @@ END:
 	return deleted, err
 }
 
+// print the objects that would be deleted by rm, without deleting them
+func (b *BosCli) removeObjectDryrun(args *removeObjectArgs) (int, error) {
+	var (
+		deleted    int
+		listResult *listFileResult
+		err        error
+	)
+
+	objectList := NewObjectListIterator(b.bosClient, args.filter, args.bucketName,
+		args.objectKey, "", true, true, args.isDir, true, MAX_DELETE_OBJECTS_EACH_REQUEST)
+	for {
+		listResult, err = objectList.next()
+		if err != nil {
+			return deleted, err
+		}
+		if listResult.ended {
+			break
+		}
+		if listResult.isDir {
+			continue
+		}
+		printIfNotQuiet("(dryrun) Delete object: %s%s/%s\n", BOS_PATH_PREFIX, args.bucketName,
+			listResult.file.path)
+		deleted++
+	}
+	return deleted, nil
+}
+
 // cp : upload, download or copy
 // param args: Parsed args, must have SRC, DST, force, no_override
 // exception: Both SRC and DST are local path or stream
+// filterArgs only work when copy a directory with --recursive
+// concurrency is the max number of objects copied at the same time when copy a directory
 func (b *BosCli) Copy(srcPath, dstPath, storageClass, downLoadTmp string, recursive, restart, quiet,
-	yes, disableBar bool) {
+	yes, disableBar bool, concurrency int, filterArgs *FilterArgs) {
 
 	var (
 		retCode BosCliErrorCode
@@ -418,15 +533,35 @@ func (b *BosCli) Copy(srcPath, dstPath, storageClass, downLoadTmp string, recurs
 	Quiet = quiet
 	DisableBar = disableBar
 
-	isSourceRemotePath := strings.HasPrefix(srcPath, BOS_PATH_PREFIX)
-	isDestinationRemotePath := strings.HasPrefix(dstPath, BOS_PATH_PREFIX)
+	isSourceRemotePath := isRemotePath(srcPath)
+	isDestinationRemotePath := isRemotePath(dstPath)
+	transferSse.bindReadKey(srcPath, dstPath)
+
+	// generate filter
+	filter, retCode, err := newFilterFromArgs(filterArgs, srcPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+
+	concurrency, retCode, err = getConcurrency(concurrency)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+
+	// the version of source object is read
+	if retCode, err = bindVersionOfPath(srcPath, recursive); retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
 
 	if isSourceRemotePath && isDestinationRemotePath {
-		retCode, err = b.copyBetweenRemote(srcPath, dstPath, storageClass, recursive, restart)
+		retCode, err = b.copyBetweenRemote(srcPath, dstPath, storageClass, downLoadTmp, recursive,
+			restart, concurrency, filter)
 	} else if isSourceRemotePath {
-		retCode, err = b.copyDownload(srcPath, dstPath, downLoadTmp, recursive, yes, restart)
+		retCode, err = b.cliOfPath(srcPath).copyDownload(srcPath, dstPath, downLoadTmp, recursive,
+			yes, restart, concurrency, filter)
 	} else if isDestinationRemotePath {
-		retCode, err = b.copyUpload(srcPath, dstPath, storageClass, recursive, restart)
+		retCode, err = b.cliOfPath(dstPath).copyUpload(srcPath, dstPath, storageClass,
+			downLoadTmp, recursive, restart, concurrency, filter)
 	} else {
 		bcecliAbnormalExistMsg("You can use cp/copy to copy files between local file system.")
 	}
@@ -436,21 +571,30 @@ func (b *BosCli) Copy(srcPath, dstPath, storageClass, downLoadTmp string, recurs
 }
 
 type copyBetweenRemoteArgs struct {
+	srcBosClient  bosClientInterface
+	dstBosClient  bosClientInterface
 	srcBucketName string
 	srcObjectKey  string
 	dstBucketName string
 	dstObjectKey  string
 	srcIsDir      bool
+	isSameRemote  bool
+	concurrency   int
+	filter        *bosFilter
+	downLoadTmp   string // the folder of relay files of copy between different remotes
 }
 
 // implement copy objects
-func (b *BosCli) copyBetweenRemote(srcPath, dstPath, storageClass string, recursive,
-	restart bool) (BosCliErrorCode, error) {
+func (b *BosCli) copyBetweenRemote(srcPath, dstPath, storageClass, downLoadTmp string,
+	recursive, restart bool, concurrency int, filter *bosFilter) (BosCliErrorCode, error) {
 	// preprocessing and check request
 	args, retCode, err := b.copyRemoteRequestPreProcess(srcPath, dstPath, storageClass, recursive)
 	if err != nil {
 		return retCode, err
 	}
+	args.concurrency = concurrency
+	args.filter = filter
+	args.downLoadTmp = downLoadTmp
 
 	// execute copy between remote
 	ret, retCode, err := b.copyObjectExecute(args, storageClass, restart)
@@ -459,8 +603,7 @@ func (b *BosCli) copyBetweenRemote(srcPath, dstPath, storageClass string, recurs
 	if err != nil {
 		bcecliAbnormalExistCodeErr(retCode, err)
 	}
-	// TODO need print failed number
-	printIfNotQuiet("[%d] objects remote copied.\n", ret.successed)
+	printIfNotQuiet("[%d] objects remote copied, [%d] failed.\n", ret.successed, ret.failed)
 	if ret.failed > 0 {
 		return BOSCLI_EMPTY_CODE, nil
 	} else {
@@ -472,6 +615,8 @@ func (b *BosCli) copyBetweenRemote(srcPath, dstPath, storageClass string, recurs
 func (b *BosCli) copyRemoteRequestPreProcess(srcPath, dstPath, storageClass string,
 	recursive bool) (*copyBetweenRemoteArgs, BosCliErrorCode, error) {
 
+	srcRemoteName, _, _ := splitRemotePath(srcPath)
+	dstRemoteName, _, _ := splitRemotePath(dstPath)
 	srcBucketName, srcObjectKey := splitBosBucketKey(srcPath)
 	dstBucketName, dstObjectKey := splitBosBucketKey(dstPath)
 
@@ -480,6 +625,15 @@ func (b *BosCli) copyRemoteRequestPreProcess(srcPath, dstPath, storageClass stri
 		return nil, retCode, fmt.Errorf("don't support storage-class %s", storageClass)
 	}
 
+	srcBosClient, err := b.getClientOfPath(srcPath)
+	if err != nil {
+		return nil, BOSCLI_EMPTY_CODE, err
+	}
+	dstBosClient, err := b.getClientOfPath(dstPath)
+	if err != nil {
+		return nil, BOSCLI_EMPTY_CODE, err
+	}
+
 	if srcBucketName == "" {
 		return nil, BOSCLI_SRC_BUCKET_IS_EMPTY, fmt.Errorf("Please check source bucket name")
 	}
@@ -487,7 +641,7 @@ func (b *BosCli) copyRemoteRequestPreProcess(srcPath, dstPath, storageClass stri
 		return nil, BOSCLI_DST_BUCKET_IS_EMPTY, fmt.Errorf("Please check destination bucket name")
 	}
 
-	ok, err := b.handler.doesBucketExist(b.bosClient, srcBucketName)
+	ok, err := b.handler.doesBucketExist(srcBosClient, srcBucketName)
 	if err != nil {
 		return nil, BOSCLI_EMPTY_CODE, err
 	} else if !ok {
@@ -495,7 +649,7 @@ func (b *BosCli) copyRemoteRequestPreProcess(srcPath, dstPath, storageClass stri
 			srcBucketName)
 	}
 
-	ok, err = b.handler.doesBucketExist(b.bosClient, dstBucketName)
+	ok, err = b.handler.doesBucketExist(dstBosClient, dstBucketName)
 	if err != nil {
 		return nil, BOSCLI_EMPTY_CODE, err
 	} else if !ok {
@@ -511,11 +665,14 @@ func (b *BosCli) copyRemoteRequestPreProcess(srcPath, dstPath, storageClass stri
 		}
 		if recursive {
 			return &copyBetweenRemoteArgs{
+				srcBosClient:  srcBosClient,
+				dstBosClient:  dstBosClient,
 				srcBucketName: srcBucketName,
 				srcObjectKey:  srcObjectKey,
 				dstBucketName: dstBucketName,
 				dstObjectKey:  dstObjectKey,
 				srcIsDir:      true,
+				isSameRemote:  srcRemoteName == dstRemoteName,
 			}, BOSCLI_OK, nil
 		} else {
 			return nil, BOSCLI_BATCH_COPY_SRCOBJECT_END, fmt.Errorf("Please use -r to copy " +
@@ -525,34 +682,42 @@ func (b *BosCli) copyRemoteRequestPreProcess(srcPath, dstPath, storageClass stri
 
 	// src is single object
 	return &copyBetweenRemoteArgs{
+		srcBosClient:  srcBosClient,
+		dstBosClient:  dstBosClient,
 		srcBucketName: srcBucketName,
 		srcObjectKey:  srcObjectKey,
 		dstBucketName: dstBucketName,
 		dstObjectKey:  dstObjectKey,
+		isSameRemote:  srcRemoteName == dstRemoteName,
 	}, BOSCLI_OK, nil
 }
 
 // copy objects
+// when copy a directory, objects are copied concurrently by a bounded worker pool.
 func (b *BosCli) copyObjectExecute(args *copyBetweenRemoteArgs, storageClass string, restart bool) (
 	*executeResult, BosCliErrorCode, error) {
 
 	var (
-		copied        int
-		failedNum     int
-		listResult    *listFileResult
-		srcObjectName string
-		dstObjectName string
-		srcBosClient  bosClientInterface
-		err           error
+		listResult *listFileResult
+		err        error
 	)
 
-	objectLists := NewObjectListIterator(b.bosClient, nil, args.srcBucketName, args.srcObjectKey,
+	// one object is copied at a time when source is a single object
+	concurrency := 1
+	if args.srcIsDir {
+		concurrency = args.concurrency
+		DisableBar = true
+	}
+	executor := newOpExecutor(concurrency)
+
+	objectLists := NewObjectListIterator(args.srcBosClient, args.filter, args.srcBucketName,
+		args.srcObjectKey,
 		"", true, true, args.srcIsDir, false, 1000)
 
 	for {
 		listResult, err = objectLists.next()
 		if err != nil {
-			return &executeResult{successed: copied, failed: failedNum}, BOSCLI_EMPTY_CODE, err
+			return executor.wait(), BOSCLI_EMPTY_CODE, err
 		}
 		if listResult.ended {
 			break
@@ -562,8 +727,8 @@ func (b *BosCli) copyObjectExecute(args *copyBetweenRemoteArgs, storageClass str
 		}
 
 		object := listResult.file
-		srcObjectName = object.path
-		dstObjectName = object.key
+		srcObjectName := object.path
+		dstObjectName := object.key
 		if dstObjectName == "" {
 			continue
 		}
@@ -576,24 +741,27 @@ func (b *BosCli) copyObjectExecute(args *copyBetweenRemoteArgs, storageClass str
 			}
 		}
 
-		if isTheSameBucketAndObject(args.srcBucketName, srcObjectName, args.dstBucketName,
-			dstObjectName, storageClass, object.storageClass) {
-			failedNum++
+		// an old version can be copied to the object itself to restore it
+		if args.isSameRemote && versionIdOf(args.srcBucketName, srcObjectName) == nil &&
+			isTheSameBucketAndObject(args.srcBucketName, srcObjectName,
+				args.dstBucketName, dstObjectName, storageClass, object.storageClass) {
+			executor.addResult(false)
 			printIfNotQuiet("Can not cover object with same object, skip: %s\n", object.key)
 			continue
 		}
-		err = b.handler.utilCopyObject(srcBosClient, b.bosClient, args.srcBucketName, srcObjectName,
-			args.dstBucketName, dstObjectName, storageClass, object.size, object.mtime,
-			object.gtime, restart)
-		if err == nil {
-			copied++
-		} else {
-			failedNum++
-			fmt.Printf("Error occurs when copy object %s%s/%s: %s\n", BOS_PATH_PREFIX,
-				args.srcBucketName, srcObjectName, getErrorMsg(err))
-		}
+
+		executor.execute(func() error {
+			err := b.handler.utilCopyObject(args.srcBosClient, args.dstBosClient,
+				args.srcBucketName, srcObjectName, args.dstBucketName, dstObjectName, storageClass,
+				args.downLoadTmp, object.size, object.mtime, object.gtime, restart)
+			if err != nil {
+				fmt.Printf("Error occurs when copy object %s%s/%s: %s\n", BOS_PATH_PREFIX,
+					args.srcBucketName, srcObjectName, getErrorMsg(err))
+			}
+			return err
+		})
 	}
-	return &executeResult{successed: copied, failed: failedNum}, BOSCLI_EMPTY_CODE, err
+	return executor.wait(), BOSCLI_EMPTY_CODE, err
 }
 
 type copyDownloadArgs struct {
@@ -601,16 +769,20 @@ type copyDownloadArgs struct {
 	srcObjectKey       string
 	srcIsDir           bool
 	isDownloadToStream bool
+	concurrency        int
+	filter             *bosFilter
 }
 
 // implement downlaod object
 func (b *BosCli) copyDownload(srcPath, dstPath, downLoadTmp string, recursive, yes,
-	restart bool) (BosCliErrorCode, error) {
+	restart bool, concurrency int, filter *bosFilter) (BosCliErrorCode, error) {
 	// preprocessing request
 	args, retCode, err := b.copyDownloadPreProcess(srcPath, dstPath, recursive)
 	if err != nil {
 		return retCode, err
 	}
+	args.concurrency = concurrency
+	args.filter = filter
 
 	if args.isDownloadToStream {
 		return BOSCLI_EMPTY_CODE, fmt.Errorf("download to stream is not implement")
@@ -623,8 +795,7 @@ func (b *BosCli) copyDownload(srcPath, dstPath, downLoadTmp string, recursive, y
 	if err != nil {
 		return retCode, err
 	}
-	// TODO need print failed number
-	printIfNotQuiet("[%d] objects downloaded.\n", ret.successed)
+	printIfNotQuiet("[%d] objects downloaded, [%d] failed.\n", ret.successed, ret.failed)
 	if ret.failed > 0 {
 		return BOSCLI_EMPTY_CODE, nil
 	} else {
@@ -686,6 +857,7 @@ func (b *BosCli) copyDownloadPreProcess(srcPath, dstPath string, recursive bool)
 }
 
 // generate oplist and execute download
+// when download a directory, objects are downloaded concurrently by a bounded worker pool.
 func (b *BosCli) copyDownloadExecute(args *copyDownloadArgs, dstPath, downLoadTmp string, yes,
 	restart bool) (*executeResult, BosCliErrorCode, error) {
 
@@ -705,7 +877,7 @@ func (b *BosCli) copyDownloadExecute(args *copyDownloadArgs, dstPath, downLoadTm
 	// download single object
 	if !args.srcIsDir {
 		err = b.handler.utilDownloadObject(b.bosClient, args.srcBucketName, args.srcObjectKey,
-			dstPath, downLoadTmp, yes, 0, 0, 0, restart)
+			dstPath, downLoadTmp, "", yes, 0, 0, 0, restart)
 
 		if err == nil {
 			retCode = BOSCLI_OK
@@ -715,8 +887,10 @@ func (b *BosCli) copyDownloadExecute(args *copyDownloadArgs, dstPath, downLoadTm
 	}
 
 	// batch download
-	objectList := NewObjectListIterator(b.bosClient, nil, args.srcBucketName, args.srcObjectKey,
-		"", true, true, true, false, 1000)
+	DisableBar = true
+	executor := newOpExecutor(args.concurrency)
+	objectList := NewObjectListIterator(b.bosClient, args.filter, args.srcBucketName,
+		args.srcObjectKey, "", true, true, true, false, 1000)
 	for {
 		listResult, err = objectList.next()
 		if err != nil {
@@ -746,18 +920,18 @@ func (b *BosCli) copyDownloadExecute(args *copyDownloadArgs, dstPath, downLoadTm
 			dstFileName += util.OsPathSeparator + tmpDstFileName
 		}
 
-		err = b.handler.utilDownloadObject(b.bosClient, args.srcBucketName, srcObjectName,
-			dstFileName, downLoadTmp, yes, object.size, object.mtime, object.gtime, restart)
-
-		if err == nil {
-			downloaded++
-		} else {
-			failedNum++
-			fmt.Printf("Error occurs when download object %s%s/%s: %s\n", BOS_PATH_PREFIX,
-				args.srcBucketName, srcObjectName, getErrorMsg(err))
-		}
+		executor.execute(func() error {
+			err := b.handler.utilDownloadObject(b.bosClient, args.srcBucketName, srcObjectName,
+				dstFileName, downLoadTmp, object.etag, yes, object.size, object.mtime,
+				object.gtime, restart)
+			if err != nil {
+				fmt.Printf("Error occurs when download object %s%s/%s: %s\n", BOS_PATH_PREFIX,
+					args.srcBucketName, srcObjectName, getErrorMsg(err))
+			}
+			return err
+		})
 	}
-	return &executeResult{successed: downloaded, failed: failedNum}, retCode, err
+	return executor.wait(), retCode, err
 }
 
 type copyUploadArges struct {
@@ -767,15 +941,22 @@ type copyUploadArges struct {
 	srcIsDir         bool
 	uploadFromStream bool
 	concurrency      int
+	filter           *bosFilter
+	downLoadTmp      string // the folder of transformed files to be uploaded
 }
 
-func (b *BosCli) copyUpload(srcPath, dstPath, storageClass string, recursive,
-	restart bool) (BosCliErrorCode, error) {
+func (b *BosCli) copyUpload(srcPath, dstPath, storageClass, downLoadTmp string, recursive,
+	restart bool, concurrency int, filter *bosFilter) (BosCliErrorCode, error) {
 	// preprocessing and check request
 	args, retCode, err := b.copyUploadRequestPreProcess(srcPath, dstPath, storageClass, recursive)
 	if err != nil {
 		return retCode, err
 	}
+	if concurrency > 0 {
+		args.concurrency = concurrency
+	}
+	args.filter = filter
+	args.downLoadTmp = downLoadTmp
 
 	if args.uploadFromStream {
 		return BOSCLI_EMPTY_CODE, fmt.Errorf("upload from stream is not implement")
@@ -788,8 +969,7 @@ func (b *BosCli) copyUpload(srcPath, dstPath, storageClass string, recursive,
 	if err != nil {
 		return retCode, err
 	}
-	// TODO need print failed number
-	printIfNotQuiet("[%d] objects uploaded.\n", ret.successed)
+	printIfNotQuiet("[%d] objects uploaded, [%d] failed.\n", ret.successed, ret.failed)
 	if ret.failed > 0 {
 		return BOSCLI_EMPTY_CODE, nil
 	} else {
@@ -877,8 +1057,7 @@ func (b *BosCli) copyUploadRequestPreProcess(srcPath, dstPath, storageClass stri
 func (b *BosCli) uploadFileExecute(args *copyUploadArges, srcPath string, storageClass string,
 	restart bool) (*executeResult, BosCliErrorCode, error) {
 	var (
-		opSync sync.WaitGroup
-		Err    error
+		Err error
 	)
 
 	// upload from stream
@@ -888,60 +1067,9 @@ func (b *BosCli) uploadFileExecute(args *copyUploadArges, srcPath string, storag
 
 	// generate object list iterator
 	absSrcPath, _ := util.Abs(srcPath)
-	filesList := NewLocalFileIterator(absSrcPath, nil, true)
+	filesList := NewLocalFileIterator(absSrcPath, args.filter, true, useIgnoreFile)
 
-	// init channel
-	syncOpPool := make(chan int, args.concurrency)
-	executeResultChan := make(chan int, args.concurrency)
-	isEnd := make(chan bool, 1)
-	cpResultChan := make(chan executeResult, 1)
-
-	// define a function to count the failed and successed number of sync operation
-	cpResultCount := func(cpResultChan chan executeResult) {
-		failed := 0
-		successed := 0
-		for {
-			select {
-			case result := <-executeResultChan:
-				if result == -1 { // fail
-					failed += 1
-				} else if result == 1 { // success
-					successed += 1
-				}
-			case <-isEnd:
-				cpResultChan <- executeResult{failed: failed, successed: successed}
-				return
-			}
-		}
-	}
-
-	// this function is used to execute sync operation
-	cpOpFunc := func(filePath, realPath, bucketName, object, storageClass string, fileSize,
-		fileMtime, fileGtime int64, restart bool, wg *sync.WaitGroup) {
-		var (
-			err error
-		)
-
-		defer func() {
-			wg.Done()
-			<-syncOpPool
-		}()
-
-		err = b.handler.utilUploadFile(b.bosClient, filePath, realPath, bucketName,
-			object, storageClass, fileSize, fileMtime, fileGtime, restart)
-
-		// send result to cpResultCount
-		if err != nil {
-			executeResultChan <- -1 // -1 represent failed
-			printIfNotQuiet("Failed Upload: %s to %s%s/%s. Receive error: %s\n", filePath,
-				BOS_PATH_PREFIX, bucketName, object, err.Error())
-		} else {
-			executeResultChan <- 1 // 1 represent successed
-		}
-	}
-
-	// start cpResultCount
-	go cpResultCount(cpResultChan)
+	executor := newOpExecutor(args.concurrency)
 
 	// upload from file
 	for {
@@ -960,7 +1088,7 @@ func (b *BosCli) uploadFileExecute(args *copyUploadArges, srcPath string, storag
 
 		file := listResult.file
 		if file.err != nil {
-			executeResultChan <- -1
+			executor.addResult(false)
 			printIfNotQuiet("Failed Upload: %s. Receive error: %s\n", file.path, file.err.Error())
 			continue
 		}
@@ -969,24 +1097,25 @@ func (b *BosCli) uploadFileExecute(args *copyUploadArges, srcPath string, storag
 		finalObjectKey := getFinalObjectKeyFromLocalPath(absSrcPath, file.path, args.dstObjectKey,
 			args.srcIsDir)
 
-		syncOpPool <- 1
-		opSync.Add(1)
-		go cpOpFunc(file.path, file.realPath, args.dstBucketName, finalObjectKey, storageClass,
-			file.size, file.mtime, file.gtime, restart, &opSync)
+		executor.execute(func() error {
+			err := b.handler.utilUploadFile(b.bosClient, file.path, file.realPath,
+				args.dstBucketName, finalObjectKey, storageClass, args.downLoadTmp, file.size,
+				file.mtime, file.gtime, restart)
+			if err != nil {
+				printIfNotQuiet("Failed Upload: %s to %s%s/%s. Receive error: %s\n", file.path,
+					BOS_PATH_PREFIX, args.dstBucketName, finalObjectKey, err.Error())
+			}
+			return err
+		})
 	}
 
-	// waiting for all sync operation finish
-	opSync.Wait()
-
-	// end count result
-	// Sleep 200ms, waiting for cpResultCount add all op results
-	time.Sleep(200 * time.Millisecond)
-	isEnd <- true
-	ret := <-cpResultChan
-	return &ret, BOSCLI_EMPTY_CODE, Err
+	// waiting for all upload operation finish
+	return executor.wait(), BOSCLI_EMPTY_CODE, Err
 }
 
 type syncArgs struct {
+	srcBosClient         bosClientInterface
+	dstBosClient         bosClientInterface
 	srcPath              string
 	dstPath              string
 	srcBucketName        string
@@ -1006,8 +1135,9 @@ type syncArgs struct {
 // 3. compare and gen file list of src to be put to dst, and src to delete, if delete is defined
 // 4. if dryrun is defined, show list to be processed
 // param args: parsed args, must have SRC and DST explicitly defined
-func (b *BosCli) Sync(srcPath, dstPath, storageClass, downLoadTmp, syncType string, exclude, include, excludeTime,
-	includeTime, excludeDelete []string, concurrency int, del, dryrun, yes, quiet, disableBar, restart bool) {
+func (b *BosCli) Sync(srcPath, dstPath, storageClass, downLoadTmp, syncType string,
+	filterArgs *FilterArgs, excludeDelete []string, concurrency int, del, dryrun, yes, quiet,
+	disableBar, restart bool) {
 
 	var (
 		filter       *bosFilter = nil
@@ -1018,21 +1148,23 @@ func (b *BosCli) Sync(srcPath, dstPath, storageClass, downLoadTmp, syncType stri
 	Quiet = quiet
 	DisableBar = disableBar
 	IsConcurrentOperation = true
+	transferSse.bindReadKey(srcPath, dstPath)
+
+	if filterArgs == nil {
+		filterArgs = &FilterArgs{}
+	}
 
 	// preprocessing for sync reques
-	args, retCode, err := b.syncPreProcess(srcPath, dstPath, storageClass, exclude, include,
-		excludeTime, includeTime, concurrency, del, yes)
+	args, retCode, err := b.syncPreProcess(srcPath, dstPath, storageClass, filterArgs.Exclude,
+		filterArgs.Include, filterArgs.ExcludeTime, filterArgs.IncludeTime, concurrency, del, yes)
 	if err != nil {
 		bcecliAbnormalExistCodeErr(retCode, err)
 	}
 
 	//generate new filter
-	if len(exclude) > 0 || len(include) > 0 || len(excludeTime) > 0 || len(includeTime) > 0 {
-		filter, retCode, err = newSyncFilter(exclude, include, excludeTime, includeTime,
-			args.srcType == IS_LOCAL)
-		if err != nil {
-			bcecliAbnormalExistCodeErr(retCode, err)
-		}
+	filter, retCode, err = newFilterFromArgs(filterArgs, srcPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
 	}
 	if del && len(excludeDelete) > 0 {
 		deleteFilter, retCode, err = newSyncFilter(excludeDelete, []string{}, []string{},
@@ -1060,7 +1192,8 @@ func (b *BosCli) syncPreProcess(srcPath, dstPath, storageClass string, exclude,
 	BosCliErrorCode, error) {
 
 	var (
-		ok bool
+		ok  bool
+		err error
 	)
 
 	// exclude and include time cannot be used together
@@ -1077,10 +1210,13 @@ func (b *BosCli) syncPreProcess(srcPath, dstPath, storageClass string, exclude,
 	args := &syncArgs{}
 
 	// check src path
-	if strings.HasPrefix(srcPath, BOS_PATH_PREFIX) {
+	if isRemotePath(srcPath) {
 		args.srcType = IS_BOS
 		args.srcPath = trimTrailingSlash(srcPath) + boscmd.BOS_PATH_SEPARATOR
 		args.srcBucketName, args.srcObjectKey = splitBosBucketKey(args.srcPath)
+		if args.srcBosClient, err = b.getClientOfPath(srcPath); err != nil {
+			return nil, BOSCLI_EMPTY_CODE, err
+		}
 	} else {
 		args.srcType = IS_LOCAL
 		args.srcPath = trimTrailingSlash(srcPath) + util.OsPathSeparator
@@ -1093,10 +1229,13 @@ func (b *BosCli) syncPreProcess(srcPath, dstPath, storageClass string, exclude,
 	}
 
 	// check dst path
-	if strings.HasPrefix(dstPath, BOS_PATH_PREFIX) {
+	if isRemotePath(dstPath) {
 		args.dstType = IS_BOS
 		args.dstPath = trimTrailingSlash(dstPath) + boscmd.BOS_PATH_SEPARATOR
 		args.dstBucketName, args.dstObjectKey = splitBosBucketKey(args.dstPath)
+		if args.dstBosClient, err = b.getClientOfPath(dstPath); err != nil {
+			return nil, BOSCLI_EMPTY_CODE, err
+		}
 	} else {
 		args.dstType = IS_LOCAL
 		args.dstPath = trimTrailingSlash(dstPath) + util.OsPathSeparator
@@ -1111,15 +1250,9 @@ func (b *BosCli) syncPreProcess(srcPath, dstPath, storageClass string, exclude,
 	}
 
 	// get concurrency of sync
-	if concurrency < 0 {
-		return nil, BOSCLI_SYNC_PROCESS_NUM_LESS_ZERO, fmt.Errorf("concurrency for sync must" +
-			"greater than zero")
-	} else if concurrency == 0 {
-		concurrency, ok = bceconf.ServerConfigProvider.GetSyncProcessingNum()
-		if !ok {
-			return nil, BOSCLI_GET_SYNC_PROCESSING_NUM_FAILED, fmt.Errorf("There is no info " +
-				"about sync processing num found!")
-		}
+	concurrency, retCode, err := getConcurrency(concurrency)
+	if retCode != BOSCLI_OK {
+		return nil, retCode, err
 	}
 	args.syncProcessingNum = concurrency
 
@@ -1156,13 +1289,12 @@ func (b *BosCli) syncExecute(filter, deleteFilter *bosFilter, args *syncArgs, st
 	syncType string, del, dryrun, restart bool) (*executeResult, BosCliErrorCode, error) {
 
 	var (
-		srcBosClient bosClientInterface
-		srcFiles     fileListIterator
-		dstFiles     fileListIterator
-		atBothSide   syncStrategyInfterface
-		notAtSrc     syncStrategyInfterface
-		opSync       sync.WaitGroup
-		retErr       error
+		srcFiles   fileListIterator
+		dstFiles   fileListIterator
+		atBothSide syncStrategyInfterface
+		notAtSrc   syncStrategyInfterface
+		opSync     sync.WaitGroup
+		retErr     error
 	)
 
 	// get src file list iterator
@@ -1170,10 +1302,10 @@ func (b *BosCli) syncExecute(filter, deleteFilter *bosFilter, args *syncArgs, st
 		if absSrcPath, err := util.Abs(args.srcPath); err != nil {
 			return nil, BOSCLI_EMPTY_CODE, err
 		} else {
-			srcFiles = NewLocalFileIterator(absSrcPath, filter, true)
+			srcFiles = NewLocalFileIterator(absSrcPath, filter, true, useIgnoreFile)
 		}
 	} else if args.srcType == IS_BOS {
-		srcFiles = NewObjectListIterator(b.bosClient, filter, args.srcBucketName,
+		srcFiles = NewObjectListIterator(args.srcBosClient, filter, args.srcBucketName,
 			args.srcObjectKey, "", true, true, true, false, 1000)
 	} else {
 		return nil, BOSCLI_EMPTY_CODE, fmt.Errorf("Unknown source type!")
@@ -1184,17 +1316,24 @@ func (b *BosCli) syncExecute(filter, deleteFilter *bosFilter, args *syncArgs, st
 		if absDstPath, err := util.Abs(args.dstPath); err != nil {
 			return nil, BOSCLI_EMPTY_CODE, err
 		} else {
-			dstFiles = NewLocalFileIterator(absDstPath, nil, true)
+			dstFiles = NewLocalFileIterator(absDstPath, nil, true, false)
 		}
 	} else if args.dstType == IS_BOS {
-		dstFiles = NewObjectListIterator(b.bosClient, nil, args.dstBucketName, args.dstObjectKey,
+		dstFiles = NewObjectListIterator(args.dstBosClient, nil, args.dstBucketName, args.dstObjectKey,
 			"", true, true, true, false, 1000)
 	} else {
 		return nil, BOSCLI_EMPTY_CODE, fmt.Errorf("Unknown destination type!")
 	}
 
 	// init sync strategies
-	atBothSide = &sizeAndLastModifiedSync{}
+	atBothSide = &sizeAndLastModifiedSync{
+		srcType:       args.srcType,
+		dstType:       args.dstType,
+		srcBucketName: args.srcBucketName,
+		dstBucketName: args.dstBucketName,
+		srcBosClient:  args.srcBosClient,
+		dstBosClient:  args.dstBosClient,
+	}
 	notAtDst := &alwaysSync{}
 	if del {
 		notAtSrc = &deleteDstSync{
@@ -1244,24 +1383,25 @@ func (b *BosCli) syncExecute(filter, deleteFilter *bosFilter, args *syncArgs, st
 
 		switch flag {
 		case SYNC_OP_COPY:
-			err = b.handler.utilCopyObject(srcBosClient, b.bosClient, args.srcBucketName,
-				syncInfo.srcPath, args.dstBucketName, syncInfo.dstPath, storageClass,
+			err = b.handler.utilCopyObject(args.srcBosClient, args.dstBosClient, args.srcBucketName,
+				syncInfo.srcPath, args.dstBucketName, syncInfo.dstPath, storageClass, downLoadTmp,
 				syncInfo.srcFileInfo.size, syncInfo.srcFileInfo.mtime, syncInfo.srcFileInfo.gtime,
 				restart)
 
 		case SYNC_OP_UPLOAD:
-			err = b.handler.utilUploadFile(b.bosClient, syncInfo.srcPath,
+			err = b.handler.utilUploadFile(args.dstBosClient, syncInfo.srcPath,
 				syncInfo.srcFileInfo.realPath, args.dstBucketName, syncInfo.dstPath, storageClass,
-				syncInfo.srcFileInfo.size, syncInfo.srcFileInfo.mtime, syncInfo.srcFileInfo.gtime,
-				restart)
+				downLoadTmp, syncInfo.srcFileInfo.size, syncInfo.srcFileInfo.mtime,
+				syncInfo.srcFileInfo.gtime, restart)
 
 		case SYNC_OP_DOWNLOAD:
-			err = b.handler.utilDownloadObject(b.bosClient, args.srcBucketName, syncInfo.srcPath,
-				syncInfo.dstPath, downLoadTmp, overWriteDst, syncInfo.srcFileInfo.size,
-				syncInfo.srcFileInfo.mtime, syncInfo.srcFileInfo.gtime, restart)
+			err = b.handler.utilDownloadObject(args.srcBosClient, args.srcBucketName, syncInfo.srcPath,
+				syncInfo.dstPath, downLoadTmp, syncInfo.srcFileInfo.etag, overWriteDst,
+				syncInfo.srcFileInfo.size, syncInfo.srcFileInfo.mtime, syncInfo.srcFileInfo.gtime,
+				restart)
 
 		case SYNC_OP_REMOVE:
-			err = b.handler.utilDeleteObject(b.bosClient, args.dstBucketName, syncInfo.dstPath)
+			err = b.handler.utilDeleteObject(args.dstBosClient, args.dstBucketName, syncInfo.dstPath)
 
 		case SYNC_OP_DELETE:
 			err = b.handler.utilDeleteLocalFile(syncInfo.dstPath)
@@ -55,7 +55,9 @@ func getUserAgent() string {
 // build a new http client
 func newHttpClient() *http.Client {
 	httpClient := &http.Client{}
+	// objects are got as they are stored, even when they have Content-Encoding
 	transport := &http.Transport{
+		DisableCompression:    true,
 		MaxIdleConns:          defaultMaxIdleConns,
 		MaxIdleConnsPerHost:   defaultMaxIdleConnsPerHost,
 		ResponseHeaderTimeout: defaultResponseHeaderTimeout,
@@ -87,7 +89,8 @@ func setEndpointProtocol(endpoint string, useHttps bool) (string, error) {
 	return HTTP_PROTOCOL + endpoint, nil
 }
 
-func newBosClient(ak, sk, stsToken, endpoint string, useHttps bool) (*s3ClientWrapper, error) {
+func newBosClient(ak, sk, stsToken, endpoint, region string, useHttps bool) (*s3ClientWrapper,
+	error) {
 
 	// set http or https protocol
 	endpoint, err := setEndpointProtocol(endpoint, useHttps)
@@ -96,7 +99,6 @@ func newBosClient(ak, sk, stsToken, endpoint string, useHttps bool) (*s3ClientWr
 	}
 
 	cres := credentials.NewStaticCredentials(ak, sk, stsToken)
-	region, _ := bceconf.ServerConfigProvider.GetRegion()
 	cfg := &aws.Config{
 		Credentials: cres,
 		Endpoint:    &endpoint,
@@ -146,13 +148,69 @@ func buildBosClient(ak, sk, endpoint string,
 		}
 	}
 
+	region, ok := serverConfigProvider.GetRegion()
+	if !ok {
+		return nil, fmt.Errorf("There is no region found!")
+	}
+
 	if useHttps, ok := serverConfigProvider.GetUseHttpsProtocol(); !ok {
 		return nil, fmt.Errorf("There is no https protocol info found!")
 	} else {
-		return newBosClient(ak, sk, stsToken, endpoint, useHttps)
+		return newBosClient(ak, sk, stsToken, endpoint, region, useHttps)
 	}
 }
 
+// Init the client of a named remote.
+// The endpoint of remote must be configured, the region, https and credential of remote are
+// optional, use the default configuration when they are not configured.
+func buildRemoteClient(remoteName string,
+	credentialProvider bceconf.CredentialProviderInterface,
+	serverConfigProvider bceconf.ServerConfigProviderInterface) (*s3ClientWrapper, error) {
+	var (
+		ak       string
+		sk       string
+		stsToken string
+		ok       bool
+	)
+
+	remote, ok := serverConfigProvider.GetRemote(remoteName)
+	if !ok {
+		return nil, fmt.Errorf("There is no remote named %s found!", remoteName)
+	}
+
+	if cred, ok := credentialProvider.GetRemoteCredential(remoteName); ok {
+		ak, sk, stsToken = cred.Ak, cred.Sk, cred.Sts
+	} else {
+		if ak, ok = credentialProvider.GetAccessKey(); !ok {
+			return nil, fmt.Errorf("There is no access key found!")
+		}
+		if sk, ok = credentialProvider.GetSecretKey(); !ok {
+			return nil, fmt.Errorf("There is no access secret key found!")
+		}
+		stsToken, _ = credentialProvider.GetSecurityToken()
+	}
+
+	region := remote.Region
+	if region == "" {
+		if region, ok = serverConfigProvider.GetRegion(); !ok {
+			return nil, fmt.Errorf("There is no region found!")
+		}
+	}
+
+	useHttps, ok := bceconf.AOLLOWED_CONFIRM_OPTIONS[remote.Https]
+	if !ok {
+		if useHttps, ok = serverConfigProvider.GetUseHttpsProtocol(); !ok {
+			return nil, fmt.Errorf("There is no https protocol info found!")
+		}
+	}
+	client, err := newBosClient(ak, sk, stsToken, remote.Endpoint, region, useHttps)
+	if err != nil {
+		return nil, err
+	}
+	client.remoteName = remoteName
+	return client, nil
+}
+
 // init BosCLient
 func bosClientInit(ak, sk, endpoint string) (bosClientInterface, error) {
 	return buildBosClient(
@@ -163,3 +221,12 @@ func bosClientInit(ak, sk, endpoint string) (bosClientInterface, error) {
 		bceconf.ServerConfigProvider,
 	)
 }
+
+// init the client of remote
+func remoteClientInit(remoteName string) (bosClientInterface, error) {
+	return buildRemoteClient(
+		remoteName,
+		bceconf.CredentialProvider,
+		bceconf.ServerConfigProvider,
+	)
+}
@@ -14,28 +14,30 @@ package boscli
 
 import (
 	"fmt"
-	"net/http"
 	"os"
-	// 	"runtime"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 import (
 	"bcecmd/boscmd"
 	"bceconf"
-	// 	"github.com/baidubce/bce-sdk-go/services/bos"
-	"github.com/baidubce/bce-sdk-go/bce"
-	"github.com/baidubce/bce-sdk-go/services/bos/api"
 	"utils/util"
 )
 
 var (
-	testBosCli          *BosCli
-	bosClientWrapperVal *bosClientWrapper
-	testBosHandler      *fakeCliHandler
+	testBosCli      *BosCli
+	remoteBosClient *fakeRemoteBosClient
+	testBosHandler  *fakeCliHandler
 )
 
 type fakeCliHandler struct {
@@ -45,8 +47,8 @@ type fakeCliHandler struct {
 	utilUploadFileArgVal string
 }
 
-func (h *fakeCliHandler) multiDeleteDir(bosClient bosClientInterface, bucketName,
-	objectKey string) (int, error) {
+func (h *fakeCliHandler) multiDeleteDir(bosClient bosClientInterface, filter *bosFilter,
+	bucketName, objectKey string) (int, error) {
 	h.multiDeleteDirArgVal = bucketName + objectKey
 	if bucketName == "error-delete-dir" {
 		return 0, fmt.Errorf("error-delete-dir")
@@ -60,8 +62,8 @@ func (h *fakeCliHandler) multiDeleteDir(bosClient bosClientInterface, bucketName
 
 // Delete objects, retry twice.
 func (h *fakeCliHandler) multiDeleteObjectsWithRetry(bosClient bosClientInterface,
-	objectList []string, bucketName string) ([]api.DeleteObjectResult, error) {
-	return []api.DeleteObjectResult{}, nil
+	objectList []string, bucketName string) ([]DeleteObjectResult, error) {
+	return []DeleteObjectResult{}, nil
 }
 
 // delete single object
@@ -76,8 +78,8 @@ func (h *fakeCliHandler) utilDeleteObject(bosClient bosClientInterface, bucketNa
 
 // copy single object
 func (h *fakeCliHandler) utilCopyObject(srcBosClient, bosClient bosClientInterface, srcBucketName,
-	srcObjectKey, dstBucketName, dstObjectKey, storageClass string, fileSize, fileMtime,
-	timeOfgetObjectInfo int64, restart bool) error {
+	srcObjectKey, dstBucketName, dstObjectKey, storageClass, downLoadTmp string, fileSize,
+	fileMtime, timeOfgetObjectInfo int64, restart bool) error {
 	if dstObjectKey == "copyDstObjectError" {
 		return fmt.Errorf("error")
 	}
@@ -86,7 +88,8 @@ func (h *fakeCliHandler) utilCopyObject(srcBosClient, bosClient bosClientInterfa
 
 // download an object to local
 func (h *fakeCliHandler) utilDownloadObject(bosClient bosClientInterface, srcBucketName, srcObjectKey,
-	dstFilePath, downLoadTmp string, yes bool, fileSize, mtime, timeOfgetObjectInfo int64, restart bool) error {
+	dstFilePath, downLoadTmp, etag string, yes bool, fileSize, mtime, timeOfgetObjectInfo int64,
+	restart bool) error {
 	h.utilDownlaodArgVal = srcBucketName + srcObjectKey + dstFilePath
 	if yes {
 		h.utilDownlaodArgVal += "yes"
@@ -106,7 +109,7 @@ func (h *fakeCliHandler) utilDownloadObject(bosClient bosClientInterface, srcBuc
 // upload a file
 
 func (h *fakeCliHandler) utilUploadFile(bosClient bosClientInterface, srcPath, relSrcPath,
-	dstBucketName, dstObjectKey, storageClass string, fileSize, fileMtime,
+	dstBucketName, dstObjectKey, storageClass, downLoadTmp string, fileSize, fileMtime,
 	timeOfgetObjectInfo int64, restart bool) error {
 	h.utilUploadFileArgVal = srcPath + relSrcPath + dstBucketName + dstObjectKey + storageClass +
 		strconv.FormatInt(fileSize, 10)
@@ -145,9 +148,10 @@ func (h *fakeCliHandler) doesBucketExist(bosClient bosClientInterface, bucketNam
 }
 
 type fakeBosClientForBos struct {
+	fakeUnsupportedBosClient
 	opType              string
-	results             []*api.ListObjectsResult
-	objectMeta          *api.GetObjectMetaResult
+	results             []*s3.ListObjectsOutput
+	objectMeta          *s3.HeadObjectOutput
 	DeleteBucketName    string
 	makeBucketName      string
 	GetObjectMetaArgVal string
@@ -158,35 +162,30 @@ func (b *fakeBosClientForBos) HeadBucket(bucket string) error {
 		return nil
 	}
 	if bucket == "notexist" {
-		return &bce.BceServiceError{
-			StatusCode: http.StatusNotFound,
-		}
+		return awserr.New(boscmd.CODE_NO_SUCH_BUCKET, "", nil)
 	}
 	if bucket == "error" {
 		return fmt.Errorf("error")
 	}
 
 	if bucket == "forbidden" {
-		return &bce.BceServiceError{
-			StatusCode: http.StatusForbidden,
-		}
+		return awserr.New(boscmd.CODE_ACCESS_DENIED, "", nil)
 	}
 	return fmt.Errorf("unknow error")
 }
 
 // Fake ListBuckets - list all buckets
-func (b *fakeBosClientForBos) ListBuckets() (*api.ListBucketsResult, error) {
+func (b *fakeBosClientForBos) ListBuckets() (*s3.ListBucketsOutput, error) {
 	if b.opType == "error" {
 		return nil, fmt.Errorf("test")
 	}
-	locations := []string{"bj", "gz", "su"}
-	ret := &api.ListBucketsResult{}
+	ret := &s3.ListBucketsOutput{}
 	if num, err := strconv.Atoi(b.opType); err == nil {
 		for i := 1; i <= num; i++ {
-			ret.Buckets = append(ret.Buckets, api.BucketSummaryType{
-				Name:         "bucket" + strconv.Itoa(i),
-				Location:     locations[i%3],
-				CreationDate: util.TranTimestamptoLocalTime(int64(i*1000+23457), BOS_TIME_FORMT),
+			creationDate := time.Unix(int64(i*1000+23457), 0)
+			ret.Buckets = append(ret.Buckets, &s3.Bucket{
+				Name:         aws.String("bucket" + strconv.Itoa(i)),
+				CreationDate: &creationDate,
 			})
 		}
 		return ret, nil
@@ -210,37 +209,46 @@ func (b *fakeBosClientForBos) GetBucketLocation(bucket string) (string, error) {
 }
 
 // Fake ListObjects - list all objects of the given bucket
-func (b *fakeBosClientForBos) ListObjects(bucket string, args *api.ListObjectsArgs) (
-	*api.ListObjectsResult, error) {
+func (b *fakeBosClientForBos) ListObjects(bucket, delimiter, marker, prefix string,
+	maxKeys int) (*s3.ListObjectsOutput, error) {
 	var (
-		marker int
-		err    error
+		index int
+		err   error
 	)
 	if bucket == "error" {
-		ret := bucket + args.Prefix
-		if args.Delimiter == "" {
+		ret := bucket + prefix
+		if delimiter == "" {
 			ret += "recursive"
 		}
-		return nil, fmt.Errorf(ret)
+		return nil, fmt.Errorf("%s", ret)
 	}
-	if args.Marker == "" {
-		marker, err = strconv.Atoi(bucket)
+	if marker == "" {
+		index, err = strconv.Atoi(bucket)
 	} else {
-		marker, err = strconv.Atoi(args.Marker)
+		index, err = strconv.Atoi(marker)
 	}
 	if err != nil {
 		return nil, err
 	}
-	if marker < len(b.results) {
-		if args.Delimiter == "" {
-			return &api.ListObjectsResult{
-				Contents:    b.results[marker].Contents,
-				IsTruncated: b.results[marker].IsTruncated,
-				NextMarker:  b.results[marker].NextMarker,
-			}, nil
-		} else {
-			return b.results[marker], nil
+	if index < len(b.results) {
+		// only the objects and dirs with the prefix are listed
+		ret := &s3.ListObjectsOutput{
+			IsTruncated: aws.Bool(aws.BoolValue(b.results[index].IsTruncated)),
+			NextMarker:  b.results[index].NextMarker,
 		}
+		for _, item := range b.results[index].Contents {
+			if strings.HasPrefix(*item.Key, prefix) {
+				ret.Contents = append(ret.Contents, item)
+			}
+		}
+		if delimiter != "" {
+			for _, item := range b.results[index].CommonPrefixes {
+				if strings.HasPrefix(*item.Prefix, prefix) {
+					ret.CommonPrefixes = append(ret.CommonPrefixes, item)
+				}
+			}
+		}
+		return ret, nil
 	}
 	return nil, fmt.Errorf("Error in list objects")
 }
@@ -255,20 +263,14 @@ func (b *fakeBosClientForBos) DeleteBucket(bucket string) error {
 
 }
 
-// Fake BasicGeneratePresignedUrl  generate an authorization url with expire time
-func (b *fakeBosClientForBos) BasicGeneratePresignedUrl(bucket string, object string,
-	expireInSeconds int) string {
-	return ""
-}
-
 // Fake DeleteMultipleObjectsFromKeyList - delete a list of objects with given key string array
 func (b *fakeBosClientForBos) DeleteMultipleObjectsFromKeyList(bucket string,
-	keyList []string) (*api.DeleteMultipleObjectsResult, error) {
+	keyList []string) (*DeleteMultipleObjectsResult, error) {
 	retryNum := 1
 	if strings.HasSuffix(keyList[0], "retry") {
 		retryNum = 2
 	}
-	ret := &api.DeleteMultipleObjectsResult{}
+	ret := &DeleteMultipleObjectsResult{}
 	haveError := false
 	for _, val := range keyList {
 		if strings.HasPrefix(val, "TFAILED") {
@@ -278,7 +280,7 @@ func (b *fakeBosClientForBos) DeleteMultipleObjectsFromKeyList(bucket string,
 				continue
 			}
 			val += "retry"
-			ret.Errors = append(ret.Errors, api.DeleteObjectResult{
+			ret.Errors = append(ret.Errors, DeleteObjectResult{
 				Key: val, Code: components[2], Message: components[3]})
 			haveError = true
 		}
@@ -292,20 +294,18 @@ func (b *fakeBosClientForBos) DeleteMultipleObjectsFromKeyList(bucket string,
 // Fake DeleteObject - delete the given object
 func (b *fakeBosClientForBos) DeleteObject(bucket, object string) error {
 	if bucket == "error" {
-		return fmt.Errorf(bucket + object)
+		return fmt.Errorf("%s", bucket+object)
 	}
 	return nil
 }
 
 // Fake GetObjectMeta
-func (b *fakeBosClientForBos) GetObjectMeta(bucket, object string) (*api.GetObjectMetaResult,
+func (b *fakeBosClientForBos) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput,
 	error) {
 
 	b.GetObjectMetaArgVal = bucket + object
 	if object == "404" {
-		return nil, &bce.BceServiceError{
-			StatusCode: 404,
-		}
+		return nil, awserr.New(boscmd.CODE_NO_SUCH_KEY, "", nil)
 	}
 	if b.objectMeta == nil {
 		return nil, fmt.Errorf("Error in list objects")
@@ -314,8 +314,8 @@ func (b *fakeBosClientForBos) GetObjectMeta(bucket, object string) (*api.GetObje
 }
 
 // Fake Copy Object
-func (b *fakeBosClientForBos) CopyObject(bucket, object, srcBucket, srcObject string,
-	args *api.CopyObjectArgs) (*api.CopyObjectResult, error) {
+func (b *fakeBosClientForBos) CopyObject(bucket, object, srcBucket, srcObject,
+	storageClass string) (*s3.CopyObjectOutput, error) {
 	if srcBucket == "error" {
 		return nil, fmt.Errorf("error")
 	}
@@ -326,135 +326,50 @@ func (b *fakeBosClientForBos) CopyObject(bucket, object, srcBucket, srcObject st
 }
 
 // Fake of BasiGetObjectToFile
-func (b *fakeBosClientForBos) BasicGetObjectToFile(bucket, object, localPath string) error {
+func (b *fakeBosClientForBos) BasicGetObjectToFile(bucket, object, localPath string) (
+	map[string]*string, error) {
 	if bucket == "success" && object == "a/b/c" {
-		return nil
+		return nil, nil
 	}
-	return fmt.Errorf(bucket + object + localPath)
+	return nil, fmt.Errorf("%s", bucket+object+localPath)
 }
 
 // Fake of PutObjectFromFile
-func (b *fakeBosClientForBos) PutObjectFromFile(bucket, object, fileName string,
-	args *api.PutObjectArgs) (string, error) {
+func (b *fakeBosClientForBos) PutObjectFromFile(bucket, object, fileName,
+	storageClass string, meta map[string]*string) (string, error) {
 	if fileName == "success" {
 		return "", nil
 	}
-	return "", fmt.Errorf("smail" + fileName + bucket + object + args.StorageClass)
-}
-
-// Fake of UploadSuperFile
-func (b *fakeBosClientForBos) UploadSuperFile(bucket, object, fileName, storageClass string) error {
-	if fileName == "success" {
-		return nil
-	}
-	return fmt.Errorf("big" + fileName + bucket + object + storageClass)
-}
-
-// Fake of BasicUploadPart
-func (b *fakeBosClientForBos) BasicUploadPart(bucket, object, uploadId string, partNumber int,
-	content *bce.Body) (string, error) {
-	return "", fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClientForBos) UploadPartFromBytes(bucket, object, uploadId string, partNumber int,
-	content []byte, args *api.UploadPartArgs) (string, error) {
-	return "", fmt.Errorf("Not support")
-}
-
-// Fake of GetBucketStorageclass
-func (b *fakeBosClientForBos) UploadPartCopy(bucket, object, srcBucket, srcObject, uploadId string,
-	partNumber int, args *api.UploadPartCopyArgs) (*api.CopyObjectResult, error) {
-	return nil, fmt.Errorf("Not support")
-}
-
-// Fake of GetBucketStorageclass
-func (b *fakeBosClientForBos) InitiateMultipartUpload(bucket, object, contentType string,
-	args *api.InitiateMultipartUploadArgs) (*api.InitiateMultipartUploadResult, error) {
-	return nil, fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClientForBos) AbortMultipartUpload(bucket, object, uploadId string) error {
-	return fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClientForBos) CompleteMultipartUploadFromStruct(bucket, object, uploadId string,
-	parts *api.CompleteMultipartUploadArgs,
-	meta map[string]string) (*api.CompleteMultipartUploadResult, error) {
-
-	return nil, fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClientForBos) GetObject(bucket, object string, responseHeaders map[string]string,
-	ranges ...int64) (*api.GetObjectResult, error) {
-
-	return nil, fmt.Errorf("Not support")
+	return "", fmt.Errorf("%s", "smail"+fileName+bucket+object+storageClass)
 }
 
 func init() {
 	bosClientForBos := &fakeBosClientForBos{
-		results: []*api.ListObjectsResult{
-			&api.ListObjectsResult{
-				CommonPrefixes: []api.PrefixType{
-					api.PrefixType{
-						Prefix: "a/dir/",
-					},
-					api.PrefixType{
-						Prefix: "a/dir2/",
-					},
+		results: []*s3.ListObjectsOutput{
+			&s3.ListObjectsOutput{
+				CommonPrefixes: []*s3.CommonPrefix{
+					fakePrefix("a/dir/"),
+					fakePrefix("a/dir2/"),
 				},
-				Contents: []api.ObjectSummaryType{
-					api.ObjectSummaryType{
-						Key:          "a/b",
-						LastModified: "2006-01-02T15:04:05Z",
-						Size:         100,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "a/c",
-						LastModified: "2016-11-02T15:04:05Z",
-						Size:         200,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "a/d",
-						LastModified: "2017-11-02T15:04:05Z",
-						Size:         300,
-						StorageClass: "STANDARD",
-					},
+				Contents: []*s3.Object{
+					fakeObject("a/b", "2006-01-02T15:04:05Z", 100, ""),
+					fakeObject("a/c", "2016-11-02T15:04:05Z", 200, ""),
+					fakeObject("a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 				},
-				IsTruncated: true,
-				NextMarker:  "1",
+				IsTruncated: aws.Bool(true),
+				NextMarker:  aws.String("1"),
 			},
-			&api.ListObjectsResult{
-				CommonPrefixes: []api.PrefixType{
-					api.PrefixType{
-						Prefix: "a/eir/",
-					},
-					api.PrefixType{
-						Prefix: "a/eir2/",
-					},
+			&s3.ListObjectsOutput{
+				CommonPrefixes: []*s3.CommonPrefix{
+					fakePrefix("a/eir/"),
+					fakePrefix("a/eir2/"),
 				},
-				Contents: []api.ObjectSummaryType{
-					api.ObjectSummaryType{
-						Key:          "a/f",
-						LastModified: "2006-01-02T15:04:05Z",
-						Size:         100,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "a/g",
-						LastModified: "2016-11-02T15:04:05Z",
-						Size:         200,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "a/h",
-						LastModified: "2017-11-02T15:04:05Z",
-						Size:         300,
-						StorageClass: "STANDARD",
-					},
+				Contents: []*s3.Object{
+					fakeObject("a/f", "2006-01-02T15:04:05Z", 100, ""),
+					fakeObject("a/g", "2016-11-02T15:04:05Z", 200, ""),
+					fakeObject("a/h", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 				},
-				IsTruncated: false,
+				IsTruncated: aws.Bool(false),
 			},
 		},
 	}
@@ -467,13 +382,7 @@ func init() {
 	testBosCli.bosClient = bosClientForBos
 	testBosHandler = &fakeCliHandler{}
 	testBosCli.handler = testBosHandler
-	bosClientTemp, err := buildBosClient("", "", "", bceconf.CredentialProvider,
-		bceconf.ServerConfigProvider)
-	if err != nil {
-		fmt.Printf("create new bos.Client failed")
-		os.Exit(1)
-	}
-	bosClientWrapperVal = &bosClientWrapper{bosClient: bosClientTemp}
+	remoteBosClient = newFakeRemoteBosClient()
 }
 
 func TestNewBosCli(t *testing.T) {
@@ -662,7 +571,7 @@ func TestList(t *testing.T) {
 			t.Errorf("List: bosClient is not fakeBosClientForBos")
 			continue
 		}
-		testBosCli.List(tCase.bosPath, tCase.all, tCase.recursive, tCase.summary)
+		testBosCli.List(tCase.bosPath, tCase.all, tCase.recursive, tCase.summary, nil)
 	}
 }
 
@@ -760,7 +669,7 @@ func TestBosListObjects(t *testing.T) {
 	}
 	for i, tCase := range testCases {
 		err := testBosCli.listObjects(tCase.bucketName, tCase.objectKey, tCase.all, tCase.recursive,
-			tCase.summary)
+			tCase.summary, nil)
 		util.ExpectEqual("tools.go listObjects I", i+1, t.Errorf, tCase.isSuc, err == nil)
 		if !tCase.isSuc {
 			util.ExpectEqual("tools.go listObjects I", i+1, t.Errorf, tCase.out, err.Error())
@@ -814,19 +723,21 @@ func TestMakeBucketPreProcess(t *testing.T) {
 			yes:        "yes",
 			code:       BOSCLI_OK,
 		},
-		//7
+		//7 the region is chosen by the endpoint, so there is no confirmation
 		makeBucketPreProcessType{
 			bucketName: "liupen-gz",
+			out:        "liupen-gz",
 			region:     "gz",
 			useAuto:    false,
 			yes:        "no",
-			code:       BOSCLI_OPRATION_CANCEL,
+			code:       BOSCLI_OK,
 		},
 		makeBucketPreProcessType{
 			bucketName: "liupen-gz",
+			out:        "liupen-gz",
 			region:     "gz",
 			useAuto:    true,
-			code:       BOSCLI_INTERNAL_ERROR,
+			code:       BOSCLI_OK,
 		},
 		//9
 		makeBucketPreProcessType{
@@ -892,7 +803,7 @@ func TestMakeBucketPreProcess(t *testing.T) {
 		}
 		tempClient := testBosCli.bosClient
 		if tCase.changeClient {
-			testBosCli.bosClient = bosClientWrapperVal
+			testBosCli.bosClient = remoteBosClient
 		}
 		ret, code := testBosCli.makeBucketPreProcess(tCase.bucketName, tCase.region)
 		util.ExpectEqual("bos.go makeBucketPreProcess I", i+1, t.Errorf, tCase.code, code)
@@ -1545,7 +1456,7 @@ func TestRemoveObject(t *testing.T) {
 			defer os.Remove(tempFileName)
 			os.Stdin = fd
 		}
-		testBosCli.RemoveObject(tCase.bosPath, tCase.yes, tCase.recursive, false)
+		testBosCli.RemoveObject(tCase.bosPath, tCase.yes, tCase.recursive, false, false, nil)
 		fakeClient, ok := testBosCli.handler.(*fakeCliHandler)
 		if !ok {
 			t.Errorf("handler is not fakeCliHandler")
@@ -1754,12 +1665,8 @@ type copyObjectList struct {
 func getTestCopyList(bucketName, objectKey string) ([]copyObjectList, error) {
 	ret := []copyObjectList{}
 
-	client, err := initBosClientForBucket("", "", bucketName)
-	if err != nil {
-		return nil, err
-	}
-	objectLists := NewObjectListIterator(client, nil, bucketName, objectKey, "", true, true, true,
-		false, 1000)
+	objectLists := NewObjectListIterator(remoteBosClient, nil, bucketName, objectKey, "", true,
+		true, true, false, 1000)
 
 	for {
 		listResult, err := objectLists.next()
@@ -1890,6 +1797,9 @@ func TestCopyObjectExecute(t *testing.T) {
 	}
 	for i, tCase := range testCases {
 		args := &copyBetweenRemoteArgs{
+			srcBosClient:  remoteBosClient,
+			dstBosClient:  remoteBosClient,
+			isSameRemote:  true,
 			srcBucketName: tCase.srcBucket,
 			srcObjectKey:  tCase.srcObject,
 			dstBucketName: tCase.dstBucket,
@@ -1928,6 +1838,11 @@ type copyRemoteType struct {
 }
 
 func TestCopyBetweenRemote(t *testing.T) {
+	tempClient := testBosCli.bosClient
+	testBosCli.bosClient = remoteBosClient
+	defer func() {
+		testBosCli.bosClient = tempClient
+	}()
 	testCases := []copyRemoteType{
 		//1
 		copyRemoteType{
@@ -1957,7 +1872,7 @@ func TestCopyBetweenRemote(t *testing.T) {
 	}
 	for i, tCase := range testCases {
 		retCode, _ := testBosCli.copyBetweenRemote(tCase.srcPath, tCase.dstPath, tCase.storageClass,
-			tCase.recursive, true)
+			"", tCase.recursive, true, 1, nil)
 		util.ExpectEqual("bos.go copyBetweenRemote", i+1, t.Errorf, tCase.isSuc, retCode == BOSCLI_OK)
 	}
 }
@@ -2011,7 +1926,7 @@ func TestCopyDownloadPreProcess(t *testing.T) {
 		//6
 		copyDownloadPreProcessType{
 			srcPath: "bos:/bucket/",
-			dstPath: "/root/",
+			dstPath: "/proc/",
 			code:    BOSCLI_DIR_IS_NOT_WRITABLE,
 			isSuc:   false,
 		},
@@ -2115,66 +2030,20 @@ type copyDownloadExecuteType struct {
 
 var (
 	tempFakeBosClient = &fakeBosClientForBos{
-		objectMeta: &api.GetObjectMetaResult{
-			api.ObjectMeta{
-				LastModified:  "Wed, 06 Apr 2016 06:34:40 GMT",
-				ContentLength: 100,
-				StorageClass:  "STANDARD",
-			},
-		},
-		results: []*api.ListObjectsResult{
-			&api.ListObjectsResult{
-				Contents: []api.ObjectSummaryType{
-					api.ObjectSummaryType{
-						Key:          "key/a/b",
-						LastModified: "2006-01-02T15:04:05Z",
-						Size:         100,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/c",
-						LastModified: "2016-11-02T15:04:05Z",
-						Size:         200,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/d",
-						LastModified: "2017-11-02T15:04:05Z",
-						Size:         300,
-						StorageClass: "STANDARD",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/f",
-						LastModified: "2006-01-02T15:04:05Z",
-						Size:         100,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/g",
-						LastModified: "2016-11-02T15:04:05Z",
-						Size:         200,
-						StorageClass: "",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/h",
-						LastModified: "2017-11-02T15:04:05Z",
-						Size:         300,
-						StorageClass: "STANDARD",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/error",
-						LastModified: "2017-11-02T15:04:05Z",
-						Size:         300,
-						StorageClass: "STANDARD",
-					},
-					api.ObjectSummaryType{
-						Key:          "key/a/i",
-						LastModified: "2017-11-02T15:04:05Z",
-						Size:         300,
-						StorageClass: "STANDARD",
-					},
+		objectMeta: fakeObjectMeta("Wed, 06 Apr 2016 06:34:40 GMT", 100, "STANDARD"),
+		results: []*s3.ListObjectsOutput{
+			&s3.ListObjectsOutput{
+				Contents: []*s3.Object{
+					fakeObject("key/a/b", "2006-01-02T15:04:05Z", 100, ""),
+					fakeObject("key/a/c", "2016-11-02T15:04:05Z", 200, ""),
+					fakeObject("key/a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
+					fakeObject("key/a/f", "2006-01-02T15:04:05Z", 100, ""),
+					fakeObject("key/a/g", "2016-11-02T15:04:05Z", 200, ""),
+					fakeObject("key/a/h", "2017-11-02T15:04:05Z", 300, "STANDARD"),
+					fakeObject("key/a/error", "2017-11-02T15:04:05Z", 300, "STANDARD"),
+					fakeObject("key/a/i", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 				},
-				IsTruncated: false,
+				IsTruncated: aws.Bool(false),
 			},
 		},
 	}
@@ -2315,7 +2184,7 @@ func TestCopyDownload(t *testing.T) {
 	}
 	for i, tCase := range testCases {
 		retCode, _ := testBosCli.copyDownload(tCase.srcPath, tCase.dstPath, tCase.downLoadTmp, tCase.recursive,
-			true, false)
+			true, false, 1, nil)
 		util.ExpectEqual("bos.go down I", i+1, t.Errorf, tCase.isSuc,
 			retCode == BOSCLI_OK)
 		util.ExpectEqual("bos.go down II", i+1, t.Errorf, tCase.out,
@@ -2558,6 +2427,7 @@ func TestUploadFileExecute(t *testing.T) {
 			dstObjectKey:     tCase.dstObjectKey,
 			srcIsDir:         tCase.isDir,
 			uploadFromStream: tCase.uploadFromStream,
+			concurrency:      5,
 		}
 		ret, code, err := testBosCli.uploadFileExecute(args, tCase.srcPath, tCase.storageClass,
 			true)
@@ -2634,8 +2504,8 @@ func TestCopyUpload(t *testing.T) {
 		},
 	}
 	for i, tCase := range testCases {
-		retCode, _ := testBosCli.copyUpload(tCase.srcPath, tCase.dstPath, tCase.storageClass, tCase.recursive,
-			true)
+		retCode, _ := testBosCli.copyUpload(tCase.srcPath, tCase.dstPath, tCase.storageClass, "",
+			tCase.recursive, true, 0, nil)
 
 		util.ExpectEqual("bos.go copyUpload I", i+1, t.Errorf, tCase.isSuc,
 			retCode == BOSCLI_OK)
@@ -2665,8 +2535,9 @@ type copyType struct {
 }
 
 func TestCopy(t *testing.T) {
+	// Copy exits when any file fails to upload, so there is no bad link
 	pathPrefix := "test_copy"
-	if err := initListFileCases(pathPrefix, true); err != nil {
+	if err := initListFileCases(pathPrefix, false); err != nil {
 		t.Errorf("TestCopyUploadExecute create file test dir and file failed")
 		return
 	}
@@ -2700,9 +2571,14 @@ func TestCopy(t *testing.T) {
 		},
 	}
 	for _, tCase := range testCases {
+		testBosCli.bosClient = tempFakeBosClient
+		if strings.Contains(tCase.srcPath, "cli-test") {
+			testBosCli.bosClient = remoteBosClient
+		}
 		testBosCli.Copy(tCase.srcPath, tCase.dstPath, tCase.storageClass, tCase.downLoadTmp,
-			tCase.recursive, true, true, true, false)
+			tCase.recursive, true, true, true, false, 0, nil)
 	}
+	testBosCli.bosClient = tempFakeBosClient
 }
 
 type syncPreProcessType struct {
@@ -3314,7 +3190,13 @@ func TestSyncExecute(t *testing.T) {
 		)
 		fmt.Printf("start id: %d\n", i+1)
 
+		bosClient := bosClientInterface(tempFakeBosClient)
+		if tCase.srcBucketName == "cli-test" {
+			bosClient = remoteBosClient
+		}
 		args := &syncArgs{
+			srcBosClient:         bosClient,
+			dstBosClient:         bosClient,
 			srcPath:              tCase.srcPath,
 			dstPath:              tCase.dstPath,
 			srcBucketName:        tCase.srcBucketName,
@@ -3406,7 +3288,8 @@ func TestSync(t *testing.T) {
 
 	for _, tCase := range testCases {
 		testBosCli.Sync(tCase.srcPath, tCase.dstPath, tCase.storageClass, tCase.downLoadTmp,
-			tCase.syncType, tCase.exclude, tCase.include, tCase.excludeTime, tCase.includeTime,
+			tCase.syncType, &FilterArgs{Exclude: tCase.exclude, Include: tCase.include,
+				ExcludeTime: tCase.excludeTime, IncludeTime: tCase.includeTime},
 			tCase.excludeDelete, tCase.concurrency, tCase.del, tCase.dryrun, tCase.yes, tCase.quiet,
 			tCase.disableBar, tCase.restart)
 	}
@@ -18,10 +18,20 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 )
 
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"bcecmd/boscmd"
+)
+
 // Create new BosApi
 func NewBosApi() *BosApi {
 	var (
@@ -36,11 +46,26 @@ func NewBosApi() *BosApi {
 	if err != nil {
 		bcecliAbnormalExistErr(err)
 	}
+	bosapiClient.handler = &cliHandler{}
 	return bosapiClient
 }
 
 type BosApi struct {
 	bosClient bosClientInterface
+	handler   handlerInterface
+}
+
+// Switch to the client of the remote which bosPath belongs to
+func (b *BosApi) useClientOfPath(bosPath string) {
+	remoteName, _, _ := splitRemotePath(bosPath)
+	if remoteName == "" {
+		return
+	}
+	client, err := remoteClientInit(remoteName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	b.bosClient = client
 }
 
 type putBucketAclArgs struct {
@@ -60,6 +85,7 @@ func (b *BosApi) PutBucketAcl(aclConfigPath, bosPath string, canned string) {
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCodeErr(retCode, err)
 	}
+	b.useClientOfPath(bosPath)
 
 	//executing
 	err, retCode = b.putBucketAclExecute(args.opType, args.acl, args.bucketName, canned)
@@ -91,15 +117,36 @@ func (b *BosApi) putBucketAclPreProcess(aclConfigPath, bosPath, canned string) (
 
 	// is canned acl?
 	if canned != "" {
-		if canned != "private" && canned != "public-read" && canned != "public-read-write" {
+		if !isStringIn(canned, bucketCannedAcls) {
 			return nil, fmt.Errorf("usupported canned ACL"), BOSCLI_PUT_ACL_CANNED_DONT_SUPPORT
 		}
 		return &putBucketAclArgs{bucketName: bucketName, opType: 2}, nil, BOSCLI_OK
+	} else if aclConfigPath != "" {
+		acl, err, retCode := readAclConfig(aclConfigPath)
+		if retCode != BOSCLI_OK {
+			return nil, err, retCode
+		}
+		return &putBucketAclArgs{bucketName: bucketName, acl: acl, opType: 1}, nil, BOSCLI_OK
 	} else {
 		return nil, nil, BOSCLI_PUT_ACL_CANNED_FILE_BOTH_EMPTY
 	}
 }
 
+// read ACL from file and validate it
+func readAclConfig(aclConfigPath string) ([]byte, error, BosCliErrorCode) {
+	acl, err := ioutil.ReadFile(aclConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err, BosCliErrorCode(boscmd.LOCAL_FILE_NOT_EXIST)
+		}
+		return nil, err, BOSCLI_EMPTY_CODE
+	}
+	if _, err := parseAclConfig(acl); err != nil {
+		return nil, err, BOSCLI_ACL_IS_INVALID
+	}
+	return acl, nil, BOSCLI_OK
+}
+
 // Executing put acl
 func (b *BosApi) putBucketAclExecute(opType int, aclJosn []byte, bucketName, canned string) (error,
 	BosCliErrorCode) {
@@ -131,6 +178,7 @@ func (b *BosApi) GetBucketAcl(bosPath string) {
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCode(retCode)
 	}
+	b.useClientOfPath(bosPath)
 
 	if err := b.getBucketAclExecute(bucketName); err != nil {
 		bcecliAbnormalExistErr(err)
@@ -161,21 +209,622 @@ func (b *BosApi) getBucketAclExecute(bucketName string) error {
 		return err
 	}
 
-	fmt.Println(ret.String())
+	// print ACL in the format of acl config file
+	out, err := formatApiJson(&s3.AccessControlPolicy{Owner: ret.Owner, Grants: ret.Grants})
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
 
-	// print ACL
-	/*
-		aclJosn, err := json.Marshal(ret)
-		if err != nil {
-			return err
+// Put object ACL from canned ACL or ACL file
+func (b *BosApi) PutObjectAcl(aclConfigPath, bosPath, objectName, canned string) {
+	bucketName, objectName, acl, err, retCode := b.putObjectAclPreProcess(aclConfigPath,
+		bosPath, objectName, canned)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if canned != "" {
+		err = b.bosClient.PutObjectAclFromCanned(bucketName, objectName, canned)
+	} else {
+		err = b.bosClient.PutObjectAclFromString(bucketName, objectName, string(acl))
+	}
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put object ACL preprocessing
+// RETURN: bucket name, object name, ACL read from file, error, error code
+func (b *BosApi) putObjectAclPreProcess(aclConfigPath, bosPath, objectName,
+	canned string) (string, string, []byte, error, BosCliErrorCode) {
+
+	bucketName, objectName, retCode := b.headObjectPreProcess(bosPath, objectName)
+	if retCode != BOSCLI_OK {
+		return "", "", nil, nil, retCode
+	}
+	if aclConfigPath != "" && canned != "" {
+		return "", "", nil, fmt.Errorf("Can't put acl from canned and file at the same time"),
+			BOSCLI_PUT_ACL_CANNED_FILE_SAME_TIME
+	} else if canned != "" {
+		if !isStringIn(canned, objectCannedAcls) {
+			return "", "", nil, fmt.Errorf("usupported canned ACL"),
+				BOSCLI_PUT_ACL_CANNED_DONT_SUPPORT
 		}
-		var out bytes.Buffer
-		json.Indent(&out, aclJosn, "", "  ")
-		fmt.Println(out.String())
-	*/
+		return bucketName, objectName, nil, nil, BOSCLI_OK
+	} else if aclConfigPath == "" {
+		return "", "", nil, nil, BOSCLI_PUT_ACL_CANNED_FILE_BOTH_EMPTY
+	}
+	acl, err, retCode := readAclConfig(aclConfigPath)
+	if retCode != BOSCLI_OK {
+		return "", "", nil, err, retCode
+	}
+	return bucketName, objectName, acl, nil, BOSCLI_OK
+}
+
+// Get object ACL
+func (b *BosApi) GetObjectAcl(bosPath, objectName string) {
+	bucketName, objectName, retCode := b.headObjectPreProcess(bosPath, objectName)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.GetObjectAcl(bucketName, objectName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	out, err := formatApiJson(&s3.AccessControlPolicy{Owner: ret.Owner, Grants: ret.Grants})
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	fmt.Println(out)
+}
+
+type putLifecycleArgs struct {
+	bucketName string
+	lifecycle  []byte
+}
+
+// Put lifecycle, print a template of lifecycle configuration when template is true
+func (b *BosApi) PutLifecycle(lifecycleConfigPath, bosPath string, template bool) {
+	if template {
+		fmt.Println(LIFECYCLE_TEMPLATE)
+		return
+	}
+
+	args, err, retCode := b.putLifecyclePreProcess(lifecycleConfigPath, bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutBucketLifecycleFromString(args.bucketName,
+		string(args.lifecycle)); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put lifecycle preprocessing, the configuration is validated locally
+func (b *BosApi) putLifecyclePreProcess(lifecycleConfigPath, bosPath string) (*putLifecycleArgs,
+	error, BosCliErrorCode) {
+
+	if lifecycleConfigPath == "" || bosPath == "" {
+		return nil, nil, BOSCLI_PUT_LIFECYCLE_NO_CONFIG_AND_BUCKET
+	}
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		return nil, nil, retCode
+	}
+
+	lifecycle, err := ioutil.ReadFile(lifecycleConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err, BosCliErrorCode(boscmd.LOCAL_FILE_NOT_EXIST)
+		}
+		return nil, err, BOSCLI_EMPTY_CODE
+	}
+	if _, err := parseLifecycleConfig(lifecycle); err != nil {
+		return nil, err, BOSCLI_LIFECYCLE_IS_INVALID
+	}
+	return &putLifecycleArgs{bucketName: bucketName, lifecycle: lifecycle}, nil, BOSCLI_OK
+}
+
+// Get lifecycle
+func (b *BosApi) GetLifecycle(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.getLifecycleExecute(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Get lifecycle execute, the configuration is printed in the format of put-lifecycle
+func (b *BosApi) getLifecycleExecute(bucketName string) error {
+	ret, err := b.bosClient.GetBucketLifecycle(bucketName)
+	if err != nil {
+		return err
+	}
+	out, err := formatApiJson(&s3.BucketLifecycleConfiguration{Rules: ret.Rules})
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
 	return nil
 }
 
+// Delete lifecycle
+func (b *BosApi) DeleteLifecycle(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.DeleteBucketLifecycle(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put logging, the access logs of bosPath are put to targetBosPath with targetPrefix
+func (b *BosApi) PutLogging(targetBosPath, targetPrefix, bosPath string) {
+	bucketName, targetName, retCode := b.putLoggingPreProcess(targetBosPath, targetPrefix,
+		bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if retCode, err := b.putLoggingCheckTarget(targetName); err != nil {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+
+	if err := b.putLoggingExecute(targetName, targetPrefix, bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put logging preprocessing
+// RETURN: bucket name, target bucket name, error code
+func (b *BosApi) putLoggingPreProcess(targetBosPath, targetPrefix, bosPath string) (string,
+	string, BosCliErrorCode) {
+
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		return "", "", retCode
+	}
+	if targetBosPath == "" {
+		return "", "", BOSCLI_PUT_LOG_NO_TARGET_BUCKET
+	}
+	targetName, retCode := b.getBucketAclPreProcess(targetBosPath)
+	if retCode == BOSCLI_BUCKETNAME_IS_EMPTY {
+		return "", "", BOSCLI_PUT_LOG_NO_TARGET_BUCKET
+	} else if retCode != BOSCLI_OK {
+		return "", "", retCode
+	}
+	return bucketName, targetName, BOSCLI_OK
+}
+
+// the target bucket must exist, otherwise logs are lost silently
+func (b *BosApi) putLoggingCheckTarget(targetName string) (BosCliErrorCode, error) {
+	ok, err := b.handler.doesBucketExist(b.bosClient, targetName)
+	if err != nil {
+		return BOSCLI_EMPTY_CODE, err
+	} else if !ok {
+		return BOSCLI_LOG_TARGET_BUCKET_NOT_EXIST, fmt.Errorf("target bucket %s don't exist!",
+			targetName)
+	}
+	return BOSCLI_OK, nil
+}
+
+func (b *BosApi) putLoggingExecute(targetName, targetPrefix, bucketName string) error {
+	return b.bosClient.PutBucketLogging(bucketName, targetName, targetPrefix)
+}
+
+// Get logging of bosPath, or the logging status of all buckets when all is true
+func (b *BosApi) GetLogging(bosPath string, all bool) {
+	if all {
+		b.getAllLogging()
+		return
+	}
+
+	bucketName, retCode := b.getLoggingPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.getLoggingExecute(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// get logging preprocessing
+func (b *BosApi) getLoggingPreProcess(bosPath string) (string, BosCliErrorCode) {
+	return b.getBucketAclPreProcess(bosPath)
+}
+
+func (b *BosApi) getLoggingExecute(bucketName string) error {
+	ret, err := b.bosClient.GetBucketLogging(bucketName)
+	if err != nil {
+		return err
+	}
+	out, err := formatApiJson(ret)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+// Print whether logging is enabled of every bucket, exit abnormally when logging of any bucket
+// is disabled or can't be got.
+func (b *BosApi) getAllLogging() {
+	buckets, err := b.bosClient.ListBuckets()
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+
+	disabled, failed := 0, 0
+	for _, bucket := range buckets.Buckets {
+		bucketName := aws.StringValue(bucket.Name)
+		ret, err := b.bosClient.GetBucketLogging(bucketName)
+		if err != nil {
+			fmt.Printf("%s  error: %s\n", bucketName, getErrorMsg(err))
+			failed++
+		} else if enabled := ret.LoggingEnabled; enabled == nil {
+			fmt.Printf("%s  disabled\n", bucketName)
+			disabled++
+		} else {
+			fmt.Printf("%s  enabled => %s%s/%s\n", bucketName, BOS_PATH_PREFIX,
+				aws.StringValue(enabled.TargetBucket), aws.StringValue(enabled.TargetPrefix))
+		}
+	}
+	fmt.Printf("[%d] buckets, [%d] logging disabled, [%d] failed.\n", len(buckets.Buckets),
+		disabled, failed)
+	if disabled > 0 || failed > 0 {
+		bcecliAbnormalExistCode(BOSCLI_EMPTY_CODE)
+	}
+}
+
+// Delete logging
+func (b *BosApi) DeleteLogging(bosPath string) {
+	bucketName, retCode := b.deleteLoggingPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.DeleteBucketLogging(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// delete logging preprocessing
+func (b *BosApi) deleteLoggingPreProcess(bosPath string) (string, BosCliErrorCode) {
+	return b.getBucketAclPreProcess(bosPath)
+}
+
+// Put bucket policy, print a policy of template for the bucket when template isn't empty
+func (b *BosApi) PutBucketPolicy(policyConfigPath, bosPath, template string) {
+	if template != "" {
+		bucketName := ""
+		if bosPath != "" {
+			bucketName, _ = splitBosBucketKey(bosPath)
+		}
+		policy, err := policyTemplate(template, bucketName)
+		if err != nil {
+			bcecliAbnormalExistErr(err)
+		}
+		fmt.Println(policy)
+		return
+	}
+
+	bucketName, policy, err, retCode := b.putBucketPolicyPreProcess(policyConfigPath, bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutBucketPolicy(bucketName, string(policy)); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put bucket policy preprocessing, the syntax of policy is checked locally
+// RETURN: bucket name, policy, error, error code
+func (b *BosApi) putBucketPolicyPreProcess(policyConfigPath, bosPath string) (string, []byte,
+	error, BosCliErrorCode) {
+
+	if policyConfigPath == "" || bosPath == "" {
+		return "", nil, nil, BOSCLI_PUT_POLICY_NO_CONFIG_AND_BUCKET
+	}
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		return "", nil, nil, retCode
+	}
+
+	policy, err := ioutil.ReadFile(policyConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, err, BosCliErrorCode(boscmd.LOCAL_FILE_NOT_EXIST)
+		}
+		return "", nil, err, BOSCLI_EMPTY_CODE
+	}
+	if err := checkBucketPolicy(policy); err != nil {
+		return "", nil, err, BOSCLI_POLICY_IS_INVALID
+	}
+	return bucketName, policy, nil, BOSCLI_OK
+}
+
+// Get bucket policy
+func (b *BosApi) GetBucketPolicy(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	policy, err := b.bosClient.GetBucketPolicy(bucketName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(policy), "", "  "); err != nil {
+		// print as it is when server returns something else
+		fmt.Println(policy)
+		return
+	}
+	fmt.Println(out.String())
+}
+
+// Delete bucket policy
+func (b *BosApi) DeleteBucketPolicy(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.DeleteBucketPolicy(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put bucket CORS from a rule file in json or xml
+func (b *BosApi) PutBucketCors(corsConfigPath, bosPath string) {
+	bucketName, config, err, retCode := b.putBucketCorsPreProcess(corsConfigPath, bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutBucketCors(bucketName, config); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put bucket CORS preprocessing, the rules are validated locally
+// RETURN: bucket name, CORS configuration, error, error code
+func (b *BosApi) putBucketCorsPreProcess(corsConfigPath, bosPath string) (string,
+	*s3.CORSConfiguration, error, BosCliErrorCode) {
+
+	if corsConfigPath == "" || bosPath == "" {
+		return "", nil, nil, BOSCLI_PUT_CORS_NO_CONFIG_AND_BUCKET
+	}
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		return "", nil, nil, retCode
+	}
+
+	cors, err := ioutil.ReadFile(corsConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, err, BosCliErrorCode(boscmd.LOCAL_FILE_NOT_EXIST)
+		}
+		return "", nil, err, BOSCLI_EMPTY_CODE
+	}
+	config, err := parseCorsConfig(cors)
+	if err != nil {
+		return "", nil, err, BOSCLI_CORS_IS_INVALID
+	}
+	return bucketName, config, nil, BOSCLI_OK
+}
+
+// Get bucket CORS, the rules are printed in the json format of put-bucket-cors
+func (b *BosApi) GetBucketCors(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.GetBucketCors(bucketName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	out, err := formatApiJson(&s3.CORSConfiguration{CORSRules: ret.CORSRules})
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	fmt.Println(out)
+}
+
+// Delete bucket CORS
+func (b *BosApi) DeleteBucketCors(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.DeleteBucketCors(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put bucket tagging, tags is "k1=v1,k2=v2" and replaces all the tags of bucket
+func (b *BosApi) PutBucketTagging(bosPath, tags string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	tagSet, err := parseTags(tags, BUCKET_MAX_TAG_COUNT)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_TAGS_IS_INVALID, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutBucketTagging(bucketName, tagSet); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Get bucket tagging, tags are printed as "key=value" lines
+func (b *BosApi) GetBucketTagging(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.GetBucketTagging(bucketName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	if len(ret.TagSet) > 0 {
+		fmt.Println(formatTags(ret.TagSet))
+	}
+}
+
+// Delete bucket tagging
+func (b *BosApi) DeleteBucketTagging(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.DeleteBucketTagging(bucketName); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Put object tagging, tags is "k1=v1,k2=v2" and replaces all the tags of object
+func (b *BosApi) PutObjectTagging(bosPath, objectName, tags string) {
+	bucketName, objectName, retCode := b.headObjectPreProcess(bosPath, objectName)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	tagSet, err := parseTags(tags, OBJECT_MAX_TAG_COUNT)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_TAGS_IS_INVALID, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutObjectTagging(bucketName, objectName, tagSet); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Get object tagging, tags are printed as "key=value" lines
+func (b *BosApi) GetObjectTagging(bosPath, objectName string) {
+	bucketName, objectName, retCode := b.headObjectPreProcess(bosPath, objectName)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.GetObjectTagging(bucketName, objectName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	if len(ret.TagSet) > 0 {
+		fmt.Println(formatTags(ret.TagSet))
+	}
+}
+
+// Put bucket versioning, status is Enabled or Suspended. Versioning can't be disabled once it
+// is enabled, it can only be suspended.
+func (b *BosApi) PutBucketVersioning(bosPath, status string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	if status != s3.BucketVersioningStatusEnabled && status != s3.BucketVersioningStatusSuspended {
+		bcecliAbnormalExistCodeErr(BOSCLI_VERSIONING_STATUS_IS_INVALID,
+			fmt.Errorf("unsupported versioning status '%s'", status))
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutBucketVersioning(bucketName, status); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Get bucket versioning, the status is empty when versioning has never been enabled
+func (b *BosApi) GetBucketVersioning(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.GetBucketVersioning(bucketName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	out, err := formatApiJson(ret)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	fmt.Println(out)
+}
+
+// Put bucket default encryption, sse is AES256 or aws:kms, kmsKeyId can only be used with
+// aws:kms.
+func (b *BosApi) PutBucketEncryption(bosPath, sse, kmsKeyId string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	config, err := newBucketEncryption(sse, kmsKeyId)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_SSE_IS_INVALID, err)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.PutBucketEncryption(bucketName, config); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// Get bucket default encryption
+func (b *BosApi) GetBucketEncryption(bosPath string) {
+	bucketName, retCode := b.getBucketAclPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.GetBucketEncryption(bucketName)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	out, err := formatApiJson(ret.ServerSideEncryptionConfiguration)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	fmt.Println(out)
+}
+
 // Put storage class
 // must have bucket-name and storage-class
 func (b *BosApi) PutBucketStorageClass(bosPath, storageClass string) {
@@ -184,6 +833,7 @@ func (b *BosApi) PutBucketStorageClass(bosPath, storageClass string) {
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCode(retCode)
 	}
+	b.useClientOfPath(bosPath)
 
 	// put storage class
 	if err := b.putBucketStorageClassExecute(bucketName, storageClass); err != nil {
@@ -229,6 +879,7 @@ func (b *BosApi) GetBucketStorageClass(bosPath string) {
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCode(retCode)
 	}
+	b.useClientOfPath(bosPath)
 
 	// get storage class
 	err := b.getBucketStorageClassExecute(bucketName)
@@ -266,12 +917,14 @@ func (b *BosApi) getBucketStorageClassExecute(bucketName string) error {
 }
 
 // for command get-object-meta
-func (b *BosApi) HeadObject(bucketName, objectName string) {
+func (b *BosApi) HeadObject(bucketName, objectName, versionId string) {
+	b.useClientOfPath(bucketName)
 // check bucket name
 	bucketName, objectName, retCode := b.headObjectPreProcess(bucketName, objectName)
 	if retCode != BOSCLI_OK {
 		bcecliAbnormalExistCode(retCode)
 	}
+	bindVersion(bucketName, objectName, versionId)
 
 	// get storage class
 	err := b.headObjectExecute(bucketName, objectName)
@@ -312,7 +965,85 @@ func (b *BosApi) headObjectExecute(bucketName, objectName string) error {
 	}
 
 	// print object information
-	fmt.Printf(ret.GoString())
+	fmt.Print(ret.GoString())
 	return nil
 }
 
+// for command list-multipart-uploads
+func (b *BosApi) ListMultipartUploads(bosPath, prefix, keyMarker, uploadIdMarker string,
+	maxUploads int) {
+
+	// check bucket name
+	bucketName, retCode := b.getBucketStorageClassPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.ListMultipartUploads(bucketName, prefix, keyMarker, uploadIdMarker,
+		maxUploads)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	out, err := formatApiJson(ret)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	fmt.Println(out)
+}
+
+// for command list-parts
+func (b *BosApi) ListParts(bosPath, objectName, uploadId string, partNumberMarker int64,
+	maxParts int) {
+
+	bucketName, objectName, uploadId, retCode := b.multipartUploadPreProcess(bosPath,
+		objectName, uploadId)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	ret, err := b.bosClient.ListParts(bucketName, objectName, uploadId, partNumberMarker,
+		maxParts)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	out, err := formatApiJson(ret)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	fmt.Println(out)
+}
+
+// for command abort-multipart-upload
+func (b *BosApi) AbortMultipartUpload(bosPath, objectName, uploadId string) {
+	bucketName, objectName, uploadId, retCode := b.multipartUploadPreProcess(bosPath,
+		objectName, uploadId)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCode(retCode)
+	}
+	b.useClientOfPath(bosPath)
+
+	if err := b.bosClient.AbortMultipartUpload(bucketName, objectName, uploadId); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// check bucket name, object name and upload id of a multipart upload
+func (b *BosApi) multipartUploadPreProcess(bosPath, objectName, uploadId string) (string,
+	string, string, BosCliErrorCode) {
+
+	bucketName, retCode := b.getBucketStorageClassPreProcess(bosPath)
+	if retCode != BOSCLI_OK {
+		return "", "", "", retCode
+	}
+	objectName = strings.TrimSpace(objectName)
+	if objectName == "" {
+		return "", "", "", BOSCLI_OBJECTKEY_IS_EMPTY
+	}
+	uploadId = strings.TrimSpace(uploadId)
+	if uploadId == "" {
+		return "", "", "", BOSCLI_UPLOAD_ID_IS_EMPTY
+	}
+	return bucketName, objectName, uploadId, BOSCLI_OK
+}
@@ -15,21 +15,17 @@ package boscli
 import (
 	"fmt"
 	"io/ioutil"
-	// 	"net/http"
 	"os"
-	// 	"runtime"
-	// 	"path/filepath"
-	// 	"strconv"
-	// 	"strings"
 	"testing"
 )
 
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
 import (
 	"bcecmd/boscmd"
-	// 	"bceconf"
-	// 	"github.com/baidubce/bce-sdk-go/services/bos"
-	// 	"github.com/baidubce/bce-sdk-go/bce"
-	"github.com/baidubce/bce-sdk-go/services/bos/api"
 	"utils/util"
 )
 
@@ -40,6 +36,7 @@ var (
 func init() {
 	bosapi = NewBosApi()
 	bosapi.bosClient = &fakeBosClientForBos{}
+	bosapi.handler = &fakeCliHandler{}
 }
 
 // Fake of PutBucketLifecycle
@@ -47,23 +44,23 @@ func (b *fakeBosClientForBos) PutBucketLifecycleFromString(bucket, lifecycle str
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket + lifecycle)
+	return fmt.Errorf("%s", bucket+lifecycle)
 }
 
 // Fake of GetBucketLifecycle
-func (b *fakeBosClientForBos) GetBucketLifecycle(bucket string) (*api.GetBucketLifecycleResult,
-	error) {
+func (b *fakeBosClientForBos) GetBucketLifecycle(bucket string) (
+	*s3.GetBucketLifecycleConfigurationOutput, error) {
 	if bucket == "success" {
-		return &api.GetBucketLifecycleResult{
-			Rule: []api.LifecycleRuleType{
-				api.LifecycleRuleType{
-					Id:     "123",
-					Status: "enabled",
+		return &s3.GetBucketLifecycleConfigurationOutput{
+			Rules: []*s3.LifecycleRule{
+				&s3.LifecycleRule{
+					ID:     aws.String("123"),
+					Status: aws.String(s3.ExpirationStatusEnabled),
 				},
 			},
 		}, nil
 	}
-	return nil, fmt.Errorf(bucket)
+	return nil, fmt.Errorf("%s", bucket)
 }
 
 // Fake of DeleteBucketLifecycle
@@ -71,28 +68,28 @@ func (b *fakeBosClientForBos) DeleteBucketLifecycle(bucket string) error {
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket)
+	return fmt.Errorf("%s", bucket)
 }
 
-// Fake of PutBucketLoggingFromStruct
-func (b *fakeBosClientForBos) PutBucketLoggingFromStruct(bucket string,
-	obj *api.PutBucketLoggingArgs) error {
+// Fake of PutBucketLogging
+func (b *fakeBosClientForBos) PutBucketLogging(bucket, targetBucket, targetPrefix string) error {
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket + obj.TargetBucket + obj.TargetPrefix)
+	return fmt.Errorf("%s", bucket+targetBucket+targetPrefix)
 }
 
 // Fake of GetBucketLogging
-func (b *fakeBosClientForBos) GetBucketLogging(bucket string) (*api.GetBucketLoggingResult, error) {
+func (b *fakeBosClientForBos) GetBucketLogging(bucket string) (*s3.GetBucketLoggingOutput, error) {
 	if bucket == "success" {
-		return &api.GetBucketLoggingResult{
-			Status:       "enabled",
-			TargetBucket: "success1",
-			TargetPrefix: "log1",
+		return &s3.GetBucketLoggingOutput{
+			LoggingEnabled: &s3.LoggingEnabled{
+				TargetBucket: aws.String("success1"),
+				TargetPrefix: aws.String("log1"),
+			},
 		}, nil
 	}
-	return nil, fmt.Errorf(bucket)
+	return nil, fmt.Errorf("%s", bucket)
 }
 
 // Fake of DeleteBucketLogging
@@ -100,7 +97,7 @@ func (b *fakeBosClientForBos) DeleteBucketLogging(bucket string) error {
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket)
+	return fmt.Errorf("%s", bucket)
 }
 
 // Fake of PutBucketStorageclass
@@ -108,7 +105,7 @@ func (b *fakeBosClientForBos) PutBucketStorageclass(bucket, storageClass string)
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket + storageClass)
+	return fmt.Errorf("%s", bucket+storageClass)
 }
 
 // Fake of GetBucketStorageclass
@@ -116,7 +113,7 @@ func (b *fakeBosClientForBos) GetBucketStorageclass(bucket string) (string, erro
 	if bucket == "success" {
 		return "COLD", nil
 	}
-	return "", fmt.Errorf(bucket)
+	return "", fmt.Errorf("%s", bucket)
 }
 
 // Fake of PutBucketAclFromCanned
@@ -124,7 +121,7 @@ func (b *fakeBosClientForBos) PutBucketAclFromCanned(bucket, cannedAcl string) e
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket + cannedAcl)
+	return fmt.Errorf("%s", bucket+cannedAcl)
 }
 
 // Fake of PutBucketAcl
@@ -132,20 +129,20 @@ func (b *fakeBosClientForBos) PutBucketAclFromString(bucket, acl string) error {
 	if bucket == "success" {
 		return nil
 	}
-	return fmt.Errorf(bucket + acl)
+	return fmt.Errorf("%s", bucket+acl)
 }
 
 // Fake of GetBucketAcl
-func (b *fakeBosClientForBos) GetBucketAcl(bucket string) (*api.GetBucketAclResult, error) {
+func (b *fakeBosClientForBos) GetBucketAcl(bucket string) (*s3.GetBucketAclOutput, error) {
 	if bucket == "success" {
-		return &api.GetBucketAclResult{
-			AccessControlList: []api.GrantType{},
-			Owner: api.AclOwnerType{
-				Id: "123",
+		return &s3.GetBucketAclOutput{
+			Grants: []*s3.Grant{},
+			Owner: &s3.Owner{
+				ID: aws.String("123"),
 			},
 		}, nil
 	}
-	return nil, fmt.Errorf(bucket)
+	return nil, fmt.Errorf("%s", bucket)
 }
 
 type putBucketAclPreProcessType struct {
@@ -160,7 +157,7 @@ type putBucketAclPreProcessType struct {
 
 func TestPutBucketAclPreProcess(t *testing.T) {
 	fd, fileName, err := util.CreateAnRandomFileWithContent("%s",
-		`{"accessControlList": [{"grantee": [{"id": "*"}],"permission": ["READ"]}]}`)
+		`{"Grants": [{"Grantee": {"ID": "123"}, "Permission": "READ"}]}`)
 	if err != nil {
 		t.Errorf("create acl test file failed! error: %v", err)
 		return
@@ -222,13 +219,13 @@ func TestPutBucketAclPreProcess(t *testing.T) {
 		putBucketAclPreProcessType{
 			bosPath:    "bos:/bucket",
 			configPath: "./bosapi.go",
-			code:       BOSCLI_EMPTY_CODE,
+			code:       BOSCLI_ACL_IS_INVALID,
 		},
 		// 9
 		putBucketAclPreProcessType{
 			bosPath:    "bos:/bucket",
 			configPath: "./bosapi.go",
-			code:       BOSCLI_EMPTY_CODE,
+			code:       BOSCLI_ACL_IS_INVALID,
 		},
 		// 10
 		putBucketAclPreProcessType{
@@ -275,7 +272,7 @@ type putBucketAclType struct {
 
 func TestPutBucketAcl(t *testing.T) {
 	fd, fileName, err := util.CreateAnRandomFileWithContent("%s",
-		`{"accessControlList": [{"grantee": [{"id": "*"}],"permission": ["READ"]}]}`)
+		`{"Grants": [{"Grantee": {"ID": "123"}, "Permission": "READ"}]}`)
 	if err != nil {
 		t.Errorf("create acl test file failed! error: %v", err)
 		return
@@ -311,7 +308,7 @@ type putBucketAclExecuteType struct {
 }
 
 func TestPutBucketAclExecute(t *testing.T) {
-	acl := `{"accessControlList": [{"grantee": [{"id": "*"}],"permission": ["READ"]}]}`
+	acl := `{"Grants": [{"Grantee": {"ID": "123"}, "Permission": "READ"}]}`
 
 	testCases := []putBucketAclExecuteType{
 		// 1
@@ -438,42 +435,19 @@ func TestGetBucketAcl(t *testing.T) {
 var (
 	lifecycle = `
 		{
-			"rule": [
-			{
-				 "status": "enabled",
-		 		 "action": {
-		 				"name": "Transition",
-		 				"storageClass": "STANDARD_IA"
-		 			},
-		 			"resource": [
-		 					"liupeng-bj/*"
-		 			],
-		 			"id": "sample-rule-transition-1",
-		 			"condition": {
-		 				"time": {
-		 					"dateGreaterThan": "$(lastModified)+P180D"
-						}
-					},
-					"test":"yes"
-			  },
-		{
-				 "status": "enabled",
-		 		 "action": {
-		 				"name": "Transition",
-		 				"storageClass": "COLD"
-		 			},
-		 			"resource": [
-		 					"liupeng-bj/*"
-		 			],
-		 			"id": "sample-rule-transition-2",
-		 			"condition": {
-		 				"time": {
-		 					"dateGreaterThan": "$(lastModified)+P300D"
-						}
-					},
-					"index":"index.html"
-			  }
-		
+			"Rules": [
+				{
+					"ID": "sample-rule-transition-1",
+					"Status": "Enabled",
+					"Filter": {"Prefix": "liupeng-bj/"},
+					"Transitions": [{"Days": 180, "StorageClass": "STANDARD_IA"}]
+				},
+				{
+					"ID": "sample-rule-transition-2",
+					"Status": "Enabled",
+					"Filter": {"Prefix": "liupeng-bj/"},
+					"Transitions": [{"Days": 300, "StorageClass": "GLACIER"}]
+				}
 			]
 		}
 	`
@@ -482,7 +456,6 @@ var (
 type putLifecyclePreProcessType struct {
 	configPath string
 	bosPath    string
-	template   bool
 	bucketName string
 	lifecycle  []byte
 	code       BosCliErrorCode
@@ -508,18 +481,21 @@ func TestPutLifecyclePreProcess(t *testing.T) {
 	testCases := []putLifecyclePreProcessType{
 		// 1
 		putLifecyclePreProcessType{
-			bosPath: "/liup",
-			code:    BOSCLI_BOSPATH_IS_INVALID,
+			bosPath:    "/liup",
+			configPath: fileName,
+			code:       BOSCLI_BOSPATH_IS_INVALID,
 		},
 		// 2
 		putLifecyclePreProcessType{
-			bosPath: "liup/object",
-			code:    BOSCLI_BUCKETNAME_CONTAIN_OBJECTNAME,
+			bosPath:    "liup/object",
+			configPath: fileName,
+			code:       BOSCLI_BUCKETNAME_CONTAIN_OBJECTNAME,
 		},
 		// 3
 		putLifecyclePreProcessType{
-			bosPath: "bos://",
-			code:    BOSCLI_BUCKETNAME_IS_EMPTY,
+			bosPath:    "bos://",
+			configPath: fileName,
+			code:       BOSCLI_BUCKETNAME_IS_EMPTY,
 		},
 		// 4
 		putLifecyclePreProcessType{
@@ -531,7 +507,7 @@ func TestPutLifecyclePreProcess(t *testing.T) {
 		putLifecyclePreProcessType{
 			bosPath:    "bos:/bucket",
 			configPath: "./bosapi.go",
-			code:       BOSCLI_EMPTY_CODE,
+			code:       BOSCLI_LIFECYCLE_IS_INVALID,
 		},
 		// 6
 		putLifecyclePreProcessType{
@@ -549,8 +525,7 @@ func TestPutLifecyclePreProcess(t *testing.T) {
 	}
 
 	for i, tCase := range testCases {
-		ret, _, code := bosapi.putLifecyclePreProcess(tCase.configPath, tCase.bosPath,
-			tCase.template)
+		ret, _, code := bosapi.putLifecyclePreProcess(tCase.configPath, tCase.bosPath)
 		util.ExpectEqual("bosapi.go putLifecyclePreProcess I", i+1, t.Errorf, tCase.code, code)
 		if code == BOSCLI_OK {
 			util.ExpectEqual("bosapi.go putLifecyclePreProcess II", i+1, t.Errorf, tCase.bucketName,
@@ -602,87 +577,6 @@ func TestPutLifecycle(t *testing.T) {
 	}
 }
 
-type putLifecycleExecuteType struct {
-	lifecycleJosn []byte
-	bucketName    string
-	template      bool
-	code          BosCliErrorCode
-	err           string
-}
-
-func TestPutLifecycleExecute(t *testing.T) {
-	testCases := []putLifecycleExecuteType{
-		// 1
-		putLifecycleExecuteType{
-			bucketName:    "success",
-			lifecycleJosn: []byte(lifecycle),
-			code:          BOSCLI_OK,
-		},
-		// 2
-		putLifecycleExecuteType{
-			template: true,
-			code:     BOSCLI_OK,
-		},
-		// 3
-		putLifecycleExecuteType{
-			bucketName:    "error",
-			lifecycleJosn: []byte(lifecycle),
-			err:           "error" + lifecycle,
-			code:          BOSCLI_EMPTY_CODE,
-		},
-	}
-
-	for i, tCase := range testCases {
-		err, code := bosapi.putLifecycleExecute(tCase.lifecycleJosn, tCase.bucketName,
-			tCase.template)
-		util.ExpectEqual("bosapi.go putLifecycleExecute I", i+1, t.Errorf, tCase.code, code)
-		if code != BOSCLI_OK {
-			util.ExpectEqual("bosapi.go putLifecycleExecute II", i+1, t.Errorf, tCase.err,
-				err.Error())
-		}
-	}
-}
-
-type getLifecyclePreProcessType struct {
-	bosPath    string
-	bucketName string
-	code       BosCliErrorCode
-}
-
-func TestGetLifecyclePreProcess(t *testing.T) {
-	testCases := []getLifecyclePreProcessType{
-		// 1
-		getLifecyclePreProcessType{
-			bosPath: "/liup",
-			code:    BOSCLI_BOSPATH_IS_INVALID,
-		},
-		// 2
-		getLifecyclePreProcessType{
-			bosPath: "liup/object",
-			code:    BOSCLI_BUCKETNAME_CONTAIN_OBJECTNAME,
-		},
-		// 3
-		getLifecyclePreProcessType{
-			bosPath: "bos://",
-			code:    BOSCLI_BUCKETNAME_IS_EMPTY,
-		},
-		// 4
-		getLifecyclePreProcessType{
-			bosPath:    "bos:/bucket",
-			bucketName: "bucket",
-			code:       BOSCLI_OK,
-		},
-	}
-	for i, tCase := range testCases {
-		ret, code := bosapi.getLifecyclePreProcess(tCase.bosPath)
-		util.ExpectEqual("bosapi.go getLifecyclePreProcess I", i+1, t.Errorf, tCase.code, code)
-		if tCase.code == BOSCLI_OK {
-			util.ExpectEqual("bosapi.go getLifecyclePreProcess II", i+1, t.Errorf, tCase.bucketName,
-				ret)
-		}
-	}
-}
-
 type getLifecycleExecuteType struct {
 	bucketName string
 	err        string
@@ -720,79 +614,6 @@ func TestGetLifecycle(t *testing.T) {
 	bosapi.GetLifecycle("success")
 }
 
-type deleteLifecyclePreProcessType struct {
-	bosPath    string
-	bucketName string
-	code       BosCliErrorCode
-}
-
-func TestDeleteLifecyclePreProcess(t *testing.T) {
-	testCases := []deleteLifecyclePreProcessType{
-		// 1
-		deleteLifecyclePreProcessType{
-			bosPath: "/liup",
-			code:    BOSCLI_BOSPATH_IS_INVALID,
-		},
-		// 2
-		deleteLifecyclePreProcessType{
-			bosPath: "liup/object",
-			code:    BOSCLI_BUCKETNAME_CONTAIN_OBJECTNAME,
-		},
-		// 3
-		deleteLifecyclePreProcessType{
-			bosPath: "bos://",
-			code:    BOSCLI_BUCKETNAME_IS_EMPTY,
-		},
-		// 4
-		deleteLifecyclePreProcessType{
-			bosPath:    "bos:/bucket",
-			bucketName: "bucket",
-			code:       BOSCLI_OK,
-		},
-	}
-	for i, tCase := range testCases {
-		ret, code := bosapi.deleteLifecyclePreProcess(tCase.bosPath)
-		util.ExpectEqual("bosapi.go deleteLifecyclePreProcess I", i+1, t.Errorf, tCase.code, code)
-		if tCase.code == BOSCLI_OK {
-			util.ExpectEqual("bosapi.go deleteLifecyclePreProcess II", i+1, t.Errorf,
-				tCase.bucketName, ret)
-		}
-	}
-}
-
-type deleteLifecycleExecuteType struct {
-	bucketName string
-	err        string
-}
-
-func TestDeleteLifecycleExecute(t *testing.T) {
-	testCases := []deleteLifecycleExecuteType{
-		// 1
-		deleteLifecycleExecuteType{
-			bucketName: "success",
-		},
-		// 2
-		deleteLifecycleExecuteType{
-			bucketName: "error",
-			err:        "error",
-		},
-		// 3
-		deleteLifecycleExecuteType{
-			bucketName: "error1",
-			err:        "error1",
-		},
-	}
-	for i, tCase := range testCases {
-		err := bosapi.deleteLifecycleExecute(tCase.bucketName)
-		util.ExpectEqual("bosapi.go deleteLifecycleExecute I", i+1, t.Errorf, tCase.err == "",
-			err == nil)
-		if err != nil {
-			util.ExpectEqual("bosapi.go deleteLifecycleExecute II", i+1, t.Errorf, tCase.err,
-				err.Error())
-		}
-	}
-}
-
 func TestDeleteLifecycle(t *testing.T) {
 	bosapi.DeleteLifecycle("success")
 }
@@ -856,6 +677,21 @@ func TestPutLoggingPreProcess(t *testing.T) {
 			bucketName:    "bucket",
 			targetName:    "bucket1",
 		},
+		// 9 no target bucket
+		putLoggingPreProcessType{
+			bosPath:      "bos:/bucket",
+			targetPrefix: "log",
+			code:         BOSCLI_PUT_LOG_NO_TARGET_BUCKET,
+		},
+		// 10 target prefix isn't checked here
+		putLoggingPreProcessType{
+			bosPath:       "bos:/bucket",
+			targetBosPath: "bos:/bucket1",
+			targetPrefix:  "log/",
+			code:          BOSCLI_OK,
+			bucketName:    "bucket",
+			targetName:    "bucket1",
+		},
 	}
 
 	for i, tCase := range testCases {
@@ -871,6 +707,32 @@ func TestPutLoggingPreProcess(t *testing.T) {
 	}
 }
 
+type putLoggingCheckTargetType struct {
+	targetName string
+	code       BosCliErrorCode
+	isSuc      bool
+}
+
+func TestPutLoggingCheckTarget(t *testing.T) {
+	testCases := []putLoggingCheckTargetType{
+		//1
+		putLoggingCheckTargetType{targetName: "success", code: BOSCLI_OK, isSuc: true},
+		//2 target bucket doesn't exist
+		putLoggingCheckTargetType{
+			targetName: "notExist",
+			code:       BOSCLI_LOG_TARGET_BUCKET_NOT_EXIST,
+		},
+		//3 failed to check target bucket
+		putLoggingCheckTargetType{targetName: "error", code: BOSCLI_EMPTY_CODE},
+	}
+	for i, tCase := range testCases {
+		code, err := bosapi.putLoggingCheckTarget(tCase.targetName)
+		util.ExpectEqual("bosapi.go putLoggingCheckTarget I", i+1, t.Errorf, tCase.code, code)
+		util.ExpectEqual("bosapi.go putLoggingCheckTarget II", i+1, t.Errorf, tCase.isSuc,
+			err == nil)
+	}
+}
+
 type putLoggingType struct {
 	targetBosPath string
 	targetPrefix  string
@@ -1013,7 +875,7 @@ func TestGetLoggingExecute(t *testing.T) {
 }
 
 func TestGetLogging(t *testing.T) {
-	bosapi.GetLogging("success")
+	bosapi.GetLogging("success", false)
 }
 
 type deleteLoggingPreProcessType struct {
@@ -1056,39 +918,6 @@ func TestDeleteLoggingPreProcess(t *testing.T) {
 	}
 }
 
-type deleteLoggingExecuteType struct {
-	bucketName string
-	err        string
-}
-
-func TestDeleteLoggingExecute(t *testing.T) {
-	testCases := []deleteLoggingExecuteType{
-		// 1
-		deleteLoggingExecuteType{
-			bucketName: "success",
-		},
-		// 2
-		deleteLoggingExecuteType{
-			bucketName: "error",
-			err:        "error",
-		},
-		// 3
-		deleteLoggingExecuteType{
-			bucketName: "errorlogging",
-			err:        "errorlogging",
-		},
-	}
-	for i, tCase := range testCases {
-		err := bosapi.deleteLoggingExecute(tCase.bucketName)
-		util.ExpectEqual("bosapi.go deleteLoggingExecute I", i+1, t.Errorf, tCase.err == "",
-			err == nil)
-		if err != nil {
-			util.ExpectEqual("bosapi.go deleteLoggingExecute II", i+1, t.Errorf, tCase.err,
-				err.Error())
-		}
-	}
-}
-
 func TestDeleteLogging(t *testing.T) {
 	bosapi.DeleteLogging("success")
 }
@@ -0,0 +1,285 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the management of breakpoint records of multipart transfers.
+
+package boscli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+import (
+	"bcecmd/boscmd"
+	"bceconf"
+	"utils/util"
+)
+
+// breakPointEntry is a breakpoint record file under bceconf.MultiuploadFolder
+type breakPointEntry struct {
+	id     string
+	path   string
+	record *BreakPointRecord // nil when the record is corrupt
+	err    error             // why the record is corrupt
+	mtime  time.Time         // modify time of record file
+}
+
+// the kind of transfer of the record
+func (e *breakPointEntry) kind() string {
+	if e.record == nil {
+		return "unknown"
+	}
+	switch {
+	case e.record.SrcType == IS_LOCAL && e.record.DstType == IS_BOS:
+		return "upload"
+	case e.record.SrcType == IS_BOS && e.record.DstType == IS_LOCAL:
+		return "download"
+	case e.record.SrcType == IS_BOS && e.record.DstType == IS_BOS:
+		return "copy"
+	}
+	return "unknown"
+}
+
+// the time since the record was updated last time
+func (e *breakPointEntry) age(now time.Time) time.Duration {
+	updated := e.mtime
+	if e.record != nil && e.record.RecordTime > 0 {
+		updated = time.Unix(e.record.RecordTime, 0)
+	}
+	return now.Sub(updated)
+}
+
+// a record is expired when it isn't updated in expiration days, a corrupt record is always
+// expired as it can't be resumed
+func (e *breakPointEntry) expired(now time.Time, expiration int) bool {
+	return e.record == nil || e.age(now) > time.Duration(expiration)*24*time.Hour
+}
+
+// List breakpoint records sorted by id, temp files of records are ignored.
+func listBreakPointEntries(folder string) ([]*breakPointEntry, error) {
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]*breakPointEntry, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasSuffix(file.Name(), BREAK_POINT_TEMP_SUFFIX) {
+			continue
+		}
+		entry := &breakPointEntry{
+			id:    file.Name(),
+			path:  filepath.Join(folder, file.Name()),
+			mtime: file.ModTime(),
+		}
+		entry.record, entry.err = readBreakPointRecord(entry.path)
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	return entries, nil
+}
+
+// get the expiration days of breakpoint records
+func getBreakPointExpiration() int {
+	expiration, ok := bceconf.ServerConfigProvider.GetBreakpointFileExpiration()
+	if !ok {
+		bcecliAbnormalExistErr(fmt.Errorf("There is no info about breakpoint file " +
+			"expiration found!"))
+	}
+	return expiration
+}
+
+func mustListBreakPointEntries() []*breakPointEntry {
+	entries, err := listBreakPointEntries(bceconf.MultiuploadFolder)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	return entries
+}
+
+// ResumeList - list the breakpoint records of pending multipart transfers
+func (b *BosCli) ResumeList() {
+	entries := mustListBreakPointEntries()
+	expiration := getBreakPointExpiration()
+	now := time.Now()
+
+	for _, entry := range entries {
+		state := ""
+		if entry.record == nil {
+			state = " (corrupt)"
+		} else if entry.expired(now, expiration) {
+			state = " (expired)"
+		}
+		progress := "-/-"
+		src, dst := "-", "-"
+		if entry.record != nil {
+			progress = fmt.Sprintf("%d/%d", len(entry.record.CompltePartList),
+				entry.record.PartsNum)
+			src, dst = entry.record.SrcPath, entry.record.DstPath
+		}
+		fmt.Printf("%s  %-8s  %-11s  %-10s  %s => %s%s\n", entry.id, entry.kind(), progress,
+			formatAge(entry.age(now)), src, dst, state)
+	}
+	fmt.Printf("[%d] breakpoint records.\n", len(entries))
+}
+
+// ResumeShow - show the detail of a breakpoint record
+func (b *BosCli) ResumeShow(id string) {
+	entry := findBreakPointEntry(mustListBreakPointEntries(), id)
+	if entry == nil {
+		bcecliAbnormalExistCode(BOSCLI_BREAKPOINT_RECORD_NOT_EXIST)
+	}
+	now := time.Now()
+
+	fmt.Printf("ID: %s\n", entry.id)
+	fmt.Printf("Record: %s\n", entry.path)
+	fmt.Printf("Type: %s\n", entry.kind())
+	fmt.Printf("Age: %s\n", formatAge(entry.age(now)))
+	if entry.record == nil {
+		fmt.Printf("Corrupt: %s\n", entry.err)
+		return
+	}
+	record := entry.record
+	fmt.Printf("Expired: %t\n", entry.expired(now, getBreakPointExpiration()))
+	fmt.Printf("Source: %s\n", record.SrcPath)
+	fmt.Printf("Destination: %s\n", record.DstPath)
+	if record.DstType == IS_LOCAL {
+		fmt.Printf("Temp File: %s\n", record.UploadId)
+	} else {
+		fmt.Printf("Upload Id: %s\n", record.UploadId)
+	}
+	fmt.Printf("File Size: %d\n", record.SrcFileSize)
+	fmt.Printf("Part Size: %d\n", record.PartSize)
+	fmt.Printf("Progress: %d/%d\n", len(record.CompltePartList), record.PartsNum)
+}
+
+// ResumePurge - delete breakpoint records, the temp files of downloads and abort the multipart
+// uploads of uploads and copies. Records are selected by ids, or all expired records when
+// expired is true, or all records when all is true.
+func (b *BosCli) ResumePurge(ids []string, expired, all, yes bool) {
+	if len(ids) == 0 && !expired && !all {
+		bcecliAbnormalExistCode(BOSCLI_RESUME_PURGE_NOTHING)
+	}
+
+	entries := mustListBreakPointEntries()
+	var selected []*breakPointEntry
+	if len(ids) > 0 {
+		for _, id := range ids {
+			entry := findBreakPointEntry(entries, id)
+			if entry == nil {
+				bcecliAbnormalExistCodeErr(BOSCLI_BREAKPOINT_RECORD_NOT_EXIST,
+					fmt.Errorf("breakpoint record %s doesn't exist", id))
+			}
+			selected = append(selected, entry)
+		}
+	} else if all {
+		selected = entries
+	} else {
+		expiration := getBreakPointExpiration()
+		now := time.Now()
+		for _, entry := range entries {
+			if entry.expired(now, expiration) {
+				selected = append(selected, entry)
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Printf("[0] breakpoint records purged.\n")
+		return
+	}
+	if !yes && !util.PromptConfirm("Do you really want to purge %d breakpoint records?",
+		len(selected)) {
+		return
+	}
+
+	purged, failed := 0, 0
+	for _, entry := range selected {
+		if err := b.purgeBreakPointEntry(entry); err != nil {
+			fmt.Printf("Failed purge %s: %s\n", entry.id, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Purge: %s\n", entry.id)
+		purged++
+	}
+	fmt.Printf("[%d] breakpoint records purged, [%d] failed.\n", purged, failed)
+}
+
+// Delete the record, the temp file of download, and abort the multipart upload of upload or
+// copy. The upload is aborted on the remote of destination in record, it is done when the upload
+// doesn't exist, as it is completed or aborted already. The record is kept when the upload can't
+// be aborted, so that it can be purged again.
+func (b *BosCli) purgeBreakPointEntry(entry *breakPointEntry) error {
+	if record := entry.record; record != nil && record.UploadId != "" {
+		if record.DstType == IS_LOCAL {
+			if err := os.Remove(record.UploadId); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		} else if record.DstType == IS_BOS {
+			client, err := b.getClientOfRemote(record.DstRemote)
+			if err != nil {
+				return err
+			}
+			err = client.AbortMultipartUpload(record.DstBucketName, record.DstObjectKey,
+				record.UploadId)
+			if code, ok := GetErrorCode(err); err != nil &&
+				!(ok && code == boscmd.CODE_NO_SUCH_UPLOAD) {
+				return err
+			}
+		}
+	}
+	if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// the temp file left by a crash when writing the record
+	os.Remove(entry.path + BREAK_POINT_TEMP_SUFFIX)
+	return nil
+}
+
+// find record by id, a unique prefix of id is allowed
+func findBreakPointEntry(entries []*breakPointEntry, id string) *breakPointEntry {
+	var found *breakPointEntry
+	for _, entry := range entries {
+		if entry.id == id {
+			return entry
+		}
+		if id != "" && strings.HasPrefix(entry.id, id) {
+			if found != nil {
+				return nil
+			}
+			found = entry
+		}
+	}
+	return found
+}
+
+// format age as days, hours or minutes
+func formatAge(age time.Duration) string {
+	switch {
+	case age >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int64(age/(24*time.Hour)))
+	case age >= time.Hour:
+		return fmt.Sprintf("%dh", int64(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int64(age/time.Minute))
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+import (
+	"bcecmd/boscmd"
+	"utils/util"
+)
+
+// client which records aborted uploads
+type fakeAbortClient struct {
+	bosClientInterface
+	aborted []string
+	err     error
+}
+
+func (f *fakeAbortClient) AbortMultipartUpload(bucket, object, uploadId string) error {
+	f.aborted = append(f.aborted, bucket+"/"+object+"/"+uploadId)
+	return f.err
+}
+
+func TestListBreakPointEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bcecmd.breakpoint.")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	record := &BreakPointRecord{SrcType: IS_LOCAL, DstType: IS_BOS, UploadId: "id"}
+	writeBreakPointRecord(filepath.Join(dir, "b"), record)
+	ioutil.WriteFile(filepath.Join(dir, "a"), []byte("{"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "b"+BREAK_POINT_TEMP_SUFFIX), []byte("{"), 0644)
+
+	entries, err := listBreakPointEntries(dir)
+	util.ExpectEqual("list breakpoint entries", 1, t.Errorf, nil, err)
+	util.ExpectEqual("list breakpoint entries", 2, t.Errorf, 2, len(entries))
+	if len(entries) == 2 {
+		util.ExpectEqual("list breakpoint entries", 3, t.Errorf, "a", entries[0].id)
+		util.ExpectEqual("list breakpoint entries", 4, t.Errorf, true, entries[0].record == nil)
+		util.ExpectEqual("list breakpoint entries", 5, t.Errorf, "unknown", entries[0].kind())
+		util.ExpectEqual("list breakpoint entries", 6, t.Errorf, "upload", entries[1].kind())
+	}
+
+	// folder doesn't exist
+	entries, err = listBreakPointEntries(filepath.Join(dir, "none"))
+	util.ExpectEqual("list breakpoint entries", 7, t.Errorf, nil, err)
+	util.ExpectEqual("list breakpoint entries", 8, t.Errorf, 0, len(entries))
+}
+
+type breakPointExpiredType struct {
+	record   *BreakPointRecord
+	mtime    time.Time
+	expired  bool
+	ageLabel string
+}
+
+func TestBreakPointEntryExpired(t *testing.T) {
+	now := time.Now()
+	testCases := []breakPointExpiredType{
+		//1 corrupt record
+		breakPointExpiredType{mtime: now, expired: true, ageLabel: "0m"},
+		//2 age from record time
+		breakPointExpiredType{record: &BreakPointRecord{RecordTime: now.Unix() - 3*86400},
+			mtime: now, expired: true, ageLabel: "3d"},
+		//3 age from file modify time
+		breakPointExpiredType{record: &BreakPointRecord{}, mtime: now.Add(-5 * time.Hour),
+			ageLabel: "5h"},
+	}
+	for i, tCase := range testCases {
+		entry := &breakPointEntry{record: tCase.record, mtime: tCase.mtime}
+		util.ExpectEqual("breakpoint expired", i+1, t.Errorf, tCase.expired,
+			entry.expired(now, 2))
+		util.ExpectEqual("breakpoint age", i+1, t.Errorf, tCase.ageLabel,
+			formatAge(entry.age(now)))
+	}
+}
+
+func TestFindBreakPointEntry(t *testing.T) {
+	entries := []*breakPointEntry{
+		&breakPointEntry{id: "abc1"},
+		&breakPointEntry{id: "abc2"},
+		&breakPointEntry{id: "abd"},
+	}
+	testCases := map[string]string{
+		"abc1": "abc1",
+		"abd":  "abd",
+		"abc":  "", // not unique
+		"x":    "",
+		"":     "",
+	}
+	i := 0
+	for id, expected := range testCases {
+		i++
+		found := ""
+		if entry := findBreakPointEntry(entries, id); entry != nil {
+			found = entry.id
+		}
+		util.ExpectEqual("find breakpoint entry "+id, i, t.Errorf, expected, found)
+	}
+}
+
+func TestPurgeBreakPointEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bcecmd.breakpoint.")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	//1 download, the temp file is deleted
+	tempFile := filepath.Join(dir, "bcecmd.temp.x")
+	ioutil.WriteFile(tempFile, []byte("data"), 0644)
+	entry := &breakPointEntry{path: filepath.Join(dir, "x"), record: &BreakPointRecord{
+		SrcType: IS_BOS, DstType: IS_LOCAL, UploadId: tempFile}}
+	writeBreakPointRecord(entry.path, entry.record)
+	client := &fakeAbortClient{}
+	cli := &BosCli{bosClient: client}
+	util.ExpectEqual("purge breakpoint", 1, t.Errorf, nil, cli.purgeBreakPointEntry(entry))
+	util.ExpectEqual("purge breakpoint", 1, t.Errorf, false, util.DoesFileExist(tempFile))
+	util.ExpectEqual("purge breakpoint", 1, t.Errorf, false, util.DoesFileExist(entry.path))
+	util.ExpectEqual("purge breakpoint", 1, t.Errorf, 0, len(client.aborted))
+
+	//2 upload, the multipart upload is aborted
+	entry.record = &BreakPointRecord{SrcType: IS_LOCAL, DstType: IS_BOS, UploadId: "id",
+		DstBucketName: "bucket", DstObjectKey: "object"}
+	writeBreakPointRecord(entry.path, entry.record)
+	util.ExpectEqual("purge breakpoint", 2, t.Errorf, nil, cli.purgeBreakPointEntry(entry))
+	util.ExpectEqual("purge breakpoint", 2, t.Errorf, false, util.DoesFileExist(entry.path))
+	util.ExpectEqual("purge breakpoint", 2, t.Errorf, []string{"bucket/object/id"},
+		client.aborted)
+
+	//3 the upload has been aborted or completed
+	writeBreakPointRecord(entry.path, entry.record)
+	client.err = awserr.New(boscmd.CODE_NO_SUCH_UPLOAD, "no such upload", nil)
+	util.ExpectEqual("purge breakpoint", 3, t.Errorf, nil, cli.purgeBreakPointEntry(entry))
+	util.ExpectEqual("purge breakpoint", 3, t.Errorf, false, util.DoesFileExist(entry.path))
+
+	//4 failed to abort, the record is kept
+	writeBreakPointRecord(entry.path, entry.record)
+	client.err = fmt.Errorf("network error")
+	util.ExpectEqual("purge breakpoint", 4, t.Errorf, client.err,
+		cli.purgeBreakPointEntry(entry))
+	util.ExpectEqual("purge breakpoint", 4, t.Errorf, true, util.DoesFileExist(entry.path))
+
+	//5 the upload is aborted on the remote of destination
+	remoteClient := &fakeAbortClient{}
+	cli.remoteClients = map[string]bosClientInterface{"backup": remoteClient}
+	entry.record.DstRemote = "backup"
+	writeBreakPointRecord(entry.path, entry.record)
+	client.err = nil
+	client.aborted = nil
+	util.ExpectEqual("purge breakpoint", 5, t.Errorf, nil, cli.purgeBreakPointEntry(entry))
+	util.ExpectEqual("purge breakpoint", 5, t.Errorf, false, util.DoesFileExist(entry.path))
+	util.ExpectEqual("purge breakpoint", 5, t.Errorf, 0, len(client.aborted))
+	util.ExpectEqual("purge breakpoint", 5, t.Errorf, []string{"bucket/object/id"},
+		remoteClient.aborted)
+}
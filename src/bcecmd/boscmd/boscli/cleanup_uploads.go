@@ -0,0 +1,137 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the cleanup of incomplete multipart uploads.
+
+package boscli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"bcecmd/boscmd"
+	"utils/util"
+)
+
+// units of age, e.g. 7d
+var ageUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// Parse age which is a positive number with unit w, d, h, m or s, e.g. 7d
+func parseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+	if len(age) < 2 {
+		return 0, fmt.Errorf("invalid age '%s'", age)
+	}
+	unit, ok := ageUnits[age[len(age)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit of age '%s'", age)
+	}
+	num, err := strconv.ParseInt(age[:len(age)-1], 10, 64)
+	if err != nil || num <= 0 {
+		return 0, fmt.Errorf("invalid age '%s'", age)
+	}
+	return time.Duration(num) * unit, nil
+}
+
+// CleanupUploads - abort the incomplete multipart uploads under bosPath which were initiated
+// more than olderThan ago.
+func (b *BosCli) CleanupUploads(bosPath, olderThan string, yes, dryrun, quiet bool) {
+	Quiet = quiet
+
+	retCode, err := checkBosPath(bosPath)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	bucketName, prefix := splitBosBucketKey(bosPath)
+	if bucketName == "" {
+		bcecliAbnormalExistCode(BOSCLI_BUCKETNAME_IS_EMPTY)
+	}
+	age, err := parseAge(olderThan)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_OLDER_THAN_IS_INVALID, err)
+	}
+
+	bosClient := b.cliOfPath(bosPath).bosClient
+	uploads, err := listAllUploads(bosClient, bucketName, prefix)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	stale := selectStaleUploads(uploads, time.Now().Add(-age))
+
+	if dryrun {
+		for _, upload := range stale {
+			printIfNotQuiet("(dryrun) Abort upload: %s%s/%s %s initiated at %s\n",
+				BOS_PATH_PREFIX, bucketName, aws.StringValue(upload.Key),
+				aws.StringValue(upload.UploadId), aws.TimeValue(upload.Initiated))
+		}
+		printIfNotQuiet("[%d] uploads would be aborted.\n", len(stale))
+		return
+	}
+	if len(stale) > 0 && !yes && !util.PromptConfirm("Do you really want to abort %d "+
+		"incomplete uploads under %s?", len(stale), bosPath) {
+		return
+	}
+
+	aborted, failed := 0, 0
+	for _, upload := range stale {
+		key := aws.StringValue(upload.Key)
+		uploadId := aws.StringValue(upload.UploadId)
+		release := transferSched.acquire(0)
+		err := bosClient.AbortMultipartUpload(bucketName, key, uploadId)
+		release()
+		// the upload may have been completed or aborted by others
+		if code, ok := GetErrorCode(err); err != nil && !(ok && code == boscmd.CODE_NO_SUCH_UPLOAD) {
+			printIfNotQuiet("Failed abort upload: %s%s/%s %s. Error: %s\n", BOS_PATH_PREFIX,
+				bucketName, key, uploadId, err)
+			failed++
+			continue
+		}
+		printIfNotQuiet("Abort upload: %s%s/%s %s\n", BOS_PATH_PREFIX, bucketName, key,
+			uploadId)
+		aborted++
+	}
+	printIfNotQuiet("[%d] uploads aborted, [%d] failed.\n", aborted, failed)
+	if failed > 0 {
+		bcecliAbnormalExistCode(BOSCLI_EMPTY_CODE)
+	}
+}
+
+// select the uploads which were initiated before deadline
+func selectStaleUploads(uploads []*s3.MultipartUpload,
+	deadline time.Time) []*s3.MultipartUpload {
+
+	var stale []*s3.MultipartUpload
+	for _, upload := range uploads {
+		if upload.UploadId == nil || upload.Initiated == nil {
+			continue
+		}
+		if upload.Initiated.Before(deadline) {
+			stale = append(stale, upload)
+		}
+	}
+	return stale
+}
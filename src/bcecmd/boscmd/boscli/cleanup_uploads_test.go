@@ -0,0 +1,84 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"utils/util"
+)
+
+type parseAgeType struct {
+	age      string
+	duration time.Duration
+	isSuc    bool
+}
+
+func TestParseAge(t *testing.T) {
+	testCases := []parseAgeType{
+		//1
+		parseAgeType{age: "7d", duration: 7 * 24 * time.Hour, isSuc: true},
+		//2
+		parseAgeType{age: "2w", duration: 14 * 24 * time.Hour, isSuc: true},
+		//3
+		parseAgeType{age: " 12h ", duration: 12 * time.Hour, isSuc: true},
+		//4
+		parseAgeType{age: "30m", duration: 30 * time.Minute, isSuc: true},
+		//5
+		parseAgeType{age: "10s", duration: 10 * time.Second, isSuc: true},
+		//6 no unit
+		parseAgeType{age: "7"},
+		//7 unknown unit
+		parseAgeType{age: "7y"},
+		//8 zero
+		parseAgeType{age: "0d"},
+		//9 negative
+		parseAgeType{age: "-1d"},
+		//10 empty
+		parseAgeType{age: ""},
+	}
+	for i, tCase := range testCases {
+		duration, err := parseAge(tCase.age)
+		util.ExpectEqual("parse age", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if tCase.isSuc {
+			util.ExpectEqual("parse age", i+1, t.Errorf, tCase.duration, duration)
+		}
+	}
+}
+
+func TestSelectStaleUploads(t *testing.T) {
+	client := &fakeMultipartListClient{uploads: []*s3.MultipartUpload{
+		newTestUpload("a", "1", "STANDARD", 100),
+		newTestUpload("a", "2", "STANDARD", 300),
+		newTestUpload("b", "3", "COLD", 199),
+	}}
+	uploads, err := listAllUploads(client, "bucket", "")
+	util.ExpectEqual("select stale uploads", 1, t.Errorf, nil, err)
+	util.ExpectEqual("select stale uploads", 2, t.Errorf, 3, len(uploads))
+	// upload without id or initiated time is ignored
+	uploads = append(uploads, &s3.MultipartUpload{})
+
+	stale := selectStaleUploads(uploads, time.Unix(200, 0))
+	uploadIds := []string{}
+	for _, upload := range stale {
+		uploadIds = append(uploadIds, *upload.UploadId)
+	}
+	util.ExpectEqual("select stale uploads", 3, t.Errorf, []string{"1", "3"}, uploadIds)
+}
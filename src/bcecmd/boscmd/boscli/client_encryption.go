@@ -0,0 +1,255 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the client-side encryption of cp and sync.
+// Every object has its own random data key, which is wrapped by the local master key and
+// stored in the metadata of object. The content is split into frames of CSE_FRAME_SIZE bytes,
+// each frame is sealed by AES-256-GCM independently, so an encrypted file can be uploaded by
+// multipart upload and any frame can be decrypted alone. The nonce of a frame is its index
+// with a flag of the last frame, so frames can't be reordered or truncated.
+// The file is encrypted to a temporary file before upload, and the object is decrypted in
+// place after download.
+
+package boscli
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	CSE_FRAME_SIZE = 64 * 1024
+	CSE_TAG_SIZE   = 16
+	CSE_NONCE_SIZE = 12
+	CSE_META_KEY   = "Bcecmd-Cse-Key"
+	CSE_META_FRAME = "Bcecmd-Cse-Frame"
+	CSE_META_SIZE  = "Bcecmd-Cse-Size"
+)
+
+// the client-side encryption of cp and sync, nil when it isn't enabled
+var clientEncryption *clientEncryptor
+
+// SetClientEncryptionKey - enable the client-side encryption of cp and sync, the key file
+// contains the master key which is used to wrap the data keys of objects.
+func (b *BosCli) SetClientEncryptionKey(keyFile string) {
+	if keyFile == "" {
+		clientEncryption = nil
+		return
+	}
+	encryptor, err := newClientEncryptor(keyFile)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_CLIENT_ENCRYPTION_KEY_IS_INVALID, err)
+	}
+	clientEncryption = encryptor
+}
+
+type clientEncryptor struct {
+	masterKey cipher.AEAD
+}
+
+func newClientEncryptor(keyFile string) (*clientEncryptor, error) {
+	content, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read master key file failed, error: %s", err)
+	}
+	key, err := parseAes256Key(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %s", err)
+	}
+	masterKey, err := newGcm(key)
+	if err != nil {
+		return nil, err
+	}
+	return &clientEncryptor{masterKey: masterKey}, nil
+}
+
+func newGcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Wrap data key by master key, the result is base64 of nonce and sealed key.
+func (c *clientEncryptor) wrapKey(dataKey []byte) (string, error) {
+	nonce := make([]byte, c.masterKey.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := c.masterKey.Seal(nonce, nonce, dataKey, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *clientEncryptor) unwrapKey(wrapped string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(sealed) < c.masterKey.NonceSize() {
+		return nil, fmt.Errorf("wrapped data key is corrupt")
+	}
+	nonceSize := c.masterKey.NonceSize()
+	dataKey, err := c.masterKey.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't unwrap data key, the master key may be wrong")
+	}
+	return dataKey, nil
+}
+
+// the nonce of a frame, which is the index of frame and the flag of last frame
+func frameNonce(index int64, last bool) []byte {
+	nonce := make([]byte, CSE_NONCE_SIZE)
+	binary.BigEndian.PutUint64(nonce, uint64(index))
+	if last {
+		nonce[CSE_NONCE_SIZE-1] = 1
+	}
+	return nonce
+}
+
+// Encrypt src to dst by frames, return the metadata of encryption.
+func (c *clientEncryptor) encryptFile(src, dst string) (map[string]*string, error) {
+	dataKey := make([]byte, AES256_KEY_SIZE)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	gcm, err := newGcm(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := c.wrapKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	srcFd, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer srcFd.Close()
+	dstFd, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer dstFd.Close()
+
+	reader := bufio.NewReaderSize(srcFd, CSE_FRAME_SIZE)
+	writer := bufio.NewWriterSize(dstFd, CSE_FRAME_SIZE+CSE_TAG_SIZE)
+	frame := make([]byte, CSE_FRAME_SIZE)
+	sealed := make([]byte, 0, CSE_FRAME_SIZE+CSE_TAG_SIZE)
+	var size int64
+	for index := int64(0); ; index++ {
+		n, err := io.ReadFull(reader, frame)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		// the frame is the last one when nothing is left
+		_, peekErr := reader.Peek(1)
+		last := peekErr == io.EOF
+		if peekErr != nil && !last {
+			return nil, peekErr
+		}
+		sealed = gcm.Seal(sealed[:0], frameNonce(index, last), frame[:n], nil)
+		if _, err := writer.Write(sealed); err != nil {
+			return nil, err
+		}
+		size += int64(n)
+		if last {
+			break
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	if err := dstFd.Close(); err != nil {
+		return nil, err
+	}
+	return map[string]*string{
+		CSE_META_KEY:   aws.String(wrapped),
+		CSE_META_FRAME: aws.String(strconv.Itoa(CSE_FRAME_SIZE)),
+		CSE_META_SIZE:  aws.String(strconv.FormatInt(size, 10)),
+	}, nil
+}
+
+// Decrypt the file encrypted by encryptFile in place. The plain text of a frame is never
+// written beyond the sealed frame, so frames are decrypted one by one from the beginning and
+// the file is truncated at last.
+func (c *clientEncryptor) decryptFile(fileName string, meta map[string]*string) error {
+	dataKey, err := c.unwrapKey(metadataValue(meta, CSE_META_KEY))
+	if err != nil {
+		return err
+	}
+	gcm, err := newGcm(dataKey)
+	if err != nil {
+		return err
+	}
+	frameSize, err := strconv.ParseInt(metadataValue(meta, CSE_META_FRAME), 10, 64)
+	// the frame size is checked before the buffer of frame is allocated by it
+	if err != nil || frameSize != CSE_FRAME_SIZE {
+		return fmt.Errorf("invalid frame size of client-side encryption")
+	}
+	plainSize, err := strconv.ParseInt(metadataValue(meta, CSE_META_SIZE), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size of client-side encryption")
+	}
+
+	fd, err := os.OpenFile(fileName, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	info, err := fd.Stat()
+	if err != nil {
+		return err
+	}
+	sealedSize := info.Size()
+	sealedFrameSize := frameSize + CSE_TAG_SIZE
+
+	buf := make([]byte, sealedFrameSize)
+	var offset, size int64
+	for index := int64(0); offset < sealedSize || index == 0; index++ {
+		n := sealedSize - offset
+		if n > sealedFrameSize {
+			n = sealedFrameSize
+		}
+		if _, err := fd.ReadAt(buf[:n], offset); err != nil {
+			return err
+		}
+		last := offset+n == sealedSize
+		plain, err := gcm.Open(buf[:0], frameNonce(index, last), buf[:n], nil)
+		if err != nil {
+			return fmt.Errorf("decrypt frame %d failed, the object is corrupt", index)
+		}
+		if _, err := fd.WriteAt(plain, size); err != nil {
+			return err
+		}
+		offset += n
+		size += int64(len(plain))
+	}
+	if size != plainSize {
+		return fmt.Errorf("size of decrypted file is %d, but %d is expected", size, plainSize)
+	}
+	if err := fd.Truncate(size); err != nil {
+		return err
+	}
+	return fd.Close()
+}
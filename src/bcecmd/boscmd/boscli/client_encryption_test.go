@@ -0,0 +1,124 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+import (
+	"utils/util"
+)
+
+func newTestEncryptor(t *testing.T, dir, key string) *clientEncryptor {
+	keyFile := filepath.Join(dir, "master.key")
+	if err := ioutil.WriteFile(keyFile, []byte(key), 0600); err != nil {
+		t.Fatalf("write master key failed: %s", err)
+	}
+	encryptor, err := newClientEncryptor(keyFile)
+	if err != nil {
+		t.Fatalf("new client encryptor failed: %s", err)
+	}
+	return encryptor
+}
+
+func TestClientEncryptRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cse_test")
+	util.ExpectEqual("client encryption", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	encryptor := newTestEncryptor(t, dir, strings.Repeat("m", AES256_KEY_SIZE))
+	sizes := []int{
+		//1 empty file
+		0,
+		//2 less than a frame
+		100,
+		//3 a frame
+		CSE_FRAME_SIZE,
+		//4 frames and a partial frame
+		3*CSE_FRAME_SIZE + 7,
+	}
+	for i, size := range sizes {
+		content := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+		src := filepath.Join(dir, "src")
+		dst := filepath.Join(dir, "dst")
+		util.ExpectEqual("client encryption", i+1, t.Errorf, nil,
+			ioutil.WriteFile(src, content, 0644))
+
+		meta, err := encryptor.encryptFile(src, dst)
+		util.ExpectEqual("client encryption", i+1, t.Errorf, nil, err)
+		sealed, _ := ioutil.ReadFile(dst)
+		frames := (size + CSE_FRAME_SIZE - 1) / CSE_FRAME_SIZE
+		if frames == 0 {
+			frames = 1
+		}
+		util.ExpectEqual("client encryption", i+1, t.Errorf, size+frames*CSE_TAG_SIZE,
+			len(sealed))
+
+		util.ExpectEqual("client encryption", i+1, t.Errorf, nil,
+			encryptor.decryptFile(dst, meta))
+		plain, _ := ioutil.ReadFile(dst)
+		util.ExpectEqual("client encryption", i+1, t.Errorf, true, bytes.Equal(content, plain))
+	}
+}
+
+func TestClientDecryptFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cse_test")
+	util.ExpectEqual("client decryption", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	encryptor := newTestEncryptor(t, dir, strings.Repeat("m", AES256_KEY_SIZE))
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	ioutil.WriteFile(src, bytes.Repeat([]byte("a"), 2*CSE_FRAME_SIZE+1), 0644)
+	meta, err := encryptor.encryptFile(src, dst)
+	util.ExpectEqual("client decryption", 0, t.Errorf, nil, err)
+	sealed, _ := ioutil.ReadFile(dst)
+
+	//1 wrong master key
+	other := newTestEncryptor(t, dir, strings.Repeat("o", AES256_KEY_SIZE))
+	util.ExpectEqual("client decryption", 1, t.Errorf, true, other.decryptFile(dst, meta) != nil)
+
+	//2 truncated at the end of a frame
+	ioutil.WriteFile(dst, sealed[:CSE_FRAME_SIZE+CSE_TAG_SIZE], 0644)
+	util.ExpectEqual("client decryption", 2, t.Errorf, true,
+		encryptor.decryptFile(dst, meta) != nil)
+
+	//3 modified
+	modified := append([]byte{}, sealed...)
+	modified[10] ^= 1
+	ioutil.WriteFile(dst, modified, 0644)
+	util.ExpectEqual("client decryption", 3, t.Errorf, true,
+		encryptor.decryptFile(dst, meta) != nil)
+
+	//4 frame size isn't the size of encryption
+	ioutil.WriteFile(dst, sealed, 0644)
+	for _, frameSize := range []string{"0", "-1", "1099511627776"} {
+		badMeta := make(map[string]*string)
+		for key, val := range meta {
+			badMeta[key] = val
+		}
+		badMeta[CSE_META_FRAME] = aws.String(frameSize)
+		util.ExpectEqual("client decryption", 4, t.Errorf, true,
+			encryptor.decryptFile(dst, badMeta) != nil)
+	}
+}
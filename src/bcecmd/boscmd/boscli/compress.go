@@ -0,0 +1,190 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the compression of files uploaded by cp and sync.
+// The algorithm, the size and the last modified time of the original file are kept in the
+// metadata of object, and the object is decompressed after download when it has the metadata.
+
+package boscli
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	COMPRESS_GZIP                = "gzip"
+	COMPRESS_ZSTD                = "zstd"
+	COMPRESS_META_ALGORITHM      = "Bcecmd-Compress"
+	COMPRESS_META_ORIGINAL_SIZE  = "Bcecmd-Original-Size"
+	COMPRESS_META_ORIGINAL_MTIME = "Bcecmd-Original-Mtime"
+	COMPRESS_TMP_FILE_NAME       = "bcecmd.compress."
+	COMPRESS_BUFFER_SIZE         = 256 * 1024
+)
+
+type compressor struct {
+	newWriter func(io.Writer) (io.WriteCloser, error)
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+// compression algorithms supported, files are compressed concurrently by cp and sync, so zstd
+// uses one goroutine for each file
+var compressors = map[string]*compressor{
+	COMPRESS_GZIP: &compressor{
+		newWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+		newReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	},
+	COMPRESS_ZSTD: &compressor{
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		},
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			return decoder.IOReadCloser(), nil
+		},
+	},
+}
+
+// compression algorithm of files uploaded by cp and sync, empty when they aren't compressed
+var uploadCompress string
+
+// SetUploadCompress - set the compression algorithm of files uploaded by cp and sync
+func (b *BosCli) SetUploadCompress(algorithm string) {
+	if algorithm != "" && compressors[algorithm] == nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_COMPRESS_IS_INVALID,
+			fmt.Errorf("unsupported compression '%s'", algorithm))
+	}
+	uploadCompress = algorithm
+}
+
+// Compress src to dst, return the metadata of compression.
+func compressFile(src, dst, algorithm string) (map[string]*string, error) {
+	comp := compressors[algorithm]
+	if comp == nil {
+		return nil, fmt.Errorf("unsupported compression '%s'", algorithm)
+	}
+
+	srcFd, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer srcFd.Close()
+	info, err := srcFd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	dstFd, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer dstFd.Close()
+
+	writer := bufio.NewWriterSize(dstFd, COMPRESS_BUFFER_SIZE)
+	compWriter, err := comp.newWriter(writer)
+	if err != nil {
+		return nil, err
+	}
+	size, err := io.Copy(compWriter, srcFd)
+	if err != nil {
+		return nil, err
+	}
+	if err := compWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	if err := dstFd.Close(); err != nil {
+		return nil, err
+	}
+	return map[string]*string{
+		COMPRESS_META_ALGORITHM:      aws.String(algorithm),
+		COMPRESS_META_ORIGINAL_SIZE:  aws.String(strconv.FormatInt(size, 10)),
+		COMPRESS_META_ORIGINAL_MTIME: aws.String(strconv.FormatInt(info.ModTime().Unix(), 10)),
+	}, nil
+}
+
+// Decompress the file by the metadata of compression, the file is replaced by the
+// decompressed one.
+func decompressFile(fileName string, meta map[string]*string) error {
+	algorithm := metadataValue(meta, COMPRESS_META_ALGORITHM)
+	comp := compressors[algorithm]
+	if comp == nil {
+		return fmt.Errorf("unsupported compression '%s'", algorithm)
+	}
+	originalSize, err := strconv.ParseInt(metadataValue(meta, COMPRESS_META_ORIGINAL_SIZE), 10,
+		64)
+	if err != nil {
+		return fmt.Errorf("invalid original size of compressed object")
+	}
+
+	srcFd, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer srcFd.Close()
+	compReader, err := comp.newReader(bufio.NewReaderSize(srcFd, COMPRESS_BUFFER_SIZE))
+	if err != nil {
+		return err
+	}
+	defer compReader.Close()
+
+	// decompress to a temporary file in the same directory, and then replace the file
+	dstFd, err := ioutil.TempFile(filepath.Dir(fileName), COMPRESS_TMP_FILE_NAME)
+	if err != nil {
+		return err
+	}
+	tmpFilePath := dstFd.Name()
+	defer os.Remove(tmpFilePath)
+	defer dstFd.Close()
+
+	size, err := io.Copy(dstFd, compReader)
+	if err != nil {
+		return err
+	}
+	if size != originalSize {
+		return fmt.Errorf("size of decompressed file is %d, but %d is expected", size,
+			originalSize)
+	}
+	if err := dstFd.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFilePath, 0644); err != nil {
+		return err
+	}
+	srcFd.Close()
+	return os.Rename(tmpFilePath, fileName)
+}
+
+// the Content-Encoding of object uploaded with metadata, it is nil when the object isn't
+// compressed or the compressed content is encrypted
+func contentEncodingOf(meta map[string]*string) *string {
+	algorithm := metadataValue(meta, COMPRESS_META_ALGORITHM)
+	if algorithm == "" || metadataValue(meta, CSE_META_KEY) != "" {
+		return nil
+	}
+	return aws.String(algorithm)
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+import (
+	"utils/util"
+)
+
+type compressFileType struct {
+	algorithm string
+	size      int
+}
+
+func TestCompressFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compress_test")
+	util.ExpectEqual("compress file", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	testCases := []compressFileType{
+		//1 empty file
+		compressFileType{algorithm: COMPRESS_GZIP, size: 0},
+		//2
+		compressFileType{algorithm: COMPRESS_GZIP, size: 1024 * 1024},
+		//3
+		compressFileType{algorithm: COMPRESS_ZSTD, size: 0},
+		//4
+		compressFileType{algorithm: COMPRESS_ZSTD, size: 1024 * 1024},
+	}
+	for i, tCase := range testCases {
+		size := tCase.size
+		content := bytes.Repeat([]byte("log line\n"), size/9+1)[:size]
+		src := filepath.Join(dir, "src")
+		dst := filepath.Join(dir, "dst")
+		util.ExpectEqual("compress file", i+1, t.Errorf, nil,
+			ioutil.WriteFile(src, content, 0644))
+
+		meta, err := compressFile(src, dst, tCase.algorithm)
+		util.ExpectEqual("compress file", i+1, t.Errorf, nil, err)
+		util.ExpectEqual("compress file", i+1, t.Errorf, strconv.Itoa(size),
+			metadataValue(meta, COMPRESS_META_ORIGINAL_SIZE))
+		util.ExpectEqual("compress file", i+1, t.Errorf, true,
+			metadataValue(meta, COMPRESS_META_ORIGINAL_MTIME) != "")
+		util.ExpectEqual("compress file", i+1, t.Errorf, tCase.algorithm,
+			aws.StringValue(contentEncodingOf(meta)))
+
+		util.ExpectEqual("compress file", i+1, t.Errorf, nil, decompressFile(dst, meta))
+		plain, _ := ioutil.ReadFile(dst)
+		util.ExpectEqual("compress file", i+1, t.Errorf, true, bytes.Equal(content, plain))
+	}
+}
+
+func TestDecompressFileFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compress_test")
+	util.ExpectEqual("decompress file", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	ioutil.WriteFile(src, []byte("hello"), 0644)
+	meta, err := compressFile(src, dst, COMPRESS_GZIP)
+	util.ExpectEqual("decompress file", 0, t.Errorf, nil, err)
+	compressed, _ := ioutil.ReadFile(dst)
+
+	//1 unknown algorithm
+	err = decompressFile(dst, map[string]*string{COMPRESS_META_ALGORITHM: aws.String("lz4"),
+		COMPRESS_META_ORIGINAL_SIZE: aws.String("5")})
+	util.ExpectEqual("decompress file", 1, t.Errorf, true, err != nil)
+
+	//2 size mismatch
+	err = decompressFile(dst, map[string]*string{
+		COMPRESS_META_ALGORITHM:     meta[COMPRESS_META_ALGORITHM],
+		COMPRESS_META_ORIGINAL_SIZE: aws.String("6")})
+	util.ExpectEqual("decompress file", 2, t.Errorf, true, err != nil)
+	kept, _ := ioutil.ReadFile(dst)
+	util.ExpectEqual("decompress file", 2, t.Errorf, true, bytes.Equal(compressed, kept))
+
+	//3 not compressed
+	ioutil.WriteFile(dst, []byte("hello"), 0644)
+	util.ExpectEqual("decompress file", 3, t.Errorf, true, decompressFile(dst, meta) != nil)
+}
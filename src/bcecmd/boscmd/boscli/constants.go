@@ -19,6 +19,8 @@ const (
 
 	BOS_PATH_PREFIX        = "bos:/"
 	BOS_PATH_PREFIX_DOUBLE = "bos://"
+	S3_PATH_PREFIX         = "s3://"
+	REMOTE_NAME_SEPARATOR  = ":"
 
 	BCE_CLI_AGENT  = "bcecmd"
 	HTTP_PROTOCOL  = "http://"
@@ -38,12 +40,11 @@ const (
 	GET_NET_LOCAL_FILE_TIME_OUT = 36000 * 1000 // 10 hours
 	GET_NEXT_OBJECT_TIME_OUT    = 600 * 1000   // 10 m
 	MULTI_UPLOAD_MAX_FILE_SIZE  = 5 << 40      // 5T
-	MULTI_UPLOAD_THRESHOLD      = 32 << 20     // 32M
 	PART_SIZE_BASE              = 10 << 20     // 10M
-	MULTI_COPY_THRESHOLD        = 100 << 20    // 100M
-	MULTI_COPY_PART_SIZE        = 50 << 20     // 50M
+	MAX_PART_SIZE               = 5 << 30      // 5G
+	MIN_PART_SIZE               = 5 << 20      // 5M, all parts of upload and copy but the last
 
-	MULTI_DOWNLOAD_THRESHOLD = 100 << 20 // 32M
+	DOWNLOAD_BUFFER_SIZE = 1 << 20 // 1M
 )
 
 // sync op constants
@@ -0,0 +1,140 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the parsing and validation of bucket CORS configuration.
+
+package boscli
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	CORS_MAX_RULES = 100
+)
+
+// methods which can be allowed by CORS rule
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// CORS configuration in the xml format of S3 API
+type corsXmlConfiguration struct {
+	XMLName xml.Name      `xml:"CORSConfiguration"`
+	Rules   []corsXmlRule `xml:"CORSRule"`
+}
+
+type corsXmlRule struct {
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader"`
+	ExposeHeaders  []string `xml:"ExposeHeader"`
+	MaxAgeSeconds  *int64   `xml:"MaxAgeSeconds"`
+}
+
+// Parse CORS configuration in json or xml, xml is used when the content starts with '<'. The
+// field names of json are the same as S3 CORS API, e.g.
+// {"CORSRules": [{"AllowedOrigins": ["*"], "AllowedMethods": ["GET"]}]}
+func parseCorsConfig(cors []byte) (*s3.CORSConfiguration, error) {
+	config := &s3.CORSConfiguration{}
+	if content := bytes.TrimSpace(cors); len(content) > 0 && content[0] == '<' {
+		xmlConfig := &corsXmlConfiguration{}
+		if err := xml.Unmarshal(content, xmlConfig); err != nil {
+			return nil, fmt.Errorf("invalid CORS configuration: %s", err)
+		}
+		for _, rule := range xmlConfig.Rules {
+			config.CORSRules = append(config.CORSRules, &s3.CORSRule{
+				AllowedOrigins: aws.StringSlice(rule.AllowedOrigins),
+				AllowedMethods: aws.StringSlice(rule.AllowedMethods),
+				AllowedHeaders: aws.StringSlice(rule.AllowedHeaders),
+				ExposeHeaders:  aws.StringSlice(rule.ExposeHeaders),
+				MaxAgeSeconds:  rule.MaxAgeSeconds,
+			})
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(cors))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(config); err != nil {
+			return nil, fmt.Errorf("invalid CORS configuration: %s", err)
+		}
+	}
+
+	if err := validateCorsConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// validate CORS configuration locally
+func validateCorsConfig(config *s3.CORSConfiguration) error {
+	if len(config.CORSRules) == 0 {
+		return fmt.Errorf("CORS configuration must have at least one rule")
+	} else if len(config.CORSRules) > CORS_MAX_RULES {
+		return fmt.Errorf("CORS configuration can have at most %d rules", CORS_MAX_RULES)
+	}
+	for i, rule := range config.CORSRules {
+		if rule == nil {
+			return fmt.Errorf("rule %d is empty", i+1)
+		}
+		if err := validateCorsRule(rule); err != nil {
+			return fmt.Errorf("rule %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate a CORS rule
+func validateCorsRule(rule *s3.CORSRule) error {
+	if len(rule.AllowedMethods) == 0 {
+		return fmt.Errorf("AllowedMethods is required")
+	}
+	for _, method := range aws.StringValueSlice(rule.AllowedMethods) {
+		if !isStringIn(method, corsMethods) {
+			return fmt.Errorf("method '%s' isn't one of %v", method, corsMethods)
+		}
+	}
+
+	if len(rule.AllowedOrigins) == 0 {
+		return fmt.Errorf("AllowedOrigins is required")
+	}
+	for _, origin := range aws.StringValueSlice(rule.AllowedOrigins) {
+		if origin == "" || strings.ContainsAny(origin, " \t") {
+			return fmt.Errorf("origin '%s' is invalid", origin)
+		} else if strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("origin '%s' can have at most one '*'", origin)
+		}
+	}
+	for _, header := range aws.StringValueSlice(rule.AllowedHeaders) {
+		if header == "" {
+			return fmt.Errorf("allowed header can't be empty")
+		} else if strings.Count(header, "*") > 1 {
+			return fmt.Errorf("allowed header '%s' can have at most one '*'", header)
+		}
+	}
+	for _, header := range aws.StringValueSlice(rule.ExposeHeaders) {
+		if header == "" || strings.Contains(header, "*") {
+			return fmt.Errorf("expose header '%s' is invalid", header)
+		}
+	}
+
+	if rule.MaxAgeSeconds != nil && *rule.MaxAgeSeconds < 0 {
+		return fmt.Errorf("MaxAgeSeconds can't be negative")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+import (
+	"utils/util"
+)
+
+type parseCorsConfigType struct {
+	cors    string
+	origins []string
+	maxAge  int64
+	isSuc   bool
+}
+
+func TestParseCorsConfig(t *testing.T) {
+	testCases := []parseCorsConfigType{
+		//1 json
+		parseCorsConfigType{
+			cors: `{"CORSRules": [{"AllowedOrigins": ["https://*.example.com"],
+				"AllowedMethods": ["GET", "HEAD"], "AllowedHeaders": ["*"],
+				"ExposeHeaders": ["ETag"], "MaxAgeSeconds": 3600}]}`,
+			origins: []string{"https://*.example.com"},
+			maxAge:  3600,
+			isSuc:   true,
+		},
+		//2 xml
+		parseCorsConfigType{
+			cors: ` <CORSConfiguration>
+				<CORSRule>
+					<AllowedOrigin>http://a.com</AllowedOrigin>
+					<AllowedOrigin>http://b.com</AllowedOrigin>
+					<AllowedMethod>PUT</AllowedMethod>
+					<MaxAgeSeconds>60</MaxAgeSeconds>
+				</CORSRule>
+			</CORSConfiguration>`,
+			origins: []string{"http://a.com", "http://b.com"},
+			maxAge:  60,
+			isSuc:   true,
+		},
+		//3 invalid json
+		parseCorsConfigType{cors: `{"CORSRules": [`},
+		//4 invalid xml
+		parseCorsConfigType{cors: `<CORSConfiguration><CORSRule>`},
+		//5 no rule
+		parseCorsConfigType{cors: `<CORSConfiguration></CORSConfiguration>`},
+		//6 no method
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedOrigins": ["*"]}]}`},
+		//7 invalid method
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedOrigins": ["*"],
+			"AllowedMethods": ["get"]}]}`},
+		//8 no origin
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedMethods": ["GET"]}]}`},
+		//9 two wildcards in origin
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedOrigins": ["http://*.*.com"],
+			"AllowedMethods": ["GET"]}]}`},
+		//10 negative max age
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedOrigins": ["*"],
+			"AllowedMethods": ["GET"], "MaxAgeSeconds": -1}]}`},
+		//11 wildcard in expose header
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedOrigins": ["*"],
+			"AllowedMethods": ["GET"], "ExposeHeaders": ["*"]}]}`},
+		//12 unknown field
+		parseCorsConfigType{cors: `{"CORSRules": [{"AllowedOrigin": ["*"],
+			"AllowedMethods": ["GET"]}]}`},
+	}
+	for i, tCase := range testCases {
+		config, err := parseCorsConfig([]byte(tCase.cors))
+		util.ExpectEqual("parse cors", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if err != nil {
+			continue
+		}
+		rule := config.CORSRules[0]
+		util.ExpectEqual("parse cors", i+1, t.Errorf, tCase.origins,
+			aws.StringValueSlice(rule.AllowedOrigins))
+		util.ExpectEqual("parse cors", i+1, t.Errorf, tCase.maxAge,
+			aws.Int64Value(rule.MaxAgeSeconds))
+	}
+}
@@ -73,6 +73,29 @@ const (
 	BOSCLI_PUT_ACL_CANNED_FILE_SAME_TIME      = "boscliPutAclCannedFileSameTime"
 	BOSCLI_PUT_ACL_CANNED_FILE_BOTH_EMPTY     = "boscliPutAclCannedFileBothEmpty"
 	BOSCLI_PUT_ACL_CANNED_DONT_SUPPORT        = "boscliPutAclCannedDontSupport"
+	BOSCLI_TIME_RANGE_IS_INVALID              = "boscliTimeRangeIsInvalid"
+	BOSCLI_REGEX_IS_INVALID                   = "boscliRegexIsInvalid"
+	BOSCLI_TRANSFER_BUDGET_IS_INVALID         = "boscliTransferBudgetIsInvalid"
+	BOSCLI_TRANSFER_TUNING_IS_INVALID         = "boscliTransferTuningIsInvalid"
+	BOSCLI_BREAKPOINT_RECORD_NOT_EXIST        = "boscliBreakpointRecordNotExist"
+	BOSCLI_RESUME_PURGE_NOTHING               = "boscliResumePurgeNothing"
+	BOSCLI_UPLOAD_ID_IS_EMPTY                 = "boscliUploadIdIsEmpty"
+	BOSCLI_OLDER_THAN_IS_INVALID              = "boscliOlderThanIsInvalid"
+	BOSCLI_LIFECYCLE_IS_INVALID               = "boscliLifecycleIsInvalid"
+	BOSCLI_LOG_TARGET_BUCKET_NOT_EXIST        = "boscliLogTargetBucketNotExist"
+	BOSCLI_ACL_IS_INVALID                     = "boscliAclIsInvalid"
+	BOSCLI_PUT_POLICY_NO_CONFIG_AND_BUCKET    = "boscliPutPolicyNoConfigAndBucket"
+	BOSCLI_POLICY_IS_INVALID                  = "boscliPolicyIsInvalid"
+	BOSCLI_PUT_CORS_NO_CONFIG_AND_BUCKET      = "boscliPutCorsNoConfigAndBucket"
+	BOSCLI_CORS_IS_INVALID                    = "boscliCorsIsInvalid"
+	BOSCLI_TAGS_IS_INVALID                    = "boscliTagsIsInvalid"
+	BOSCLI_TAG_FILTER_ONLY_FOR_BOS            = "boscliTagFilterOnlyForBos"
+	BOSCLI_VERSION_ID_ONLY_FOR_OBJECT         = "boscliVersionIdOnlyForObject"
+	BOSCLI_NO_DELETE_MARKER                   = "boscliNoDeleteMarker"
+	BOSCLI_VERSIONING_STATUS_IS_INVALID       = "boscliVersioningStatusIsInvalid"
+	BOSCLI_SSE_IS_INVALID                     = "boscliSseIsInvalid"
+	BOSCLI_CLIENT_ENCRYPTION_KEY_IS_INVALID   = "boscliClientEncryptionKeyIsInvalid"
+	BOSCLI_COMPRESS_IS_INVALID                = "boscliCompressIsInvalid"
 )
 
 var BosCliSuggetions map[BosCliErrorCode]string
@@ -91,8 +114,8 @@ func initSuggetionChinese() {
 		"您输入的 Object Key 不能为空！"
 	BosCliSuggetions[BOSCLI_BOSPATH_IS_INVALID] =
 		"请您检查是否输入了无效的Bos路径！BOS路径可以是bucket 也可以是 bucket + object, Bos路径" +
-			"必须以 bos:/开始。\n" +
-			"例如: bos:/bucket, bos:/bucket/object。"
+			"必须以 bos:/、s3:// 或已配置的 remote 名称加冒号开始。\n" +
+			"例如: bos:/bucket, bos:/bucket/object, s3://bucket/object, remote:bucket/object。"
 	BosCliSuggetions[BOSCLI_BUCKETNAME_CONTAIN_OBJECTNAME] =
 		"Bucket Name 不应该包含 Object 信息（Bucket Name 只能包含小写字母、数字和“-”，开头结" +
 			"尾为小写字母和数字，长度在3-63之间）"
@@ -171,10 +194,84 @@ func initSuggetionChinese() {
 	BosCliSuggetions[BOSCLI_PUT_ACL_CANNED_FILE_SAME_TIME] =
 		"不能同时通过canned ACL和ACL文件来设置bucket的ACL"
 	BosCliSuggetions[BOSCLI_PUT_ACL_CANNED_DONT_SUPPORT] =
-		"Canned ACL 仅支持 private、public-read、public-read-write三种"
+		"Bucket 的 Canned ACL 仅支持 private、public-read、public-read-write三种，Object 还支持 " +
+			"authenticated-read、bucket-owner-read、bucket-owner-full-control"
 	BosCliSuggetions[BOSCLI_PUT_ACL_CANNED_FILE_BOTH_EMPTY] =
 		"请指定Bucket 的 ACL配置信息，您可以通过 --canned 指定 canned ACL，或者通过 " +
 			"--acl-config-file 从文件中上传ACL"
+	BosCliSuggetions[BOSCLI_TIME_RANGE_IS_INVALID] =
+		"时间范围的格式为 '开始时间,结束时间'，时间可以是 Unix 时间戳或者 UTC 时间" +
+			"（例如 2006-01-02T15:04:05Z），开始时间或结束时间为空表示不限制。\n" +
+			"例如: --exclude-time 1513077420,1513077510 或 --include-time 2018-01-01T00:00:00Z,"
+	BosCliSuggetions[BOSCLI_REGEX_IS_INVALID] =
+		"--regex-include 和 --regex-exclude 的值必须是合法的 Go 正则表达式，" +
+			"例如: --regex-exclude '\\.(log|tmp)$'"
+	BosCliSuggetions[BOSCLI_TRANSFER_BUDGET_IS_INVALID] =
+		"--max-requests 和 --max-memory 不能小于 0，--max-requests 为 0 表示不限制，" +
+			"--max-memory 为 0 表示使用配置文件中的 multi_upload_max_memory。\n" +
+			"例如: --max-requests 32 --max-memory 512MiB"
+	BosCliSuggetions[BOSCLI_TRANSFER_TUNING_IS_INVALID] =
+		"分块传输的阈值、分块大小和线程数不能小于 0，分块大小不能超过 5GiB，" +
+			"并且阈值不能超过分块大小的 10000 倍。\n" +
+			"例如: --upload-threshold 64MiB --upload-part-size 16MiB --upload-thread-num 8"
+	BosCliSuggetions[BOSCLI_BREAKPOINT_RECORD_NOT_EXIST] =
+		"断点记录不存在，请使用 bcecmd bos resume list 查看所有断点记录的 ID！"
+	BosCliSuggetions[BOSCLI_RESUME_PURGE_NOTHING] =
+		"请指定要清除的断点记录 ID，或者使用 --expired 清除过期的断点记录、" +
+			"使用 --all 清除全部断点记录！"
+	BosCliSuggetions[BOSCLI_UPLOAD_ID_IS_EMPTY] =
+		"您输入的 Upload Id 不能为空！可以使用 bcecmd bosapi list-multipart-uploads 查看" +
+			"未完成的分块上传。"
+	BosCliSuggetions[BOSCLI_OLDER_THAN_IS_INVALID] =
+		"--older-than 必须是大于 0 的数字加单位，单位可以是 w(周)、d(天)、h(小时)、m(分钟)、" +
+			"s(秒)。\n例如: --older-than 7d"
+	BosCliSuggetions[BOSCLI_LIFECYCLE_IS_INVALID] =
+		"生命周期配置文件不合法，请参考 S3 生命周期 API 的字段检查您的配置，可以使用 " +
+			"bcecmd bosapi put-lifecycle --template 生成配置模板！"
+	BosCliSuggetions[BOSCLI_LOG_TARGET_BUCKET_NOT_EXIST] =
+		"用于保存日志的 bucket 不存在，请先使用 bcecmd bos mb 创建该 bucket，或者指定其他已存在" +
+			"的 bucket！"
+	BosCliSuggetions[BOSCLI_ACL_IS_INVALID] =
+		"ACL 配置文件不合法，格式示例:\n" +
+			"{\"Grants\": [{\"Grantee\": {\"ID\": \"用户ID\"}, \"Permission\": \"READ\"}]}\n" +
+			"Grantee 可以是 ID、EmailAddress 或 URI，Permission 可以是 FULL_CONTROL、WRITE、" +
+			"WRITE_ACP、READ 或 READ_ACP"
+	BosCliSuggetions[BOSCLI_PUT_POLICY_NO_CONFIG_AND_BUCKET] =
+		"请指定要配置权限策略的bucket name，和权限策略文件的地址, 操作示例:\n" +
+			"bcecmd bosapi put-bucket-policy --policy-config-file policy.json --bucket-name " +
+			"bucket1"
+	BosCliSuggetions[BOSCLI_POLICY_IS_INVALID] =
+		"权限策略文件不合法，每条 Statement 需要包含 Effect、Principal、Action 和 Resource，" +
+			"可以使用 bcecmd bosapi put-bucket-policy --template read-only 生成策略模板！"
+	BosCliSuggetions[BOSCLI_PUT_CORS_NO_CONFIG_AND_BUCKET] =
+		"请指定要配置跨域访问的bucket name，和跨域规则文件的地址, 操作示例:\n" +
+			"bcecmd bosapi put-bucket-cors --cors-config-file cors.json --bucket-name bucket1"
+	BosCliSuggetions[BOSCLI_CORS_IS_INVALID] =
+		"跨域规则文件不合法，文件可以是 JSON 或 XML 格式，JSON 格式示例:\n" +
+			"{\"CORSRules\": [{\"AllowedOrigins\": [\"https://www.example.com\"], " +
+			"\"AllowedMethods\": [\"GET\", \"HEAD\"], \"MaxAgeSeconds\": 3600}]}\n" +
+			"AllowedMethods 只能是 GET、PUT、POST、DELETE 或 HEAD，Origin 中最多包含一个 '*'，" +
+			"MaxAgeSeconds 不能小于 0"
+	BosCliSuggetions[BOSCLI_TAGS_IS_INVALID] =
+		"标签格式不合法，格式示例: --tags project=web,env=prod\n" +
+			"key 不能为空且不能以 aws: 开头，key 最长 128 个字符，value 最长 256 个字符，" +
+			"key 不能重复；object 最多 10 个标签，bucket 最多 50 个标签"
+	BosCliSuggetions[BOSCLI_TAG_FILTER_ONLY_FOR_BOS] =
+		"--tag-filter 只能用于 BOS 上的 object，请检查源路径是否为 bos:/ 开头的路径！"
+	BosCliSuggetions[BOSCLI_VERSION_ID_ONLY_FOR_OBJECT] =
+		"--version-id 只能用于 BOS 上的单个 object，不能和 -r 一起使用！"
+	BosCliSuggetions[BOSCLI_NO_DELETE_MARKER] =
+		"object 的最新版本不是删除标记，不需要恢复；可以使用 bcecmd bos ls --versions 查看 " +
+			"object 的所有版本"
+	BosCliSuggetions[BOSCLI_VERSIONING_STATUS_IS_INVALID] =
+		"多版本状态只能是 Enabled 或 Suspended！"
+	BosCliSuggetions[BOSCLI_SSE_IS_INVALID] =
+		"服务端加密参数不合法，--sse 只能是 AES256 或 aws:kms，--sse-kms-key-id 只能和 " +
+			"--sse aws:kms 一起使用；--sse-c-key-file 不能和 --sse 一起使用，密钥文件内容必须是 " +
+			"32 字节的密钥或其 base64 编码"
+	BosCliSuggetions[BOSCLI_CLIENT_ENCRYPTION_KEY_IS_INVALID] =
+		"客户端加密的主密钥文件不可读或不合法，文件内容必须是 32 字节的密钥或其 base64 编码"
+	BosCliSuggetions[BOSCLI_COMPRESS_IS_INVALID] = "--compress 目前只支持 gzip 和 zstd"
 
 }
 
@@ -0,0 +1,411 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"bcecmd/boscmd"
+)
+
+// The base of fake bos clients, every method returns "Not support" error. Fake clients embed it
+// and override the methods which are used by their tests.
+type fakeUnsupportedBosClient struct{}
+
+var errFakeNotSupport = fmt.Errorf("Not support")
+
+func (b *fakeUnsupportedBosClient) RemoteName() string {
+	return ""
+}
+
+func (b *fakeUnsupportedBosClient) HeadBucket(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) ListBuckets() (*s3.ListBucketsOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) ListObjects(bucket, delimiter, marker, prefix string,
+	maxKeys int) (*s3.ListObjectsOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucket(bucket string) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteBucket(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketLocation(bucket string) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) BasicGeneratePresignedUrl(bucket string, object string,
+	expireInSeconds int) string {
+	return ""
+}
+
+func (b *fakeUnsupportedBosClient) DeleteMultipleObjectsFromKeyList(bucket string,
+	keyList []string) (*DeleteMultipleObjectsResult, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteObject(bucket, object string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteObjectVersion(bucket, object, versionId string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) ListObjectVersions(bucket, prefix, delimiter, keyMarker,
+	versionIdMarker string, maxKeys int) (*s3.ListObjectVersionsOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput,
+	error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetDestinationObjectMeta(bucket,
+	object string) (*s3.HeadObjectOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) CopyObject(bucket, object, srcBucket, srcObject,
+	storageClass string) (*s3.CopyObjectOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) BasicGetObjectToFile(bucket, object, localPath string) (
+	map[string]*string, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutObjectFromFile(bucket, object, fileName,
+	storageClass string, meta map[string]*string) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketLifecycleFromString(bucket, lifecycle string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketLifecycle(bucket string) (
+	*s3.GetBucketLifecycleConfigurationOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteBucketLifecycle(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketLogging(bucket, targetBucket,
+	targetPrefix string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketLogging(bucket string) (*s3.GetBucketLoggingOutput,
+	error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteBucketLogging(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketPolicy(bucket, policy string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketPolicy(bucket string) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteBucketPolicy(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketCors(bucket string,
+	config *s3.CORSConfiguration) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketCors(bucket string) (*s3.GetBucketCorsOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteBucketCors(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketTagging(bucket string, tagSet []*s3.Tag) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketTagging(bucket string) (*s3.GetBucketTaggingOutput,
+	error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) DeleteBucketTagging(bucket string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutObjectTagging(bucket, object string,
+	tagSet []*s3.Tag) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetObjectTagging(bucket, object string) (
+	*s3.GetObjectTaggingOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketVersioning(bucket, status string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketVersioning(bucket string) (
+	*s3.GetBucketVersioningOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketEncryption(bucket string,
+	config *s3.ServerSideEncryptionConfiguration) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketEncryption(bucket string) (
+	*s3.GetBucketEncryptionOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketStorageclass(bucket, storageClass string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketStorageclass(bucket string) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketAclFromCanned(bucket, cannedAcl string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutBucketAclFromString(bucket, acl string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetBucketAcl(bucket string) (*s3.GetBucketAclOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutObjectAclFromCanned(bucket, object,
+	cannedAcl string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) PutObjectAclFromString(bucket, object, acl string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetObjectAcl(bucket, object string) (*s3.GetObjectAclOutput,
+	error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) UploadPartCopy(bucket, object, srcBucket, srcObject, uploadId,
+	copyRange string, partNumber int64) (*s3.CopyPartResult, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) UploadPartFromBytes(bucket, object, uploadId string,
+	partNumber int, content []byte, input *s3.UploadPartInput) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) UploadPartFromReader(bucket, object, uploadId string,
+	partNumber int, content io.ReadSeeker, input *s3.UploadPartInput) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) InitiateMultipartUpload(bucket, object, contentType,
+	storageClass string, meta map[string]*string) (string, error) {
+	return "", errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) AbortMultipartUpload(bucket, object, uploadId string) error {
+	return errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) CompleteMultipartUploadFromStruct(bucket, object,
+	uploadId string, parts *s3.CompletedMultipartUpload) (*s3.CompleteMultipartUploadOutput,
+	error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetObject(bucket, object string,
+	responseHeaders map[string]string, ranges ...int64) (*s3.GetObjectOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) ListParts(bucket, object, uploadId string,
+	partNumberMarker int64, maxParts int) (*s3.ListPartsOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) ListMultipartUploads(bucket, prefix, keyMarker,
+	uploadIdMarker string, maxUploads int) (*s3.ListMultipartUploadsOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+func (b *fakeUnsupportedBosClient) GetObjectIfMatch(bucket, object, etag string, rangeStart,
+	rangeEnd int64) (*s3.GetObjectOutput, error) {
+	return nil, errFakeNotSupport
+}
+
+// object in the result of list objects, lastModified is formatted as BOS_TIME_FORMT
+func fakeObject(key, lastModified string, size int64, storageClass string) *s3.Object {
+	mtime, err := time.Parse(BOS_TIME_FORMT, lastModified)
+	if err != nil {
+		panic(err)
+	}
+	return &s3.Object{
+		Key:          aws.String(key),
+		LastModified: &mtime,
+		Size:         aws.Int64(size),
+		StorageClass: aws.String(storageClass),
+	}
+}
+
+func fakePrefix(prefix string) *s3.CommonPrefix {
+	return &s3.CommonPrefix{Prefix: aws.String(prefix)}
+}
+
+// the result of head object, lastModified is formatted as BOS_HTTP_TIME_FORMT
+func fakeObjectMeta(lastModified string, contentLength int64,
+	storageClass string) *s3.HeadObjectOutput {
+
+	mtime, err := time.Parse(BOS_HTTP_TIME_FORMT, lastModified)
+	if err != nil {
+		panic(err)
+	}
+	return &s3.HeadObjectOutput{
+		LastModified:  &mtime,
+		ContentLength: aws.Int64(contentLength),
+		StorageClass:  aws.String(storageClass),
+	}
+}
+
+// A fake of the bos service which keeps objects in memory. The tests of copy and sync between
+// remotes read the bucket cli-test from it.
+type fakeRemoteBosClient struct {
+	fakeUnsupportedBosClient
+	buckets map[string][]*s3.Object
+}
+
+func newFakeRemoteBosClient() *fakeRemoteBosClient {
+	b := &fakeRemoteBosClient{buckets: make(map[string][]*s3.Object)}
+	b.addObject("cli-test", "bce", "2017-11-02T15:04:05Z", 1024, "STANDARD")
+	for i := 0; i < 8; i++ {
+		b.addObject("cli-test", fmt.Sprintf("progress/file%d", i), "2017-11-02T15:04:05Z",
+			int64(100*(i+1)), "STANDARD")
+		b.addObject("0", fmt.Sprintf("a/old%d", i), "2016-11-02T15:04:05Z", 100, "STANDARD")
+	}
+	for _, bucket := range []string{"bucekt", "bucket", "dstBucket"} {
+		b.buckets[bucket] = nil
+	}
+	return b
+}
+
+func (b *fakeRemoteBosClient) addObject(bucket, key, lastModified string, size int64,
+	storageClass string) {
+	objects := append(b.buckets[bucket], fakeObject(key, lastModified, size, storageClass))
+	sort.Slice(objects, func(i, j int) bool { return *objects[i].Key < *objects[j].Key })
+	b.buckets[bucket] = objects
+}
+
+func (b *fakeRemoteBosClient) HeadBucket(bucket string) error {
+	if _, ok := b.buckets[bucket]; !ok {
+		return awserr.New(boscmd.CODE_NO_SUCH_BUCKET, "", nil)
+	}
+	return nil
+}
+
+func (b *fakeRemoteBosClient) ListObjects(bucket, delimiter, marker, prefix string,
+	maxKeys int) (*s3.ListObjectsOutput, error) {
+
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return nil, awserr.New(boscmd.CODE_NO_SUCH_BUCKET, "", nil)
+	}
+	ret := &s3.ListObjectsOutput{IsTruncated: aws.Bool(false)}
+	prefixes := make(map[string]bool)
+	for _, object := range objects {
+		key := *object.Key
+		if key <= marker || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if len(ret.Contents)+len(ret.CommonPrefixes) == maxKeys {
+			ret.IsTruncated = aws.Bool(true)
+			break
+		}
+		if pos := strings.Index(key[len(prefix):], delimiter); delimiter != "" && pos >= 0 {
+			commonPrefix := key[:len(prefix)+pos+len(delimiter)]
+			if !prefixes[commonPrefix] {
+				prefixes[commonPrefix] = true
+				ret.CommonPrefixes = append(ret.CommonPrefixes, fakePrefix(commonPrefix))
+			}
+		} else {
+			ret.Contents = append(ret.Contents, object)
+		}
+		ret.NextMarker = aws.String(key)
+	}
+	return ret, nil
+}
+
+func (b *fakeRemoteBosClient) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput,
+	error) {
+
+	objects, ok := b.buckets[bucket]
+	if !ok {
+		return nil, awserr.New(boscmd.CODE_NO_SUCH_BUCKET, "", nil)
+	}
+	for _, item := range objects {
+		if *item.Key == object {
+			return &s3.HeadObjectOutput{
+				LastModified:  item.LastModified,
+				ContentLength: item.Size,
+				StorageClass:  item.StorageClass,
+			}, nil
+		}
+	}
+	return nil, awserr.New(boscmd.CODE_NO_SUCH_KEY, "", nil)
+}
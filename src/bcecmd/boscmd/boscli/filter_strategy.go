@@ -11,14 +11,29 @@
 // and limitations under the License.
 
 // This module provides the filter strategy for pattern exclude and time filter.
+// The patterns read from --exclude-from and --include-from have gitignore semantics, and are
+// matched relative to the source path.
+// The regular expressions of --regex-exclude and --regex-include are matched with the full path.
+// The tags of --tag-filter are only available for objects on BOS, an object is kept only when
+// it has all the tags. The tags are got by a request for every object, after the pattern and
+// time filters, so only the objects kept by them cost a request.
 
 // time range [start time, end time]
 
 package boscli
 
 import (
-	// 	"fmt"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 import (
@@ -26,11 +41,35 @@ import (
 	"utils/util"
 )
 
+const (
+	TIME_RANGE_SEPARATOR = ","
+)
+
 type timeRange struct {
 	start int64
 	end   int64
 }
 
+// The options of path filter and time filter, which are shared by ls, cp, rm and sync
+type FilterArgs struct {
+	Exclude      []string
+	Include      []string
+	ExcludeTime  []string
+	IncludeTime  []string
+	ExcludeFrom  []string
+	IncludeFrom  []string
+	RegexExclude []string
+	RegexInclude []string
+	TagFilter    []string
+}
+
+// whether there is no filter specified
+func (f *FilterArgs) isEmpty() bool {
+	return f == nil || (len(f.Exclude) == 0 && len(f.Include) == 0 && len(f.ExcludeTime) == 0 &&
+		len(f.IncludeTime) == 0 && len(f.ExcludeFrom) == 0 && len(f.IncludeFrom) == 0 &&
+		len(f.RegexExclude) == 0 && len(f.RegexInclude) == 0 && len(f.TagFilter) == 0)
+}
+
 type bosFilter struct {
 	pathFilterIsInclude bool
 	timeFilterIsInclude bool
@@ -39,6 +78,11 @@ type bosFilter struct {
 	patterns            []string
 	timeRanges          []timeRange
 	pathSeparator       string
+	fileRulesIsInclude  bool
+	fileRules           *ignoreMatcher
+	regexIsInclude      bool
+	regexps             []*regexp.Regexp
+	tags                map[string]string
 }
 
 func getAbsPattern(pattern string) (string, error) {
@@ -67,6 +111,195 @@ func newSyncFilter(exclude, include, excludeTime, includeTime []string,
 	return filter, BOSCLI_OK, nil
 }
 
+// Generate filter from filter args, return nil when there is no filter specified
+// srcPath is the local path or BOS path to be filtered, the patterns read from files are
+// matched relative to it.
+func newFilterFromArgs(args *FilterArgs, srcPath string) (*bosFilter, BosCliErrorCode, error) {
+	if args.isEmpty() {
+		return nil, BOSCLI_OK, nil
+	}
+	if len(args.Exclude) > 0 && len(args.Include) > 0 {
+		return nil, BOSCLI_SYNC_EXCLUDE_INCLUDE_TOG, fmt.Errorf("exclude and include " +
+			"cannot be used together")
+	}
+	if len(args.ExcludeFrom) > 0 && len(args.IncludeFrom) > 0 {
+		return nil, BOSCLI_SYNC_EXCLUDE_INCLUDE_TOG, fmt.Errorf("exclude-from and " +
+			"include-from cannot be used together")
+	}
+	if len(args.RegexExclude) > 0 && len(args.RegexInclude) > 0 {
+		return nil, BOSCLI_SYNC_EXCLUDE_INCLUDE_TOG, fmt.Errorf("regex-exclude and " +
+			"regex-include cannot be used together")
+	}
+	if len(args.ExcludeTime) > 0 && len(args.IncludeTime) > 0 {
+		return nil, BOSCLI_SYNC_EXCLUDE_INCLUDE_TIME_TOG, fmt.Errorf("exclude time and " +
+			"include time cannot be used together")
+	}
+
+	srcIsLocal := !isRemotePath(srcPath)
+	if len(args.TagFilter) > 0 && srcIsLocal {
+		return nil, BOSCLI_TAG_FILTER_ONLY_FOR_BOS, fmt.Errorf("tag filter only works for " +
+			"objects on BOS")
+	}
+	filter, retCode, err := newSyncFilter(args.Exclude, args.Include, args.ExcludeTime,
+		args.IncludeTime, srcIsLocal)
+	if retCode != BOSCLI_OK {
+		return nil, retCode, err
+	}
+	if srcIsLocal {
+		filter.pathSeparator = util.OsPathSeparator
+	} else {
+		filter.pathSeparator = boscmd.BOS_PATH_SEPARATOR
+	}
+
+	if retCode, err := filter.setFileRules(args.ExcludeFrom, args.IncludeFrom, srcPath,
+		srcIsLocal); retCode != BOSCLI_OK {
+		return nil, retCode, err
+	}
+
+	if retCode, err := filter.setRegexps(args.RegexExclude, args.RegexInclude); retCode !=
+		BOSCLI_OK {
+		return nil, retCode, err
+	}
+
+	if retCode, err := filter.setTags(args.TagFilter); retCode != BOSCLI_OK {
+		return nil, retCode, err
+	}
+	return filter, BOSCLI_OK, nil
+}
+
+// Set the tags of tag filter, every item is "k1=v1,k2=v2"
+func (b *bosFilter) setTags(tagFilter []string) (BosCliErrorCode, error) {
+	for _, tags := range tagFilter {
+		tagSet, err := parseTags(tags, OBJECT_MAX_TAG_COUNT)
+		if err != nil {
+			return BOSCLI_TAGS_IS_INVALID, err
+		}
+		if b.tags == nil {
+			b.tags = make(map[string]string)
+		}
+		for _, tag := range tagSet {
+			key, value := *tag.Key, *tag.Value
+			if val, ok := b.tags[key]; ok && val != value {
+				return BOSCLI_TAGS_IS_INVALID, fmt.Errorf("tag filter has different values "+
+					"of key '%s'", key)
+			}
+			b.tags[key] = value
+		}
+	}
+	return BOSCLI_OK, nil
+}
+
+// whether the tags of objects are required by the filter
+func (b *bosFilter) tagFilterEnabled() bool {
+	return len(b.tags) > 0
+}
+
+// Filter with the tags of object, objects without all the tags are filtered.
+func (b *bosFilter) TagFilter(tagSet []*s3.Tag) bool {
+	return !hasAllTags(tagSet, b.tags)
+}
+
+// Set the regular expressions of regex-exclude or regex-include
+func (b *bosFilter) setRegexps(regexExclude, regexInclude []string) (BosCliErrorCode, error) {
+	regexTemp := regexExclude
+	if len(regexInclude) > 0 {
+		regexTemp = regexInclude
+		b.regexIsInclude = true
+	}
+
+	for _, expr := range regexTemp {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return BOSCLI_REGEX_IS_INVALID, fmt.Errorf("Invalid regular expression: %s, error: %s",
+				expr, err)
+		}
+		b.regexps = append(b.regexps, re)
+	}
+	return BOSCLI_OK, nil
+}
+
+// Filter with the regular expressions of regex-exclude or regex-include
+// directories are never filtered by regex-include, because their children may be included.
+func (b *bosFilter) regexFilter(bosPath string) bool {
+	if len(b.regexps) == 0 {
+		return false
+	}
+	matched := false
+	for _, re := range b.regexps {
+		if re.MatchString(bosPath) {
+			matched = true
+			break
+		}
+	}
+	if b.regexIsInclude {
+		return !matched && !strings.HasSuffix(bosPath, b.pathSeparator)
+	}
+	return matched
+}
+
+// Set the rules read from the files of exclude-from or include-from
+func (b *bosFilter) setFileRules(excludeFrom, includeFrom []string, srcPath string,
+	srcIsLocal bool) (BosCliErrorCode, error) {
+
+	var (
+		baseDir   string
+		filesTemp []string
+		err       error
+	)
+
+	if len(includeFrom) > 0 {
+		filesTemp = includeFrom
+		b.fileRulesIsInclude = true
+	} else {
+		filesTemp = excludeFrom
+	}
+	if len(filesTemp) == 0 {
+		return BOSCLI_OK, nil
+	}
+
+	// rules are matched relative to the source path
+	if srcIsLocal {
+		if baseDir, err = util.Abs(srcPath); err != nil {
+			return BOSCLI_EMPTY_CODE, err
+		}
+		if !util.DoesDirExist(baseDir) {
+			baseDir = filepath.Dir(baseDir)
+		}
+	} else {
+		baseDir = FilterPrefixOfBosPath(srcPath)
+	}
+	if !strings.HasSuffix(baseDir, b.pathSeparator) {
+		baseDir += b.pathSeparator
+	}
+
+	b.fileRules = &ignoreMatcher{baseDir: baseDir}
+	for _, fileName := range filesTemp {
+		rules, err := readIgnoreRules(fileName)
+		if err != nil {
+			return BOSCLI_EMPTY_CODE, fmt.Errorf("read patterns from %s failed, error: %s",
+				fileName, err)
+		}
+		b.fileRules.rules = append(b.fileRules.rules, rules...)
+	}
+	return BOSCLI_OK, nil
+}
+
+// Filter with the rules read from exclude-from or include-from
+// directories are never filtered by include-from, because their children may be included.
+func (b *bosFilter) fileRulesFilter(bosPath string) (bool, error) {
+	if b.fileRules == nil {
+		return false, nil
+	}
+	ignored, err := b.fileRules.isIgnored(bosPath, b.pathSeparator)
+	if err != nil {
+		return false, err
+	}
+	if b.fileRulesIsInclude {
+		return !ignored && !strings.HasSuffix(bosPath, b.pathSeparator), nil
+	}
+	return ignored, nil
+}
+
 // Set exclude or include
 func (b *bosFilter) setPatterns(exclude, include []string, srcIsLocal bool) (BosCliErrorCode,
 	error) {
@@ -128,6 +361,14 @@ func (b *bosFilter) setTimeRanges(excludeTime, includeTime []string) (BosCliErro
 		timeTemp = excludeTime
 	}
 
+	for _, timeStr := range timeTemp {
+		tRange, err := parseTimeRange(timeStr)
+		if err != nil {
+			return BOSCLI_TIME_RANGE_IS_INVALID, err
+		}
+		b.timeRanges = append(b.timeRanges, tRange)
+	}
+
 	if len(timeTemp) > 0 {
 		b.timeFilterEnabled = true
 	}
@@ -135,11 +376,61 @@ func (b *bosFilter) setTimeRanges(excludeTime, includeTime []string) (BosCliErro
 	return BOSCLI_OK, nil
 }
 
+// Parse time range from string "start,end", the start or end time can be unix timestamp or UTC
+// time like 2006-01-02T15:04:05Z, when the start or end is empty, the range is unlimited.
+func parseTimeRange(timeStr string) (timeRange, error) {
+	var (
+		tRange timeRange
+		err    error
+	)
+
+	times := strings.Split(timeStr, TIME_RANGE_SEPARATOR)
+	if len(times) != 2 {
+		return tRange, fmt.Errorf("Invalid time range: %s, time range must be 'start,end'",
+			timeStr)
+	}
+
+	if tRange.start, err = parseTimePoint(times[0], 0); err != nil {
+		return tRange, err
+	}
+	if tRange.end, err = parseTimePoint(times[1], math.MaxInt64); err != nil {
+		return tRange, err
+	}
+	if tRange.start > tRange.end {
+		return tRange, fmt.Errorf("Invalid time range: %s, start time is later than end time",
+			timeStr)
+	}
+	return tRange, nil
+}
+
+// Parse a time point to unix timestamp, return defaultVal when timeStr is empty
+func parseTimePoint(timeStr string, defaultVal int64) (int64, error) {
+	timeStr = strings.TrimSpace(timeStr)
+	if timeStr == "" {
+		return defaultVal, nil
+	}
+	if timestamp, err := strconv.ParseInt(timeStr, 10, 64); err == nil {
+		return timestamp, nil
+	}
+	if utcTime, err := time.Parse(BOS_TIME_FORMT, timeStr); err == nil {
+		return utcTime.Unix(), nil
+	}
+	return 0, fmt.Errorf("Invalid time: %s, time must be unix timestamp or UTC time like %s",
+		timeStr, BOS_TIME_FORMT)
+}
+
 // Filter with path pattern
 func (b *bosFilter) PatternFilter(bosPath string) (bool, error) {
 	// if path have bos prefix, remove bos prefix
 
 	bosPath = FilterPrefixOfBosPath(bosPath)
+	if filtered, err := b.fileRulesFilter(bosPath); filtered || err != nil {
+		return filtered, err
+	}
+	if b.regexFilter(bosPath) {
+		return true, nil
+	}
+
 	for _, pattern := range b.patterns {
 		if strings.HasSuffix(bosPath, b.pathSeparator) && !strings.HasSuffix(pattern,
 			b.pathSeparator) {
@@ -166,13 +457,22 @@ func (b *bosFilter) PatternFilter(bosPath string) (bool, error) {
 
 // Only work for exclude local file with pattern
 func (b *bosFilter) ExcludePatternFilter(bosPath string) (bool, error) {
+	// if path have bos prefix, remove bos prefix
+	bosPath = FilterPrefixOfBosPath(bosPath)
+
+	if !b.fileRulesIsInclude {
+		if filtered, err := b.fileRulesFilter(bosPath); filtered || err != nil {
+			return filtered, err
+		}
+	}
+	if !b.regexIsInclude && b.regexFilter(bosPath) {
+		return true, nil
+	}
+
 	if b.pathFilterIsInclude {
 		return false, nil
 	}
 
-	// if path have bos prefix, remove bos prefix
-	bosPath = FilterPrefixOfBosPath(bosPath)
-
 	for _, pattern := range b.patterns {
 		if strings.HasSuffix(bosPath, b.pathSeparator) && !strings.HasSuffix(pattern,
 			b.pathSeparator) {
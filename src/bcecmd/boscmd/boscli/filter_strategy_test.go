@@ -16,6 +16,7 @@ import (
 	// 	"fmt"
 	// 	"os"
 	// 	"runtime"
+	"math"
 	"strings"
 	"testing"
 	// 	"time"
@@ -425,3 +426,257 @@ func TestPatternFilter(t *testing.T) {
 		}
 	}
 }
+
+type parseTimeRangeType struct {
+	timeStr string
+	start   int64
+	end     int64
+	isSuc   bool
+}
+
+func TestParseTimeRange(t *testing.T) {
+	testCases := []parseTimeRangeType{
+		//1
+		parseTimeRangeType{
+			timeStr: "100,200",
+			start:   100,
+			end:     200,
+			isSuc:   true,
+		},
+		//2
+		parseTimeRangeType{
+			timeStr: "2018-01-01T00:00:00Z,2019-01-01T00:00:00Z",
+			start:   1514764800,
+			end:     1546300800,
+			isSuc:   true,
+		},
+		//3
+		parseTimeRangeType{
+			timeStr: "100,",
+			start:   100,
+			end:     math.MaxInt64,
+			isSuc:   true,
+		},
+		//4
+		parseTimeRangeType{
+			timeStr: ",2018-01-01T00:00:00Z",
+			start:   0,
+			end:     1514764800,
+			isSuc:   true,
+		},
+		//5
+		parseTimeRangeType{
+			timeStr: "200,100",
+			isSuc:   false,
+		},
+		//6
+		parseTimeRangeType{
+			timeStr: "100",
+			isSuc:   false,
+		},
+		//7
+		parseTimeRangeType{
+			timeStr: "abc,200",
+			isSuc:   false,
+		},
+	}
+
+	for i, tCase := range testCases {
+		tRange, err := parseTimeRange(tCase.timeStr)
+		util.ExpectEqual("filter_strategy parseTimeRange I", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if err != nil || !tCase.isSuc {
+			continue
+		}
+		util.ExpectEqual("filter_strategy parseTimeRange II", i+1, t.Errorf, tCase.start,
+			tRange.start)
+		util.ExpectEqual("filter_strategy parseTimeRange III", i+1, t.Errorf, tCase.end,
+			tRange.end)
+	}
+}
+
+type newFilterFromArgsType struct {
+	args     *FilterArgs
+	retCode  BosCliErrorCode
+	isNil    bool
+	timeIncl bool
+}
+
+func TestNewFilterFromArgs(t *testing.T) {
+	testCases := []newFilterFromArgsType{
+		//1
+		newFilterFromArgsType{
+			args:    nil,
+			retCode: BOSCLI_OK,
+			isNil:   true,
+		},
+		//2
+		newFilterFromArgsType{
+			args:    &FilterArgs{},
+			retCode: BOSCLI_OK,
+			isNil:   true,
+		},
+		//3
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				Exclude: []string{"*.jpg"},
+				Include: []string{"*.png"},
+			},
+			retCode: BOSCLI_SYNC_EXCLUDE_INCLUDE_TOG,
+			isNil:   true,
+		},
+		//4
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				ExcludeTime: []string{"100,200"},
+				IncludeTime: []string{"300,400"},
+			},
+			retCode: BOSCLI_SYNC_EXCLUDE_INCLUDE_TIME_TOG,
+			isNil:   true,
+		},
+		//5
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				IncludeTime: []string{"300,"},
+			},
+			retCode:  BOSCLI_OK,
+			timeIncl: true,
+		},
+		//6
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				ExcludeTime: []string{"300"},
+			},
+			retCode: BOSCLI_TIME_RANGE_IS_INVALID,
+			isNil:   true,
+		},
+		//7
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				Exclude: []string{"bos:/bucket/*.jpg"},
+			},
+			retCode: BOSCLI_OK,
+		},
+		//8
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				ExcludeFrom: []string{"exclude.txt"},
+				IncludeFrom: []string{"include.txt"},
+			},
+			retCode: BOSCLI_SYNC_EXCLUDE_INCLUDE_TOG,
+			isNil:   true,
+		},
+		//9
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				ExcludeFrom: []string{"./not-exist-exclude-file"},
+			},
+			retCode: BOSCLI_EMPTY_CODE,
+			isNil:   true,
+		},
+		//10
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				RegexExclude: []string{"\\.log$"},
+				RegexInclude: []string{"\\.jpg$"},
+			},
+			retCode: BOSCLI_SYNC_EXCLUDE_INCLUDE_TOG,
+			isNil:   true,
+		},
+		//11
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				RegexExclude: []string{"(\\.log$"},
+			},
+			retCode: BOSCLI_REGEX_IS_INVALID,
+			isNil:   true,
+		},
+		//12
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				TagFilter: []string{"env=prod", "team=web,env=prod"},
+			},
+			retCode: BOSCLI_OK,
+		},
+		//13
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				TagFilter: []string{"env=prod", "env=test"},
+			},
+			retCode: BOSCLI_TAGS_IS_INVALID,
+			isNil:   true,
+		},
+		//14
+		newFilterFromArgsType{
+			args: &FilterArgs{
+				TagFilter: []string{"env"},
+			},
+			retCode: BOSCLI_TAGS_IS_INVALID,
+			isNil:   true,
+		},
+	}
+
+	for i, tCase := range testCases {
+		filter, retCode, _ := newFilterFromArgs(tCase.args, "bos:/bucket/")
+		util.ExpectEqual("filter_strategy newFilterFromArgs I", i+1, t.Errorf, tCase.retCode,
+			retCode)
+		util.ExpectEqual("filter_strategy newFilterFromArgs II", i+1, t.Errorf, tCase.isNil,
+			filter == nil)
+		if filter == nil {
+			continue
+		}
+		util.ExpectEqual("filter_strategy newFilterFromArgs III", i+1, t.Errorf, tCase.timeIncl,
+			filter.timeFilterEnabled && filter.timeFilterIsInclude)
+	}
+}
+
+type regexFilterType struct {
+	args     *FilterArgs
+	bosPath  string
+	filtered bool
+}
+
+func TestRegexFilter(t *testing.T) {
+	testCases := []regexFilterType{
+		//1
+		regexFilterType{
+			args:     &FilterArgs{RegexExclude: []string{"\\.(log|tmp)$"}},
+			bosPath:  "bos:/bucket/dir/a.log",
+			filtered: true,
+		},
+		//2
+		regexFilterType{
+			args:     &FilterArgs{RegexExclude: []string{"\\.(log|tmp)$"}},
+			bosPath:  "bos:/bucket/dir/a.jpg",
+			filtered: false,
+		},
+		//3
+		regexFilterType{
+			args:     &FilterArgs{RegexInclude: []string{"^bucket/20[0-9]{2}/"}},
+			bosPath:  "bos:/bucket/2018/a.jpg",
+			filtered: false,
+		},
+		//4
+		regexFilterType{
+			args:     &FilterArgs{RegexInclude: []string{"^bucket/20[0-9]{2}/"}},
+			bosPath:  "bos:/bucket/1999/a.jpg",
+			filtered: true,
+		},
+		//5 globstar of exclude pattern
+		regexFilterType{
+			args:     &FilterArgs{Exclude: []string{"bos:/bucket/**/cache/*"}},
+			bosPath:  "bos:/bucket/cache/a.jpg",
+			filtered: true,
+		},
+	}
+
+	for i, tCase := range testCases {
+		filter, retCode, err := newFilterFromArgs(tCase.args, "bos:/bucket/")
+		if retCode != BOSCLI_OK {
+			t.Errorf("ID: %d, new filter failed, error: %v", i+1, err)
+			continue
+		}
+		filtered, err := filter.PatternFilter(tCase.bosPath)
+		util.ExpectEqual("filter_strategy regexFilter I", i+1, t.Errorf, true, err == nil)
+		util.ExpectEqual("filter_strategy regexFilter II", i+1, t.Errorf, tCase.filtered, filtered)
+	}
+}
@@ -15,6 +15,7 @@
 package boscli
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -24,20 +25,29 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 import (
 	"bcecmd/boscmd"
-	"bceconf"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/baidubce/bce-sdk-go/util/log"
 	"utils/util"
 )
 
 const (
-	MAX_DELETE_NUM_EACH_TIME = 500
+	// delete objects in batches of DeleteObjects, and batches are deleted in parallel
+	MAX_DELETE_NUM_EACH_TIME = MAX_DELETE_OBJECTS_EACH_REQUEST * PARALLEL_DELETE_REQUEST_NUM
+
+	// the prefix of relay files of copy between remotes
+	RELAY_TMP_FILE_NAME = "bcecmd.relay."
 )
 
+// make sure there is only one prompt at a time when objects are processed concurrently
+var promptLock sync.Mutex
+
 // process upload, download, copy or delete
 type cliHandler struct{}
 
@@ -184,6 +194,18 @@ func (o *objectListIterator) listAllObjects(bucketName, objectKey, marker string
 				continue
 			}
 
+			// tag filter, it is the last filter as the tags of every object are got one by one
+			if o.filter != nil && o.filter.tagFilterEnabled() {
+				tagging, err := o.bosClient.GetObjectTagging(bucketName, *item.Key)
+				if err != nil {
+					o.objectsChan <- &listFileResult{err: err}
+					goto END
+				}
+				if o.filter.TagFilter(tagging.TagSet) {
+					continue
+				}
+			}
+
 			o.objectsChan <- &listFileResult{
 				file: &fileDetail{
 					path:         *item.Key,
@@ -192,6 +214,7 @@ func (o *objectListIterator) listAllObjects(bucketName, objectKey, marker string
 					gtime:        gtime,
 					size:         int64(*item.Size),
 					storageClass: *item.StorageClass,
+					etag:         aws.StringValue(item.ETag),
 				},
 				isDir: false,
 			}
@@ -252,11 +275,20 @@ func multiUploadNeedRetry(err error) bool {
 	return false
 }
 
+// the object is overwritten during multipart download, download it again from scratch
+func multiDownloadNeedRestart(err error) bool {
+	if err == nil {
+		return false
+	}
+	code, ok := GetErrorCode(err)
+	return ok && code == boscmd.CODE_PRECONDITION_FAILED
+}
+
 // Delete whole directory
 // Return:
 //       number of success deleted objects
 //       error infomation
-func (h *cliHandler) multiDeleteDir(bosClient bosClientInterface, bucketName,
+func (h *cliHandler) multiDeleteDir(bosClient bosClientInterface, filter *bosFilter, bucketName,
 	objectKey string) (int, error) {
 
 	var (
@@ -269,8 +301,8 @@ func (h *cliHandler) multiDeleteDir(bosClient bosClientInterface, bucketName,
 		successDeleteNum    int
 	)
 
-	objectLi := NewObjectListIterator(bosClient, nil, bucketName, objectKey, "", true, true, true,
-		true, MAX_DELETE_NUM_EACH_TIME)
+	objectLi := NewObjectListIterator(bosClient, filter, bucketName, objectKey, "", true, true,
+		true, true, MAX_DELETE_OBJECTS_EACH_REQUEST)
 
 	for {
 		listResult, err = objectLi.next()
@@ -401,14 +433,20 @@ func (h *cliHandler) utilDeleteObject(bosClient bosClientInterface, bucketName,
 
 // copy single object
 func (h *cliHandler) utilCopyObject(srcBosClient, bosClient bosClientInterface, srcBucketName,
-	srcObjectKey, dstBucketName, dstObjectKey, storageClass string, fileSize, fileMtime,
-	timeOfgetObjectInfo int64, restart bool) error {
+	srcObjectKey, dstBucketName, dstObjectKey, storageClass, downLoadTmp string, fileSize,
+	fileMtime, timeOfgetObjectInfo int64, restart bool) error {
 
-	var (
-		err error
-	)
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return err
+	}
 
-	if fileSize > MULTI_COPY_THRESHOLD {
+	if srcBosClient != nil && srcBosClient != bosClient {
+		// copy between different remotes
+		err = h.relayObject(srcBosClient, bosClient, srcBucketName, srcObjectKey, dstBucketName,
+			dstObjectKey, storageClass, downLoadTmp, fileSize, fileMtime, timeOfgetObjectInfo,
+			restart)
+	} else if fileSize > tuning.Copy.Threshold {
 		// multi copy
 		err = h.CopySuperFile(srcBosClient, bosClient, srcBucketName, srcObjectKey, dstBucketName,
 			dstObjectKey, storageClass, fileSize, fileMtime, timeOfgetObjectInfo, restart,
@@ -422,8 +460,10 @@ func (h *cliHandler) utilCopyObject(srcBosClient, bosClient bosClientInterface,
 		}
 	} else {
 		// common copy
+		release := transferSched.acquire(0)
 		_, err = bosClient.CopyObject(dstBucketName, dstObjectKey, srcBucketName, srcObjectKey,
 			storageClass)
+		release()
 	}
 
 	if err == nil {
@@ -433,9 +473,151 @@ func (h *cliHandler) utilCopyObject(srcBosClient, bosClient bosClientInterface,
 	return err
 }
 
-// download an object to local
+// Copy an object between two different remotes.
+// The destination can't read the source directly, so the data of object is downloaded to a
+// relay file in downLoadTmp and then uploaded to destination. The relay file is named by the
+// source and destination, and its modify time is set to the last modified time of source object
+// after downloading, so that a failed relay is resumed: the downloaded relay file of the same
+// source object is reused, and the breakpoint records of its download and upload are found again.
+// The relay file is kept when the relay fails, and removed when the relay succeeds.
+// The transformed upload is staged from the restored relay file, it isn't resumed.
+func (h *cliHandler) relayObject(srcBosClient, bosClient bosClientInterface, srcBucketName,
+	srcObjectKey, dstBucketName, dstObjectKey, storageClass, downLoadTmp string, fileSize,
+	fileMtime, timeOfgetObjectInfo int64, restart bool) error {
+
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := transferTmpDir(downLoadTmp)
+	if err != nil {
+		return err
+	}
+	relayPath := relayFilePath(tmpDir,
+		joinRemotePath(srcBosClient.RemoteName(), srcBucketName, srcObjectKey),
+		joinRemotePath(bosClient.RemoteName(), dstBucketName, dstObjectKey),
+	)
+
+	// need to get the basic information of this object
+	if fileSize == 0 && fileMtime == 0 {
+		ret, err := getObjectMeta(srcBosClient, srcBucketName, srcObjectKey)
+		if err != nil {
+			return err
+		}
+		fileSize = ret.size
+		fileMtime = ret.mtime
+		timeOfgetObjectInfo = ret.gtime
+	}
+
+	// download source object, unless it has been downloaded by a failed relay
+	var meta map[string]*string
+	if !restart && isRelayFileDownloaded(relayPath, fileSize, fileMtime) {
+		ret, err := getObjectMeta(srcBosClient, srcBucketName, srcObjectKey)
+		if err != nil {
+			return err
+		}
+		meta = ret.metadata
+	} else {
+		if fileSize < tuning.Download.Threshold {
+			release := transferSched.acquire(0)
+			meta, err = srcBosClient.BasicGetObjectToFile(srcBucketName, srcObjectKey, relayPath)
+			release()
+		} else {
+			meta, err = h.DownloadSuperFile(srcBosClient, srcBucketName, srcObjectKey,
+				relayPath, "Downloading", "", "", fileSize, fileMtime, timeOfgetObjectInfo,
+				restart)
+			if multiDownloadNeedRestart(err) {
+				meta, err = h.DownloadSuperFile(srcBosClient, srcBucketName, srcObjectKey,
+					relayPath, "Retry Downloading", "", "", fileSize, fileMtime,
+					timeOfgetObjectInfo, true)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		modTime := time.Unix(fileMtime, 0)
+		if err = os.Chtimes(relayPath, modTime, modTime); err != nil {
+			return err
+		}
+	}
+
+	// upload to destination, the content is restored and transformed again when the upload
+	// is transformed, otherwise the content is kept with the metadata of source object
+	var fileInfo *fileDetail
+	uploadPath := relayPath
+	if uploadTransformEnabled() {
+		// the relay file is restored in place, it isn't reused by the next relay any more
+		if err = restoreDownload(meta, relayPath); err != nil {
+			return err
+		}
+		var cleanup func()
+		fileInfo, cleanup, err = stageUpload(relayPath,
+			joinRemotePath(bosClient.RemoteName(), dstBucketName, dstObjectKey), tmpDir)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		uploadPath = fileInfo.path
+	} else if fileInfo, err = getFileMate(relayPath); err != nil {
+		return err
+	} else {
+		fileInfo.metadata = meta
+	}
+	if fileInfo.size > tuning.Upload.Threshold {
+		err = h.UploadSuperFile(bosClient, uploadPath, dstBucketName, dstObjectKey, storageClass,
+			fileInfo.metadata, fileInfo.size, fileInfo.mtime, fileInfo.gtime, restart,
+			"Uploading")
+		if err != nil && multiUploadNeedRetry(err) {
+			// this upload id might have been aborted or completed, so, retry and restart!
+			err = h.UploadSuperFile(bosClient, uploadPath, dstBucketName, dstObjectKey,
+				storageClass, fileInfo.metadata, fileInfo.size, fileInfo.mtime, fileInfo.gtime,
+				true, "Retry Uploading")
+		}
+	} else {
+		release := transferSched.acquire(0)
+		_, err = bosClient.PutObjectFromFile(dstBucketName, dstObjectKey, uploadPath,
+			storageClass, fileInfo.metadata)
+		release()
+	}
+	if err == nil {
+		os.Remove(relayPath)
+	}
+	return err
+}
+
+// The folder of relay files and transformed files to be uploaded, it is downLoadTmp when it is
+// set, otherwise the temporary folder of system.
+func transferTmpDir(downLoadTmp string) (string, error) {
+	if downLoadTmp == "" {
+		return os.TempDir(), nil
+	}
+	if util.DoesFileExist(downLoadTmp) {
+		return "", fmt.Errorf("%s is a file, it should be a directory !", downLoadTmp)
+	} else if !util.DoesDirExist(downLoadTmp) {
+		return "", fmt.Errorf("Temporary folder %s don't exist!", downLoadTmp)
+	}
+	return downLoadTmp, nil
+}
+
+// The relay file in tmpDir of copy from srcPath to dstPath, both of them are remote paths.
+func relayFilePath(tmpDir, srcPath, dstPath string) string {
+	return filepath.Join(tmpDir, RELAY_TMP_FILE_NAME+util.StringMd5(srcPath+"_"+dstPath))
+}
+
+// Whether the relay file is the downloaded source object, whose size and last modified time
+// are fileSize and fileMtime.
+func isRelayFileDownloaded(relayPath string, fileSize, fileMtime int64) bool {
+	info, err := os.Stat(relayPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return info.Size() == fileSize && info.ModTime().Unix() == fileMtime
+}
+
+// download an object to local, etag is the etag of object got by listing
 func (h *cliHandler) utilDownloadObject(bosClient bosClientInterface, srcBucketName, srcObjectKey,
-	dstFilePath, downLoadTmp string, yes bool, fileSize, mtime, timeOfgetObjectInfo int64,
+	dstFilePath, downLoadTmp, etag string, yes bool, fileSize, mtime, timeOfgetObjectInfo int64,
 	restart bool) error {
 
 	var (
@@ -489,7 +671,10 @@ func (h *cliHandler) utilDownloadObject(bosClient bosClientInterface, srcBucketN
 	// check whether need cover local file
 	if util.DoesFileExist(finalFileName) {
 		if !yes {
+			// objects may be downloaded concurrently, only one prompt at a time
+			promptLock.Lock()
 			yes = util.PromptConfirm("Will you cover the existing file %s?", finalFileName)
+			promptLock.Unlock()
 		}
 		if !yes {
 			return fmt.Errorf("Download abort for existing file.")
@@ -507,28 +692,53 @@ func (h *cliHandler) utilDownloadObject(bosClient bosClientInterface, srcBucketN
 		fileSize = ret.size
 		mtime = ret.mtime
 		timeOfgetObjectInfo = ret.gtime
+		etag = ret.etag
+	}
+
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return err
 	}
 
 	// start to download object to local
-	if fileSize < MULTI_DOWNLOAD_THRESHOLD {
+	var meta map[string]*string
+	if fileSize < tuning.Download.Threshold {
 		// download small file
-		err = bosClient.BasicGetObjectToFile(srcBucketName, srcObjectKey, finalFileName)
+		release := transferSched.acquire(0)
+		meta, err = bosClient.BasicGetObjectToFile(srcBucketName, srcObjectKey, finalFileName)
+		release()
 	} else {
 		// download super file
-		err = h.DownloadSuperFile(bosClient, srcBucketName, srcObjectKey, finalFileName,
-			"Downloading", downLoadTmp, fileSize, mtime, timeOfgetObjectInfo, restart)
+		meta, err = h.DownloadSuperFile(bosClient, srcBucketName, srcObjectKey, finalFileName,
+			"Downloading", downLoadTmp, etag, fileSize, mtime, timeOfgetObjectInfo, restart)
+		if multiDownloadNeedRestart(err) {
+			// the etag is stale, the newest one is got by DownloadSuperFile
+			log.Debugf("bos:/%s/%s is overwritten during download, download it again",
+				srcBucketName, srcObjectKey)
+			meta, err = h.DownloadSuperFile(bosClient, srcBucketName, srcObjectKey,
+				finalFileName, "Retry Downloading", downLoadTmp, "", fileSize, mtime,
+				timeOfgetObjectInfo, true)
+		}
 	}
 	if err != nil {
 		return err
 	}
+	// decrypt and decompress by the metadata got with the object
+	if err := restoreDownload(meta, finalFileName); err != nil {
+		// never leave the transformed content as the downloaded file
+		os.Remove(finalFileName)
+		return err
+	}
 	printIfNotQuiet("Download: %s%s/%s to %s\n", BOS_PATH_PREFIX, srcBucketName, srcObjectKey,
 		finalFileName)
 	return nil
 }
 
+// download a large object by parts, return the user metadata of object. etag is the etag of
+// object got by listing, the object is got by HEAD when it is empty.
 func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketName, srcObjectKey,
-	fileName, testPrefix, downLoadTmp string, fileSize, mtime, timeOfgetObjectInfo int64,
-	restart bool) (err error) {
+	fileName, testPrefix, downLoadTmp, etag string, fileSize, mtime, timeOfgetObjectInfo int64,
+	restart bool) (meta map[string]*string, err error) {
 
 	var (
 		content *MultiTaskContent
@@ -543,45 +753,50 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 		}
 	}()
 
-	// get object info again, we need to get the newest info of this object, as this
-	// file may have been modified.
-	if (time.Now().Unix() - timeOfgetObjectInfo) > GAP_GET_OBJECT_INFO_AGAIN {
-		log.Debugf("need get object info again, bos:/%s/%s => %s ", srcBucketName,
-			srcObjectKey, fileName)
-		ret, err := getObjectMeta(bosClient, srcBucketName, srcObjectKey)
+	// get the newest info of this object when there is no etag, as this file may have been
+	// modified, and the etag is needed to identify the object. The listed etag is checked by
+	// every part, the download is restarted when the object has been overwritten.
+	var objectMeta *fileDetail
+	if etag == "" {
+		objectMeta, err = getObjectMeta(bosClient, srcBucketName, srcObjectKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		fileSize = ret.size
-		mtime = ret.mtime
-		timeOfgetObjectInfo = ret.gtime
+		fileSize = objectMeta.size
+		mtime = objectMeta.mtime
+		etag = objectMeta.etag
 	}
 
-	// this file is samll file
-	if fileSize < MULTI_DOWNLOAD_THRESHOLD {
-		return bosClient.BasicGetObjectToFile(srcBucketName, srcObjectKey, fileName)
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return nil, err
 	}
 
-	// get md5 of src object
-	ranges := []int64{0, 1023, fileSize - 1025, fileSize - 1}
-	md5Val, err := h.GetObjectMd5(bosClient, srcBucketName, srcObjectKey, ranges)
-	if err != nil {
-		return err
+	// this file is samll file
+	if fileSize < tuning.Download.Threshold {
+		release := transferSched.acquire(0)
+		defer release()
+		return bosClient.BasicGetObjectToFile(srcBucketName, srcObjectKey, fileName)
 	}
 
-	// get multi download part size
-	multiDownloadPartSize, ok := bceconf.ServerConfigProvider.GetMultiUploadPartSize()
-	if !ok {
-		return fmt.Errorf("There is no info about multi download part size found!")
+	// the object is identified by etag, last modified time and size in breakpoint record, fall
+	// back to md5 of parts of object when there is no etag.
+	fingerprint := etag
+	if fingerprint == "" {
+		ranges := []int64{0, 1023, fileSize - 1025, fileSize - 1}
+		fingerprint, err = h.GetObjectMd5(bosClient, srcBucketName, srcObjectKey, ranges)
+		if err != nil {
+			return nil, err
+		}
 	}
-	multiDownloadPartSizeByte := multiDownloadPartSize * (1 << 20)
 
 	// init object content for breakpoint
-	content = &MultiTaskContent{}
+	content = &MultiTaskContent{srcRemote: bosClient.RemoteName()}
 	err = content.init(srcBucketName, srcObjectKey, "", fileName, IS_BOS, IS_LOCAL,
-		md5Val, fileSize, mtime, restart, multiDownloadPartSizeByte)
+		fingerprint, fileSize, mtime, restart,
+		autoTunePartSize("download", fileSize, tuning.Download.PartSize, tuning.AutoTune))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	util.GFinisher.Insert(content)
@@ -591,9 +806,9 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 		// UploadId is the name of temporary file that stores the intermediate Data
 		if downLoadTmp != "" {
 			if util.DoesFileExist(downLoadTmp) {
-				return fmt.Errorf("%s is a file, it should be a directory !")
+				return nil, fmt.Errorf("%s is a file, it should be a directory !", downLoadTmp)
 			} else if !util.DoesDirExist(downLoadTmp) {
-				return fmt.Errorf("Temporary folder %s don't exist!", downLoadTmp)
+				return nil, fmt.Errorf("Temporary folder %s don't exist!", downLoadTmp)
 			}
 			content.uploadId = filepath.Join(downLoadTmp, "bcecmd.temp."+content.contentId)
 		} else {
@@ -605,7 +820,7 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 	// for progress bar
 	bar, err := util.NewBar(int(content.partsNum+1), testPrefix, Quiet || DisableBar)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	util.GFinisher.Insert(bar)
 	defer func() {
@@ -614,10 +829,15 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 	}()
 	bar.Finish(content.GetFinshPartNum())
 
-	// temp file for save intermediate result
+	// temp file for save intermediate result, the stale one is removed when restart
+	if content.needRestart && util.DoesFileExist(content.uploadId) {
+		if err := os.Remove(content.uploadId); err != nil {
+			return nil, err
+		}
+	}
 	file, err := os.OpenFile(content.uploadId, os.O_WRONLY|os.O_CREATE, 0644)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if file != nil {
@@ -628,26 +848,34 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 	log.Debugf("starting download super file, total parts: %d, part size: %d", content.partsNum,
 		content.partSize)
 
-	// TODO: use diff thread number with  multi-upload
-	multiDownloadThreadNum, ok := bceconf.ServerConfigProvider.GetMultiUploadThreadNum()
-	if !ok {
-		return fmt.Errorf("There is no info about multi download thread Num found!")
-	}
+	// the user metadata is got with parts when there is no HEAD
+	var (
+		partMeta     map[string]*string
+		partMetaOnce sync.Once
+		partMetaGot  bool
+	)
 
 	downloadPart := func(partId int64, rangeStart, rangeEnd, workerId int64, ret chan error,
-		pool chan int64, doneChan chan struct{}) {
-		res, rangeGetErr := bosClient.GetObject(srcBucketName, srcObjectKey, nil, rangeStart,
-			rangeEnd)
+		workers *partWorkers, doneChan chan struct{}, release func()) {
+		defer release()
+		start := time.Now()
+		// the part is got only when the object isn't overwritten during download
+		res, rangeGetErr := bosClient.GetObjectIfMatch(srcBucketName, srcObjectKey, etag,
+			rangeStart, rangeEnd)
 		if rangeGetErr != nil {
 			log.Errorf("download object part(offset:%d, size:%d) failed: %v",
-				rangeStart, res.ContentLength, rangeGetErr)
+				rangeStart, rangeEnd-rangeStart+1, rangeGetErr)
 			ret <- rangeGetErr
 			return
 		}
 		defer res.Body.Close()
+		partMetaOnce.Do(func() {
+			partMeta = res.Metadata
+			partMetaGot = true
+		})
 		log.Debugf("%s writing part %d with offset=%d, size=%d", fileName, partId, rangeStart,
 			res.ContentLength)
-		buf := make([]byte, 1048576)
+		buf := make([]byte, DOWNLOAD_BUFFER_SIZE)
 		offset := rangeStart
 		for {
 			n, e := res.Body.Read(buf)
@@ -667,7 +895,7 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 		log.Debugf("%s writing part %d done", fileName, partId)
 		content.finishPart(partId, strconv.FormatInt(partId, 10))
 		bar.Finish(content.GetFinshPartNum())
-		pool <- workerId
+		workers.put(workerId, rangeEnd-rangeStart+1, start)
 		doneChan <- struct{}{}
 	}
 
@@ -676,7 +904,9 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 		if !ok {
 			return
 		}
-		if code == boscmd.CODE_NO_SUCH_KEY && file != nil {
+		// the object is deleted or overwritten, the downloaded parts are useless
+		if (code == boscmd.CODE_NO_SUCH_KEY || code == boscmd.CODE_PRECONDITION_FAILED) &&
+			file != nil {
 			file.Close()
 			content.Remove()
 		}
@@ -685,11 +915,7 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 	// Set up multiple goroutine workers to download the object
 	doneChan := make(chan struct{}, content.partsNum)
 	retChan := make(chan error)
-	workerPool := make(chan int64, multiDownloadThreadNum)
-
-	for i := int64(0); i < multiDownloadThreadNum; i++ {
-		workerPool <- i
-	}
+	workers := newPartWorkers("download", tuning.Download.ThreadNum, tuning.AutoTune)
 
 	log.Debugf("content partsNum:%d", content.partsNum)
 	for partId := int64(1); partId <= content.partsNum; partId++ {
@@ -707,12 +933,14 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 		rangeEnd--
 
 		select {
-		case workerId := <-workerPool:
+		case workerId := <-workers.pool:
 			log.Debugf("download part partid:%d", partId)
-			go downloadPart(partId, rangeStart, rangeEnd, workerId, retChan, workerPool, doneChan)
+			release := transferSched.acquire(DOWNLOAD_BUFFER_SIZE)
+			go downloadPart(partId, rangeStart, rangeEnd, workerId, retChan, workers, doneChan,
+				release)
 		case downloadErr := <-retChan:
 			afterDownPartFail(downloadErr)
-			return downloadErr
+			return nil, downloadErr
 		}
 	}
 
@@ -723,44 +951,78 @@ func (h *cliHandler) DownloadSuperFile(bosClient bosClientInterface, srcBucketNa
 			continue
 		case downloadErr := <-retChan:
 			afterDownPartFail(downloadErr)
-			return downloadErr
+			return nil, downloadErr
+		}
+	}
+
+	// all parts have been downloaded before, the user metadata is got by HEAD
+	if objectMeta == nil && !partMetaGot {
+		objectMeta, err = getObjectMeta(bosClient, srcBucketName, srcObjectKey)
+		if err != nil {
+			return nil, err
 		}
 	}
+	if objectMeta != nil {
+		partMeta = objectMeta.metadata
+	}
 
 	// fail to close file, does need to remove temp file ?
 	if err := file.Close(); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := os.Rename(content.uploadId, fileName); err != nil {
-		return err
+		return nil, err
 	}
 	file = nil
 	bar.Finish(content.GetFinshPartNum() + 1)
 	content.complete()
-	return nil
+	return partMeta, nil
 }
 
 // upload a file
 func (h *cliHandler) utilUploadFile(bosClient bosClientInterface, srcPath, relSrcPath,
-	dstBucketName, dstObjectKey, storageClass string, fileSize, fileMtime,
+	dstBucketName, dstObjectKey, storageClass, downLoadTmp string, fileSize, fileMtime,
 	timeOfgetObjectInfo int64, restart bool) error {
 
-	var (
-		err error
-	)
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return err
+	}
 
-	if fileSize > MULTI_UPLOAD_THRESHOLD {
+	// upload the transformed temporary file instead, with the metadata of transformation
+	var meta map[string]*string
+	if uploadTransformEnabled() {
+		tmpDir, err := transferTmpDir(downLoadTmp)
+		if err != nil {
+			return err
+		}
+		fileInfo, cleanup, err := stageUpload(relSrcPath,
+			joinRemotePath(bosClient.RemoteName(), dstBucketName, dstObjectKey), tmpDir)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		relSrcPath = fileInfo.path
+		fileSize, fileMtime, timeOfgetObjectInfo = fileInfo.size, fileInfo.mtime, fileInfo.gtime
+		meta = fileInfo.metadata
+	}
+
+	if fileSize > tuning.Upload.Threshold {
 		err = h.UploadSuperFile(bosClient, relSrcPath, dstBucketName, dstObjectKey, storageClass,
-			fileSize, fileMtime, timeOfgetObjectInfo, restart, "Uploading")
+			meta, fileSize, fileMtime, timeOfgetObjectInfo, restart, "Uploading")
 		if err != nil && multiUploadNeedRetry(err) {
 			//this upload id might have been aborted or completed, so, retry and restart!
 			err = h.UploadSuperFile(bosClient, relSrcPath, dstBucketName, dstObjectKey,
-				storageClass, fileSize, fileMtime, timeOfgetObjectInfo, true, "Retry Uploading")
+				storageClass, meta, fileSize, fileMtime, timeOfgetObjectInfo, true,
+				"Retry Uploading")
 		}
 	} else {
 		// TODO putObject of go sdk don't have interface for storage-class
-		_, err = bosClient.PutObjectFromFile(dstBucketName, dstObjectKey, relSrcPath, storageClass)
+		release := transferSched.acquire(0)
+		_, err = bosClient.PutObjectFromFile(dstBucketName, dstObjectKey, relSrcPath, storageClass,
+			meta)
+		release()
 	}
 
 	if err != nil {
@@ -771,10 +1033,11 @@ func (h *cliHandler) utilUploadFile(bosClient bosClientInterface, srcPath, relSr
 	return nil
 }
 
-// UploadSuperFile - parallel upload the super file by using the multipart upload interface
+// UploadSuperFile - parallel upload the super file by using the multipart upload interface, meta
+// is the user metadata of object
 func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstBucketName,
-	dstObjectKey, storageClass string, fileSize, mtime, timeOfgetObjectInfo int64,
-	restart bool, testPrefix string) error {
+	dstObjectKey, storageClass string, meta map[string]*string, fileSize, mtime,
+	timeOfgetObjectInfo int64, restart bool, testPrefix string) error {
 
 	var (
 		content *MultiTaskContent
@@ -805,8 +1068,16 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 		timeOfgetObjectInfo = ret.gtime
 	}
 
-	if fileSize < MULTI_UPLOAD_THRESHOLD {
-		_, err := bosClient.PutObjectFromFile(dstBucketName, dstObjectKey, srcPath, storageClass)
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return err
+	}
+
+	if fileSize < tuning.Upload.Threshold {
+		release := transferSched.acquire(0)
+		defer release()
+		_, err := bosClient.PutObjectFromFile(dstBucketName, dstObjectKey, srcPath, storageClass,
+			meta)
 		return err
 	}
 
@@ -823,31 +1094,42 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 		return err
 	}
 
-	// get multi upload part size
-	multiUploadPartSize, ok := bceconf.ServerConfigProvider.GetMultiUploadPartSize()
-	if !ok {
-		return fmt.Errorf("There is no info about multi upload part size found!")
-	}
-	multiUploadPartSizeByte := multiUploadPartSize * (1 << 20)
-
 	// init object content for breakpoint
-	content = &MultiTaskContent{}
+	content = &MultiTaskContent{dstRemote: bosClient.RemoteName()}
 	err = content.init("", srcPath, dstBucketName, dstObjectKey, IS_LOCAL, IS_BOS,
-		md5Val, fileSize, mtime, restart, multiUploadPartSizeByte)
+		md5Val, fileSize, mtime, restart,
+		autoTunePartSize("upload", fileSize, tuning.Upload.PartSize, tuning.AutoTune))
 	if err != nil {
 		return err
 	}
 
 	util.GFinisher.Insert(content)
 
+	// the parts on server are authoritative, the breakpoint record may be lost, expired or
+	// out of date
+	var serverParts []*s3.Part
+	if !content.needRestart {
+		serverParts, err = listAllParts(bosClient, dstBucketName, dstObjectKey, content.uploadId)
+	} else if canResumeFromServer(restart, meta) {
+		serverParts, err = content.resumeFromServerUpload(bosClient, storageClass)
+	} else if resumeFromServer {
+		log.Debugf("%s => %s, don't resume upload on server", content.srcFilePath,
+			content.dstFilePath)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Do the parallel multipart upload
 	if content.needRestart {
 		uploadId, err := bosClient.InitiateMultipartUpload(dstBucketName, dstObjectKey, "",
-			storageClass)
+			storageClass, meta)
 		if err != nil {
 			return err
 		}
 		content.uploadId = uploadId
+	} else if err := content.reconcileParts(serverParts, fd); err != nil {
+		return err
 	}
 
 	// for progress bar
@@ -864,13 +1146,17 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 	bar.Finish(content.GetFinshPartNum())
 
 	// Inner wrapper function of parallel uploading each part to get the ETag of the part
-	uploadPart := func(bucket, object, uploadId string, partNumber int64, partBody []byte,
-		result chan *CompletedPart, ret chan error, id int64, pool chan int64) {
+	uploadPart := func(bucket, object, uploadId string, partNumber, offset, size int64,
+		buffered bool, result chan *CompletedPart, ret chan error, id int64,
+		workers *partWorkers, release func()) {
 		log.Debugf("%s => bos:/%s/%s start upload partNumber %d\n", srcPath, dstBucketName,
 			dstObjectKey, partNumber)
+		start := time.Now()
+		uploaded := int64(0)
 
-		etag, err := bosClient.UploadPartFromBytes(bucket, object, uploadId, int(partNumber),
-			partBody, nil)
+		etag, err := uploadPartFromFile(bosClient, fd, bucket, object, uploadId, partNumber,
+			offset, size, content.partSize, buffered)
+		release()
 
 		if err != nil {
 			log.Debugf("finish upload part %d from %s => %s, error is %s",
@@ -888,6 +1174,7 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 				ret <- finErr
 			} else {
 				bar.Finish(content.GetFinshPartNum())
+				uploaded = size
 				log.Debugf("finish upload part %d from %s => bos:/%s/%s, etag is %s",
 					partNumber, srcPath, dstBucketName, dstObjectKey, etag)
 				result <- &CompletedPart{
@@ -896,21 +1183,12 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 				}
 			}
 		}
-		pool <- id
-	}
-
-	// get multi upload or copy thread num
-	multiUploadThreadNum, ok := bceconf.ServerConfigProvider.GetMultiUploadThreadNum()
-	if !ok {
-		return fmt.Errorf("There is no info about multi upload thread Num found!")
+		workers.put(id, uploaded, start)
 	}
 
 	uploadedResult := make(chan *CompletedPart, content.partsNum)
 	retChan := make(chan error, content.partsNum)
-	workerPool := make(chan int64, multiUploadThreadNum)
-	for i := int64(0); i < multiUploadThreadNum; i++ {
-		workerPool <- i
-	}
+	workers := newPartWorkers("upload", tuning.Upload.ThreadNum, tuning.AutoTune)
 
 	for partId := int64(1); partId <= content.partsNum; partId++ {
 		if partInfo, ok := content.partIsFinish(partId); ok {
@@ -929,22 +1207,23 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 			uploadSize = fileSize - offset
 		}
 
-		// read part from file to bytes
-		partBody := make([]byte, uploadSize, uploadSize)
-		n, err := fd.ReadAt(partBody, offset)
-		if err != nil {
-			return err
-		} else if int64(n) != uploadSize {
-			return fmt.Errorf("read size %d != upload size %d!", n, uploadSize)
-		}
-
+		var workerId int64
 		select { // wait until get a worker to upload
-		case workerId := <-workerPool:
-			go uploadPart(dstBucketName, dstObjectKey, content.uploadId, partId, partBody,
-				uploadedResult, retChan, workerId, workerPool)
+		case workerId = <-workers.pool:
 		case uploadPartErr := <-retChan:
 			return uploadPartErr
 		}
+
+		// parts are read from file by workers in parallel, a buffered part holds the memory
+		// budget until it is uploaded, the part larger than the budget is streamed from file.
+		buffered := transferSched.canBuffer(content.partSize)
+		bufferedSize := int64(0)
+		if buffered {
+			bufferedSize = content.partSize
+		}
+		release := transferSched.acquire(bufferedSize)
+		go uploadPart(dstBucketName, dstObjectKey, content.uploadId, partId, offset, uploadSize,
+			buffered, uploadedResult, retChan, workerId, workers, release)
 	}
 
 	// Check the return of each part uploading, and decide to complete or abort it
@@ -972,6 +1251,29 @@ func (h *cliHandler) UploadSuperFile(bosClient bosClientInterface, srcPath, dstB
 	return nil
 }
 
+// Upload a part of file. The part is read into a reusable buffer when buffered is true,
+// otherwise it is streamed from the file.
+func uploadPartFromFile(bosClient bosClientInterface, fd *os.File, bucket, object,
+	uploadId string, partNumber, offset, size, bufferSize int64, buffered bool) (string, error) {
+
+	if !buffered {
+		return bosClient.UploadPartFromReader(bucket, object, uploadId, int(partNumber),
+			io.NewSectionReader(fd, offset, size), nil)
+	}
+
+	buf := partBuffers.get(int(bufferSize))
+	defer partBuffers.put(buf)
+
+	n, err := fd.ReadAt(buf[:size], offset)
+	if err != nil {
+		return "", err
+	} else if int64(n) != size {
+		return "", fmt.Errorf("read size %d != upload size %d!", n, size)
+	}
+	return bosClient.UploadPartFromReader(bucket, object, uploadId, int(partNumber),
+		bytes.NewReader(buf[:size]), nil)
+}
+
 // delete local file
 func (h *cliHandler) utilDeleteLocalFile(localPath string) error {
 	if util.DoesDirExist(localPath) {
@@ -1119,7 +1421,14 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 		timeOfgetObjectInfo = ret.gtime
 	}
 
-	if fileSize < MULTI_COPY_THRESHOLD {
+	tuning, err := getTransferTuning()
+	if err != nil {
+		return err
+	}
+
+	if fileSize < tuning.Copy.Threshold {
+		release := transferSched.acquire(0)
+		defer release()
 		_, err := bosClient.CopyObject(dstBucketName, dstObjectKey, srcBucketName, srcObjectKey,
 			storageClass)
 		return err
@@ -1133,9 +1442,13 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 	}
 
 	// init object content for breakpoint
-	content = &MultiTaskContent{}
+	content = &MultiTaskContent{
+		srcRemote: srcBosClient.RemoteName(),
+		dstRemote: bosClient.RemoteName(),
+	}
 	err = content.init(srcBucketName, srcObjectKey, dstBucketName, dstObjectKey, IS_BOS, IS_BOS,
-		md5Val, fileSize, mtime, restart, MULTI_COPY_PART_SIZE)
+		md5Val, fileSize, mtime, restart,
+		autoTunePartSize("copy", fileSize, tuning.Copy.PartSize, tuning.AutoTune))
 	if err != nil {
 		return err
 	}
@@ -1144,7 +1457,14 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 
 	// Do the parallel multipart upload
 	if content.needRestart {
-		uploadId, err := bosClient.InitiateMultipartUpload(dstBucketName, dstObjectKey, "", storageClass)
+		// multipart copy creates the object without metadata, so the metadata of source object,
+		// e.g. the data key of client-side encryption, is set by the upload
+		srcMeta, err := srcBosClient.GetObjectMeta(srcBucketName, srcObjectKey)
+		if err != nil {
+			return err
+		}
+		uploadId, err := bosClient.InitiateMultipartUpload(dstBucketName, dstObjectKey, "",
+			storageClass, srcMeta.Metadata)
 		if err != nil {
 			return err
 		}
@@ -1166,8 +1486,10 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 	// Inner wrapper function of parallel uploading each part to get the ETag of the part
 	copyPart := func(srcBucket, srcObject, dstBucket, dstObject, uploadId string, partNumber,
 		partSize, fileSize int64, result chan *CompletedPart, ret chan error, id int64,
-		pool chan int64) {
+		workers *partWorkers, release func()) {
 
+		start := time.Now()
+		copied := int64(0)
 		rangeStart := (partNumber - 1) * partSize
 		rangeEnd := partNumber * partSize
 		if rangeEnd > fileSize {
@@ -1182,6 +1504,7 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 
 		copyRet, err := bosClient.UploadPartCopy(dstBucketName, dstObjectKey, srcBucketName,
 			srcObjectKey, uploadId, sourceRange, partNumber)
+		release()
 
 		if err != nil {
 			log.Debugf("finish copy part %d from bos:/%s/%s => bos:/%s/%s, error is %s",
@@ -1202,27 +1525,19 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 					partNumber, srcBucketName, srcObjectKey, dstBucketName, dstObjectKey,
 					copyRet.ETag)
 				bar.Finish(content.GetFinshPartNum())
+				copied = rangeEnd - rangeStart + 1
 				result <- &CompletedPart{
 					ETag:       *copyRet.ETag,
 					PartNumber: partNumber,
 				}
 			}
 		}
-		pool <- id
-	}
-
-	// get multi upload or copy thread num
-	multiUploadThreadNum, ok := bceconf.ServerConfigProvider.GetMultiUploadThreadNum()
-	if !ok {
-		return fmt.Errorf("There is no info about multi upload thread Num found!")
+		workers.put(id, copied, start)
 	}
 
 	uploadedResult := make(chan *CompletedPart, content.partsNum)
 	retChan := make(chan error, content.partsNum)
-	workerPool := make(chan int64, multiUploadThreadNum)
-	for i := int64(0); i < multiUploadThreadNum; i++ {
-		workerPool <- i
-	}
+	workers := newPartWorkers("copy", tuning.Copy.ThreadNum, tuning.AutoTune)
 
 	for partId := int64(1); partId <= content.partsNum; partId++ {
 		if partInfo, ok := content.partIsFinish(partId); ok {
@@ -1235,9 +1550,11 @@ func (h *cliHandler) CopySuperFile(srcBosClient, bosClient bosClientInterface, s
 			continue
 		}
 		select { // wait until get a worker to upload
-		case workerId := <-workerPool:
+		case workerId := <-workers.pool:
+			release := transferSched.acquire(0)
 			go copyPart(srcBucketName, srcObjectKey, dstBucketName, dstObjectKey, content.uploadId,
-				partId, content.partSize, fileSize, uploadedResult, retChan, workerId, workerPool)
+				partId, content.partSize, fileSize, uploadedResult, retChan, workerId, workers,
+				release)
 		case uploadPartErr := <-retChan:
 			return uploadPartErr
 		}
@@ -13,19 +13,27 @@
 package boscli
 
 import (
+	"bytes"
 	"fmt"
-	"net/http"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
 import (
-	// 	"bceconf"
-	"github.com/baidubce/bce-sdk-go/bce"
-	"github.com/baidubce/bce-sdk-go/services/bos/api"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"bcecmd/boscmd"
+	"bceconf"
 	"utils/util"
 )
 
@@ -40,8 +48,9 @@ func init() {
 }
 
 type fakeBosClient struct {
-	results    []*api.ListObjectsResult
-	objectMeta *api.GetObjectMetaResult
+	fakeUnsupportedBosClient
+	results    []*s3.ListObjectsOutput
+	objectMeta *s3.HeadObjectOutput
 }
 
 func (b *fakeBosClient) HeadBucket(bucket string) error {
@@ -49,78 +58,47 @@ func (b *fakeBosClient) HeadBucket(bucket string) error {
 		return nil
 	}
 	if bucket == "notexist" {
-		return &bce.BceServiceError{
-			StatusCode: http.StatusNotFound,
-		}
+		return awserr.New(boscmd.CODE_NO_SUCH_BUCKET, "", nil)
 	}
 	if bucket == "error" {
 		return fmt.Errorf("error")
 	}
 
 	if bucket == "forbidden" {
-		return &bce.BceServiceError{
-			StatusCode: http.StatusForbidden,
-		}
+		return awserr.New(boscmd.CODE_ACCESS_DENIED, "", nil)
 	}
 	return fmt.Errorf("unknow error")
 }
 
-// Fake ListBuckets - list all buckets
-func (b *fakeBosClient) ListBuckets() (*api.ListBucketsResult, error) {
-	return nil, fmt.Errorf("test")
-}
-
-// Fake PutBucket - create a new bucket
-func (b *fakeBosClient) PutBucket(bucket string) (string, error) {
-	return "", fmt.Errorf("test")
-}
-
-// Fake GetBucketLocation - get the location fo the given bucket
-func (b *fakeBosClient) GetBucketLocation(bucket string) (string, error) {
-	return "", fmt.Errorf("test")
-}
-
 // Fake ListObjects - list all objects of the given bucket
-func (b *fakeBosClient) ListObjects(bucket string, args *api.ListObjectsArgs) (
-	*api.ListObjectsResult, error) {
+func (b *fakeBosClient) ListObjects(bucket, delimiter, marker, prefix string,
+	maxKeys int) (*s3.ListObjectsOutput, error) {
 	var (
-		marker int
-		err    error
+		index int
+		err   error
 	)
-	if args.Marker == "" {
-		marker, err = strconv.Atoi(bucket)
+	if marker == "" {
+		index, err = strconv.Atoi(bucket)
 	} else {
-		marker, err = strconv.Atoi(args.Marker)
+		index, err = strconv.Atoi(marker)
 	}
 	if err != nil {
 		return nil, err
 	}
-	if marker < len(b.results) {
-		return b.results[marker], nil
+	if index < len(b.results) {
+		return b.results[index], nil
 	}
 	return nil, fmt.Errorf("Error in list objects")
 }
 
-// Fake DeleteBucket - delete a empty bucket
-func (b *fakeBosClient) DeleteBucket(bucket string) error {
-
-	return fmt.Errorf("test")
-}
-
-// Fake BasicGeneratePresignedUrl  generate an authorization url with expire time
-func (b *fakeBosClient) BasicGeneratePresignedUrl(bucket string, object string,
-	expireInSeconds int) string {
-	return ""
-}
-
 // Fake DeleteMultipleObjectsFromKeyList - delete a list of objects with given key string array
 func (b *fakeBosClient) DeleteMultipleObjectsFromKeyList(bucket string,
-	keyList []string) (*api.DeleteMultipleObjectsResult, error) {
+	keyList []string) (*DeleteMultipleObjectsResult, error) {
 	retryNum := 1
 	if strings.HasSuffix(keyList[0], "retry") {
 		retryNum = 2
 	}
-	ret := &api.DeleteMultipleObjectsResult{}
+	ret := &DeleteMultipleObjectsResult{}
 	haveError := false
 	for _, val := range keyList {
 		if strings.HasPrefix(val, "TFAILED") {
@@ -130,7 +108,7 @@ func (b *fakeBosClient) DeleteMultipleObjectsFromKeyList(bucket string,
 				continue
 			}
 			val += "retry"
-			ret.Errors = append(ret.Errors, api.DeleteObjectResult{
+			ret.Errors = append(ret.Errors, DeleteObjectResult{
 				Key: val, Code: components[2], Message: components[3]})
 			haveError = true
 		}
@@ -144,17 +122,15 @@ func (b *fakeBosClient) DeleteMultipleObjectsFromKeyList(bucket string,
 // Fake DeleteObject - delete the given object
 func (b *fakeBosClient) DeleteObject(bucket, object string) error {
 	if bucket == "error" {
-		return fmt.Errorf(bucket + object)
+		return fmt.Errorf("%s", bucket+object)
 	}
 	return nil
 }
 
 // Fake GetObjectMeta
-func (b *fakeBosClient) GetObjectMeta(bucket, object string) (*api.GetObjectMetaResult, error) {
+func (b *fakeBosClient) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput, error) {
 	if object == "404" {
-		return nil, &bce.BceServiceError{
-			StatusCode: 404,
-		}
+		return nil, awserr.New(boscmd.CODE_NO_SUCH_KEY, "", nil)
 	}
 	if b.objectMeta == nil {
 		return nil, fmt.Errorf("Error in list objects")
@@ -163,8 +139,8 @@ func (b *fakeBosClient) GetObjectMeta(bucket, object string) (*api.GetObjectMeta
 }
 
 // Fake Copy Object
-func (b *fakeBosClient) CopyObject(bucket, object, srcBucket, srcObject string,
-	args *api.CopyObjectArgs) (*api.CopyObjectResult, error) {
+func (b *fakeBosClient) CopyObject(bucket, object, srcBucket, srcObject,
+	storageClass string) (*s3.CopyObjectOutput, error) {
 	if srcBucket == "error" {
 		return nil, fmt.Errorf("error")
 	}
@@ -175,125 +151,21 @@ func (b *fakeBosClient) CopyObject(bucket, object, srcBucket, srcObject string,
 }
 
 // Fake of BasiGetObjectToFile
-func (b *fakeBosClient) BasicGetObjectToFile(bucket, object, localPath string) error {
+func (b *fakeBosClient) BasicGetObjectToFile(bucket, object, localPath string) (
+	map[string]*string, error) {
 	if bucket == "success" && object == "a/b/c" {
-		return nil
+		return nil, nil
 	}
-	return fmt.Errorf(bucket + object + localPath)
+	return nil, fmt.Errorf("%s", bucket+object+localPath)
 }
 
 // Fake of PutObjectFromFile
-func (b *fakeBosClient) PutObjectFromFile(bucket, object, fileName string,
-	args *api.PutObjectArgs) (string, error) {
+func (b *fakeBosClient) PutObjectFromFile(bucket, object, fileName,
+	storageClass string, meta map[string]*string) (string, error) {
 	if fileName == "success" {
 		return "", nil
 	}
-	return "", fmt.Errorf("smail" + fileName + bucket + object + args.StorageClass)
-}
-
-// Fake of UploadSuperFile
-func (b *fakeBosClient) UploadSuperFile(bucket, object, fileName, storageClass string) error {
-	if fileName == "success" {
-		return nil
-	}
-	return fmt.Errorf("big" + fileName + bucket + object + storageClass)
-}
-
-func (b *fakeBosClient) BasicUploadPart(bucket, object, uploadId string, partNumber int,
-	content *bce.Body) (string, error) {
-	return "", fmt.Errorf("usupport BasicUploadPart")
-}
-
-func (b *fakeBosClient) UploadPartFromBytes(bucket, object, uploadId string, partNumber int,
-	content []byte, args *api.UploadPartArgs) (string, error) {
-	return "", fmt.Errorf("Not support")
-}
-
-// Fake of PutBucketLifecycle
-func (b *fakeBosClient) PutBucketLifecycleFromString(bucket, lifecycle string) error {
-	return nil
-}
-
-// Fake of GetBucketLifecycle
-func (b *fakeBosClient) GetBucketLifecycle(bucket string) (*api.GetBucketLifecycleResult, error) {
-	return nil, nil
-}
-
-// Fake of DeleteBucketLifecycle
-func (b *fakeBosClient) DeleteBucketLifecycle(bucket string) error {
-	return nil
-}
-
-// Fake of PutBucketLoggingFromStruct
-func (b *fakeBosClient) PutBucketLoggingFromStruct(bucket string,
-	obj *api.PutBucketLoggingArgs) error {
-
-	return nil
-}
-
-// Fake of GetBucketLogging
-func (b *fakeBosClient) GetBucketLogging(bucket string) (*api.GetBucketLoggingResult, error) {
-	return nil, nil
-}
-
-// Fake of DeleteBucketLogging
-func (b *fakeBosClient) DeleteBucketLogging(bucket string) error {
-	return nil
-}
-
-// Fake of PutBucketStorageclass
-func (b *fakeBosClient) PutBucketStorageclass(bucket, storageClass string) error {
-	return nil
-}
-
-// Fake of GetBucketStorageclass
-func (b *fakeBosClient) GetBucketStorageclass(bucket string) (string, error) {
-	return "", nil
-}
-
-// Fake of PutBucketAclFromCanned
-func (b *fakeBosClient) PutBucketAclFromCanned(bucket, cannedAcl string) error {
-	return nil
-}
-
-// Fake of PutBucketAcl
-func (b *fakeBosClient) PutBucketAclFromString(bucket, acl string) error {
-	return nil
-}
-
-// Fake of GetBucketAcl
-func (b *fakeBosClient) GetBucketAcl(bucket string) (*api.GetBucketAclResult, error) {
-	return nil, nil
-}
-
-// Fake of GetBucketStorageclass
-func (b *fakeBosClient) UploadPartCopy(bucket, object, srcBucket, srcObject, uploadId string,
-	partNumber int, args *api.UploadPartCopyArgs) (*api.CopyObjectResult, error) {
-	return nil, fmt.Errorf("Not support")
-}
-
-// Fake of GetBucketStorageclass
-func (b *fakeBosClient) InitiateMultipartUpload(bucket, object, contentType string,
-	args *api.InitiateMultipartUploadArgs) (*api.InitiateMultipartUploadResult, error) {
-
-	return nil, fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClient) AbortMultipartUpload(bucket, object, uploadId string) error {
-	return fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClient) CompleteMultipartUploadFromStruct(bucket, object, uploadId string,
-	parts *api.CompleteMultipartUploadArgs,
-	meta map[string]string) (*api.CompleteMultipartUploadResult, error) {
-
-	return nil, fmt.Errorf("Not support")
-}
-
-func (b *fakeBosClient) GetObject(bucket, object string, responseHeaders map[string]string,
-	ranges ...int64) (*api.GetObjectResult, error) {
-
-	return nil, fmt.Errorf("Not support")
+	return "", fmt.Errorf("%s", "smail"+fileName+bucket+object+storageClass)
 }
 
 type listObjectIteratorType struct {
@@ -317,29 +189,14 @@ func TestListObjectIterator(t *testing.T) {
 			bucketName: "0",
 			objectKey:  "",
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/b",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/c",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/d",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/b", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/c", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -378,53 +235,23 @@ func TestListObjectIterator(t *testing.T) {
 			objectKey:  "a/",
 			exclude:    []string{"bos:/0/a/*"},
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/b",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/c",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/d",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/b", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/c", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: true,
-						NextMarker:  "1",
+						IsTruncated: aws.Bool(true),
+						NextMarker:  aws.String("1"),
 					},
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/f",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/g",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/h",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/f", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/g", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/h", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -441,53 +268,23 @@ func TestListObjectIterator(t *testing.T) {
 			objectKey:  "a/",
 			include:    []string{"bos:/0/a/c", "bos:/0/a/g", "bos:/0/a/h"},
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/b",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/c",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/d",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/b", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/c", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: true,
-						NextMarker:  "1",
+						IsTruncated: aws.Bool(true),
+						NextMarker:  aws.String("1"),
 					},
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/f",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/g",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/h",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/f", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/g", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/h", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -527,53 +324,23 @@ func TestListObjectIterator(t *testing.T) {
 			objectKey:  "a/",
 			exclude:    []string{"bos:/0/a/b", "bos:/0/a/d", "bos:/0/a/f"},
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/b",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/c",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/d",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/b", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/c", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: true,
-						NextMarker:  "1",
+						IsTruncated: aws.Bool(true),
+						NextMarker:  aws.String("1"),
 					},
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/f",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/g",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/h",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("a/f", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/g", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/h", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -612,69 +379,31 @@ func TestListObjectIterator(t *testing.T) {
 			bucketName: "0",
 			objectKey:  "a/",
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						CommonPrefixes: []api.PrefixType{
-							api.PrefixType{
-								Prefix: "a/dir/",
-							},
-							api.PrefixType{
-								Prefix: "a/dir2/",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						CommonPrefixes: []*s3.CommonPrefix{
+							fakePrefix("a/dir/"),
+							fakePrefix("a/dir2/"),
 						},
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/b",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/c",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/d",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+						Contents: []*s3.Object{
+							fakeObject("a/b", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/c", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/d", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: true,
-						NextMarker:  "1",
+						IsTruncated: aws.Bool(true),
+						NextMarker:  aws.String("1"),
 					},
-					&api.ListObjectsResult{
-						CommonPrefixes: []api.PrefixType{
-							api.PrefixType{
-								Prefix: "a/eir/",
-							},
-							api.PrefixType{
-								Prefix: "a/eir2/",
-							},
+					&s3.ListObjectsOutput{
+						CommonPrefixes: []*s3.CommonPrefix{
+							fakePrefix("a/eir/"),
+							fakePrefix("a/eir2/"),
 						},
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/f",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/g",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/h",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+						Contents: []*s3.Object{
+							fakeObject("a/f", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("a/g", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("a/h", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -753,68 +482,12 @@ func TestListObjectIterator(t *testing.T) {
 				},
 			},
 		},
-		// list dir time error
-		listObjectIteratorType{
-			bucketName: "0",
-			objectKey:  "",
-			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "a/b",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/c",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "a/d",
-								LastModified: "-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
-						},
-						IsTruncated: false,
-					},
-				},
-			},
-			isDir: true,
-			output: []*listFileResult{
-				&listFileResult{
-					file: &fileDetail{
-						key:   "a/b",
-						size:  100,
-						mtime: 1136214245,
-					},
-				},
-				&listFileResult{
-					file: &fileDetail{
-						key:   "a/c",
-						size:  200,
-						mtime: 1478099045,
-					},
-				},
-				nil,
-			},
-		},
 		// single object
 		listObjectIteratorType{
 			bucketName: "0",
 			objectKey:  "a/b/c",
 			bosClient: &fakeBosClient{
-				objectMeta: &api.GetObjectMetaResult{
-					api.ObjectMeta{
-						LastModified:  "Wed, 06 Apr 2016 06:34:40 GMT",
-						ContentLength: 100,
-						StorageClass:  "STANDARD",
-					},
-				},
+				objectMeta: fakeObjectMeta("Wed, 06 Apr 2016 06:34:40 GMT", 100, "STANDARD"),
 			},
 			isDir: false,
 			output: []*listFileResult{
@@ -833,23 +506,6 @@ func TestListObjectIterator(t *testing.T) {
 			},
 		},
 
-		// single object time error
-		listObjectIteratorType{
-			bucketName: "0",
-			objectKey:  "a/b/c",
-			bosClient: &fakeBosClient{
-				objectMeta: &api.GetObjectMetaResult{
-					api.ObjectMeta{
-						LastModified:  "06 Apr 2016 06:34:40 GMT",
-						ContentLength: 100,
-						StorageClass:  "STANDARD",
-					},
-				},
-			},
-			isDir:  false,
-			output: []*listFileResult{nil},
-		},
-
 		// single object err
 		listObjectIteratorType{
 			bucketName: "0",
@@ -929,7 +585,7 @@ type multiDeleteObjectsWithRetryType struct {
 	bosClient  bosClientInterface
 	bucketName string
 	objectList []string
-	output     []api.DeleteObjectResult
+	output     []DeleteObjectResult
 	isSuc      bool
 }
 
@@ -977,16 +633,16 @@ func TestMultiDeleteObjectsWithRetry(t *testing.T) {
 				"object3",
 				"object4",
 			},
-			output: []api.DeleteObjectResult{
-				api.DeleteObjectResult{
+			output: []DeleteObjectResult{
+				DeleteObjectResult{
 					Key:  "TFAILED-3-NoSuchKey-object5retry",
 					Code: "NoSuchKey",
 				},
-				api.DeleteObjectResult{
+				DeleteObjectResult{
 					Key:  "TFAILED-3-504-object4retryretry",
 					Code: "504",
 				},
-				api.DeleteObjectResult{
+				DeleteObjectResult{
 					Key:  "TFAILED-3-505-object5retryretry",
 					Code: "505",
 				},
@@ -1024,47 +680,17 @@ func TestMultiDeleteDir(t *testing.T) {
 	testCases := []multiDeleteDirType{
 		multiDeleteDirType{
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "object1",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-2-502-object1",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-2-502-object2",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-2-503-object3",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-3-NoSuchKey-object5",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-3-505-object5",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("object1", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("TFAILED-2-502-object1", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("TFAILED-2-502-object2", "2017-11-02T15:04:05Z", 300, "STANDARD"),
+							fakeObject("TFAILED-2-503-object3", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("TFAILED-3-NoSuchKey-object5", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("TFAILED-3-505-object5", "2017-11-02T15:04:05Z", 300, "STANDARD"),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -1075,35 +701,15 @@ func TestMultiDeleteDir(t *testing.T) {
 		},
 		multiDeleteDirType{
 			bosClient: &fakeBosClient{
-				results: []*api.ListObjectsResult{
-					&api.ListObjectsResult{
-						Contents: []api.ObjectSummaryType{
-							api.ObjectSummaryType{
-								Key:          "object1",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-2-502-object1",
-								LastModified: "2016-11-02T15:04:05Z",
-								Size:         200,
-								StorageClass: "",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-2-502-object2",
-								LastModified: "2017-11-02T15:04:05Z",
-								Size:         300,
-								StorageClass: "STANDARD",
-							},
-							api.ObjectSummaryType{
-								Key:          "TFAILED-2-503-object3",
-								LastModified: "2006-01-02T15:04:05Z",
-								Size:         100,
-								StorageClass: "",
-							},
+				results: []*s3.ListObjectsOutput{
+					&s3.ListObjectsOutput{
+						Contents: []*s3.Object{
+							fakeObject("object1", "2006-01-02T15:04:05Z", 100, ""),
+							fakeObject("TFAILED-2-502-object1", "2016-11-02T15:04:05Z", 200, ""),
+							fakeObject("TFAILED-2-502-object2", "2017-11-02T15:04:05Z", 300, "STANDARD"),
+							fakeObject("TFAILED-2-503-object3", "2006-01-02T15:04:05Z", 100, ""),
 						},
-						IsTruncated: false,
+						IsTruncated: aws.Bool(false),
 					},
 				},
 			},
@@ -1114,7 +720,7 @@ func TestMultiDeleteDir(t *testing.T) {
 		},
 	}
 	for i, tCase := range testCases {
-		ret, _ := handler.multiDeleteDir(tCase.bosClient, tCase.bucketName, tCase.objectKey)
+		ret, _ := handler.multiDeleteDir(tCase.bosClient, nil, tCase.bucketName, tCase.objectKey)
 		util.ExpectEqual("handler.go multiDeleteDir II", i+1, t.Errorf, tCase.deleted, ret)
 	}
 }
@@ -1199,13 +805,15 @@ func TestUtilCopyObject(t *testing.T) {
 			err:          fmt.Errorf("error"),
 		},
 	}
-	bosClient := &fakeBosClient{}
-	srcBosClient := &fakeBosClient{}
+	// copy in the same remote
+	bosClient := &fakeBosClient{
+		objectMeta: fakeObjectMeta("Wed, 06 Apr 2016 06:34:40 GMT", 100, "STANDARD"),
+	}
 	for i, tCase := range testCases {
 
-		ret := handler.utilCopyObject(srcBosClient, bosClient, tCase.srcBucket, tCase.srcObject,
-			tCase.dstBucket, tCase.dstObject, tCase.storageClass, tCase.fileSize, tCase.fileMtime,
-			time.Now().Unix(), tCase.restart)
+		ret := handler.utilCopyObject(bosClient, bosClient, tCase.srcBucket, tCase.srcObject,
+			tCase.dstBucket, tCase.dstObject, tCase.storageClass, "", tCase.fileSize,
+			tCase.fileMtime, time.Now().Unix(), tCase.restart)
 
 		if tCase.isSuc {
 			util.ExpectEqual("handler.go utilCopyObject I", i+1, t.Errorf,
@@ -1415,10 +1023,11 @@ func TestUtilDownloadObject(t *testing.T) {
 	}
 	os.RemoveAll("./test")
 	os.Create("./cover.out")
+	defer os.Remove("./cover.out")
 	bosClient := &fakeBosClient{}
 	for i, tCase := range testCases {
 		ret := handler.utilDownloadObject(bosClient, tCase.srcBucket, tCase.srcObject,
-			tCase.localPath, tCase.downLoadTmp, tCase.yes, tCase.fileSize, tCase.mtime,
+			tCase.localPath, tCase.downLoadTmp, "", tCase.yes, tCase.fileSize, tCase.mtime,
 			tCase.timeOfgetObjectInfo, tCase.restart)
 
 		if !tCase.isSuc {
@@ -1482,7 +1091,7 @@ func TestUtilUploadFile(t *testing.T) {
 	bosClient := &fakeBosClient{}
 	for i, tCase := range testCases {
 		ret := handler.utilUploadFile(bosClient, tCase.srcPath, tCase.relSrcPath, tCase.dstBucket,
-			tCase.dstObject, tCase.storageClass, tCase.fileSize, tCase.fileMtime,
+			tCase.dstObject, tCase.storageClass, "", tCase.fileSize, tCase.fileMtime,
 			tCase.timeOfgetObjectInfo, tCase.restart)
 		if tCase.err == "" {
 			util.ExpectEqual("handler.go utilUploadFile I", i+1, t.Errorf,
@@ -1609,3 +1218,140 @@ func TestDoesBucketExist(t *testing.T) {
 		util.ExpectEqual("handler.go doesBucketExist II", i+1, t.Errorf, tCase.exist, ret)
 	}
 }
+
+func TestRelayFile(t *testing.T) {
+	// the relay file is named by source and destination
+	tmpDir, err := transferTmpDir("")
+	util.ExpectEqual("relay file", 1, t.Errorf, nil, err)
+	relayPath := relayFilePath(tmpDir, "bos:/bucket/object", "backup:bucket/object")
+	util.ExpectEqual("relay file", 1, t.Errorf, relayPath,
+		relayFilePath(tmpDir, "bos:/bucket/object", "backup:bucket/object"))
+	util.ExpectEqual("relay file", 1, t.Errorf, false,
+		relayPath == relayFilePath(tmpDir, "backup:bucket/object", "bos:/bucket/object"))
+
+	//1 the relay file is in --download-tmp-path
+	util.ExpectEqual("relay file", 1, t.Errorf, "tmp",
+		filepath.Dir(relayFilePath("tmp", "bos:/bucket/object", "backup:bucket/object")))
+	_, err = transferTmpDir("./handler.go")
+	util.ExpectEqual("relay file", 1, t.Errorf, true, err != nil)
+	_, err = transferTmpDir("./notExist")
+	util.ExpectEqual("relay file", 1, t.Errorf, true, err != nil)
+
+	//2 not downloaded
+	os.Remove(relayPath)
+	defer os.Remove(relayPath)
+	util.ExpectEqual("relay file", 2, t.Errorf, false, isRelayFileDownloaded(relayPath, 4, 100))
+
+	//3 downloaded
+	fd, err := os.Create(relayPath)
+	util.ExpectEqual("relay file", 3, t.Errorf, nil, err)
+	fd.WriteString("data")
+	fd.Close()
+	os.Chtimes(relayPath, time.Unix(100, 0), time.Unix(100, 0))
+	util.ExpectEqual("relay file", 3, t.Errorf, true, isRelayFileDownloaded(relayPath, 4, 100))
+
+	//4 the source object is modified
+	util.ExpectEqual("relay file", 4, t.Errorf, false, isRelayFileDownloaded(relayPath, 4, 200))
+	util.ExpectEqual("relay file", 4, t.Errorf, false, isRelayFileDownloaded(relayPath, 5, 100))
+}
+
+// A fake of an object which is overwritten after it is listed, a part is got only when the etag
+// of the part is the etag of the newest content.
+type fakeOverwrittenBosClient struct {
+	fakeUnsupportedBosClient
+	content      []byte
+	etag         string
+	lastModified string
+	interrupted  bool // only the first part is got, as the download is interrupted
+	heads        int
+	overwritten  int // the number of parts which are got by a stale etag
+	lock         sync.Mutex
+}
+
+func (b *fakeOverwrittenBosClient) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput,
+	error) {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.heads++
+	ret := fakeObjectMeta(b.lastModified, int64(len(b.content)), "STANDARD")
+	ret.ETag = aws.String(b.etag)
+	return ret, nil
+}
+
+func (b *fakeOverwrittenBosClient) GetObjectIfMatch(bucket, object, etag string, rangeStart,
+	rangeEnd int64) (*s3.GetObjectOutput, error) {
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if etag != b.etag {
+		b.overwritten++
+		return nil, awserr.New(boscmd.CODE_PRECONDITION_FAILED, "", nil)
+	}
+	if b.interrupted && rangeStart > 0 {
+		return nil, fmt.Errorf("connection reset")
+	}
+	return &s3.GetObjectOutput{
+		Body:          ioutil.NopCloser(bytes.NewReader(b.content[rangeStart : rangeEnd+1])),
+		ContentLength: aws.Int64(rangeEnd - rangeStart + 1),
+	}, nil
+}
+
+func TestDownloadOverwrittenObject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "download_test")
+	util.ExpectEqual("download overwritten", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	multiuploadFolder := bceconf.MultiuploadFolder
+	bceconf.MultiuploadFolder = filepath.Join(dir, "records")
+	defer func() { bceconf.MultiuploadFolder = multiuploadFolder }()
+	transferTuningLock.Lock()
+	tuning := transferTuning
+	transferTuning = &TransferTuning{
+		Download: MultipartTuning{Threshold: 1024, PartSize: 1024, ThreadNum: 1},
+	}
+	transferTuningLock.Unlock()
+	defer func() {
+		transferTuningLock.Lock()
+		transferTuning = tuning
+		transferTuningLock.Unlock()
+	}()
+
+	lastModified := "Wed, 06 Apr 2016 06:34:40 GMT"
+	modTime, _ := time.Parse(BOS_HTTP_TIME_FORMT, lastModified)
+	oldContent := bytes.Repeat([]byte("o"), 4096)
+	newContent := bytes.Repeat([]byte("n"), 4096)
+	bosClient := &fakeOverwrittenBosClient{
+		content:      oldContent,
+		etag:         "old",
+		lastModified: lastModified,
+		interrupted:  true,
+	}
+	dst := filepath.Join(dir, "object")
+	contentId := util.StringMd5(joinRemotePath("", "bucket", "object") + "_" + dst)
+	recordPath := filepath.Join(bceconf.MultiuploadFolder, contentId)
+	tmpPath := filepath.Join(dir, "bcecmd.temp."+contentId)
+
+	//1 the download is interrupted, the object is identified by the listed etag without HEAD
+	err = handler.utilDownloadObject(bosClient, "bucket", "object", dst, dir, "old", true,
+		int64(len(oldContent)), modTime.Unix(), time.Now().Unix(), false)
+	util.ExpectEqual("download overwritten", 1, t.Errorf, true, err != nil)
+	util.ExpectEqual("download overwritten", 1, t.Errorf, 0, bosClient.heads)
+	util.ExpectEqual("download overwritten", 1, t.Errorf, true, util.DoesFileExist(recordPath))
+	util.ExpectEqual("download overwritten", 1, t.Errorf, true, util.DoesFileExist(tmpPath))
+
+	//2 the object is overwritten before the download is resumed by the stale listed etag, the
+	// downloaded part is discarded and the object is downloaded again
+	bosClient.content = newContent
+	bosClient.etag = "new"
+	bosClient.interrupted = false
+	err = handler.utilDownloadObject(bosClient, "bucket", "object", dst, dir, "old", true,
+		int64(len(oldContent)), modTime.Unix(), time.Now().Unix(), false)
+	util.ExpectEqual("download overwritten", 2, t.Errorf, nil, err)
+	util.ExpectEqual("download overwritten", 2, t.Errorf, 1, bosClient.overwritten)
+	util.ExpectEqual("download overwritten", 2, t.Errorf, 1, bosClient.heads)
+	downloaded, _ := ioutil.ReadFile(dst)
+	util.ExpectEqual("download overwritten", 2, t.Errorf, string(newContent), string(downloaded))
+	util.ExpectEqual("download overwritten", 2, t.Errorf, false, util.DoesFileExist(recordPath))
+	util.ExpectEqual("download overwritten", 2, t.Errorf, false, util.DoesFileExist(tmpPath))
+}
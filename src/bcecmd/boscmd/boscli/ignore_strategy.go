@@ -0,0 +1,212 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the ignore rules with gitignore semantics, which are used by
+// .bcecmdignore, --exclude-from and --include-from. The .bcecmdignore files are used when
+// upload local directories, and they are not uploaded themselves.
+
+package boscli
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+import (
+	"utils/util"
+)
+
+const (
+	IGNORE_FILE_NAME      = ".bcecmdignore"
+	IGNORE_RULE_SEPARATOR = "/"
+)
+
+// whether to use .bcecmdignore when upload local directories, it is disabled by --no-ignore-file
+var useIgnoreFile = true
+
+// SetUseIgnoreFile - whether to skip the files ignored by .bcecmdignore and the ignore files
+func (b *BosCli) SetUseIgnoreFile(use bool) {
+	useIgnoreFile = use
+}
+
+// one line of ignore file
+type ignoreRule struct {
+	pattern  string
+	negate   bool // pattern start with "!", the matched path is re-included
+	dirOnly  bool // pattern end with "/", only match directory
+	anchored bool // pattern contain "/", only match path relative to the base directory
+}
+
+// ignore rules of one ignore file, paths are matched relative to baseDir
+type ignoreMatcher struct {
+	baseDir string
+	rules   []ignoreRule
+}
+
+// Parse one line of ignore file, return false when the line is blank or comment.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	rule := ignoreRule{}
+
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\#") || strings.HasPrefix(line, "\\!") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, IGNORE_RULE_SEPARATOR) {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, IGNORE_RULE_SEPARATOR)
+	}
+	if strings.Contains(line, IGNORE_RULE_SEPARATOR) {
+		rule.anchored = true
+		line = strings.TrimLeft(line, IGNORE_RULE_SEPARATOR)
+	}
+	if line == "" {
+		return rule, false
+	}
+	rule.pattern = line
+	return rule, true
+}
+
+// Parse ignore rules from lines
+func parseIgnoreRules(lines []string) []ignoreRule {
+	rules := []ignoreRule{}
+	for _, line := range lines {
+		if rule, ok := parseIgnoreRule(line); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Read ignore rules from file
+func readIgnoreRules(filePath string) ([]ignoreRule, error) {
+	fd, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return parseIgnoreRules(lines), nil
+}
+
+// Create ignore matcher from ignore file, return nil when the file doesn't exist.
+func newIgnoreMatcherFromFile(filePath, baseDir string) (*ignoreMatcher, error) {
+	rules, err := readIgnoreRules(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ignoreMatcher{baseDir: baseDir, rules: rules}, nil
+}
+
+// Whether the rule matches the path, relPath must be separated by "/" and relative to
+// the base directory of rule.
+func (r *ignoreRule) match(relPath string, isDir bool) (bool, error) {
+	if r.dirOnly && !isDir {
+		return false, nil
+	}
+	if r.anchored {
+		return util.MatchPath(r.pattern, relPath)
+	}
+	return util.MatchPath(r.pattern, path.Base(relPath))
+}
+
+// Get the result of the last matched rule.
+// RETURN: matched is true when there is any rule matched, and ignored is true when the last
+// matched rule is not a negation.
+func (m *ignoreMatcher) lastMatch(relPath string, isDir bool) (matched, ignored bool,
+	err error) {
+
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		ok, err := m.rules[i].match(relPath, isDir)
+		if err != nil {
+			return false, false, err
+		}
+		if ok {
+			return true, !m.rules[i].negate, nil
+		}
+	}
+	return false, false, nil
+}
+
+// Get the path relative to the base dir, return false when the path isn't under base dir.
+// the path separator of returned path is "/".
+func (m *ignoreMatcher) relPath(fullPath, separator string) (string, bool) {
+	if !strings.HasPrefix(fullPath, m.baseDir) {
+		return "", false
+	}
+	relPath := strings.Trim(fullPath[len(m.baseDir):], separator)
+	if separator != IGNORE_RULE_SEPARATOR {
+		relPath = strings.Replace(relPath, separator, IGNORE_RULE_SEPARATOR, -1)
+	}
+	return relPath, relPath != ""
+}
+
+// Whether the path is ignored, a path is ignored when any of its parent directories is
+// ignored, just like git.
+func (m *ignoreMatcher) isIgnored(fullPath, separator string) (bool, error) {
+	relPath, ok := m.relPath(fullPath, separator)
+	if !ok {
+		return false, nil
+	}
+	isDir := strings.HasSuffix(fullPath, separator)
+
+	names := strings.Split(relPath, IGNORE_RULE_SEPARATOR)
+	for i := 1; i < len(names); i++ {
+		_, ignored, err := m.lastMatch(strings.Join(names[:i], IGNORE_RULE_SEPARATOR), true)
+		if err != nil || ignored {
+			return ignored, err
+		}
+	}
+	_, ignored, err := m.lastMatch(relPath, isDir)
+	return ignored, err
+}
+
+// Whether the path is ignored by per-directory ignore files, the matchers must be sorted
+// from the top directory to the deepest one, rules of deeper directory take precedence.
+// The parent directories of path must have been checked, so only the path itself is matched.
+func isIgnoredByMatchers(matchers []*ignoreMatcher, fullPath, separator string) (bool, error) {
+	isDir := strings.HasSuffix(fullPath, separator)
+	for i := len(matchers) - 1; i >= 0; i-- {
+		relPath, ok := matchers[i].relPath(fullPath, separator)
+		if !ok {
+			continue
+		}
+		matched, ignored, err := matchers[i].lastMatch(relPath, isDir)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return ignored, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+import (
+	"utils/util"
+)
+
+type parseIgnoreRuleType struct {
+	line string
+	rule ignoreRule
+	ok   bool
+}
+
+func TestParseIgnoreRule(t *testing.T) {
+	testCases := []parseIgnoreRuleType{
+		//1
+		parseIgnoreRuleType{
+			line: "",
+			ok:   false,
+		},
+		//2
+		parseIgnoreRuleType{
+			line: "# comment",
+			ok:   false,
+		},
+		//3
+		parseIgnoreRuleType{
+			line: "*.log  ",
+			rule: ignoreRule{pattern: "*.log"},
+			ok:   true,
+		},
+		//4
+		parseIgnoreRuleType{
+			line: "!keep.log",
+			rule: ignoreRule{pattern: "keep.log", negate: true},
+			ok:   true,
+		},
+		//5
+		parseIgnoreRuleType{
+			line: "build/",
+			rule: ignoreRule{pattern: "build", dirOnly: true},
+			ok:   true,
+		},
+		//6
+		parseIgnoreRuleType{
+			line: "/doc/*.txt",
+			rule: ignoreRule{pattern: "doc/*.txt", anchored: true},
+			ok:   true,
+		},
+		//7
+		parseIgnoreRuleType{
+			line: "\\#file",
+			rule: ignoreRule{pattern: "#file"},
+			ok:   true,
+		},
+		//8
+		parseIgnoreRuleType{
+			line: "/",
+			ok:   false,
+		},
+	}
+
+	for i, tCase := range testCases {
+		rule, ok := parseIgnoreRule(tCase.line)
+		util.ExpectEqual("ignore_strategy parseIgnoreRule I", i+1, t.Errorf, tCase.ok, ok)
+		if ok {
+			util.ExpectEqual("ignore_strategy parseIgnoreRule II", i+1, t.Errorf, tCase.rule, rule)
+		}
+	}
+}
+
+type ignoreMatcherType struct {
+	path    string
+	ignored bool
+}
+
+func TestIgnoreMatcherIsIgnored(t *testing.T) {
+	matcher := &ignoreMatcher{
+		baseDir: "bucket/root/",
+		rules: parseIgnoreRules([]string{
+			"*.log",
+			"!keep.log",
+			"build/",
+			"/doc/*.txt",
+			"tmp",
+			"**/cache/**",
+		}),
+	}
+
+	testCases := []ignoreMatcherType{
+		//1
+		ignoreMatcherType{path: "bucket/root/a.log", ignored: true},
+		//2
+		ignoreMatcherType{path: "bucket/root/dir/a.log", ignored: true},
+		//3
+		ignoreMatcherType{path: "bucket/root/dir/keep.log", ignored: false},
+		//4 directory only
+		ignoreMatcherType{path: "bucket/root/build", ignored: false},
+		//5
+		ignoreMatcherType{path: "bucket/root/build/", ignored: true},
+		//6 children of ignored directory
+		ignoreMatcherType{path: "bucket/root/src/build/keep.log", ignored: true},
+		//7 anchored
+		ignoreMatcherType{path: "bucket/root/doc/a.txt", ignored: true},
+		//8
+		ignoreMatcherType{path: "bucket/root/src/doc/a.txt", ignored: false},
+		//9
+		ignoreMatcherType{path: "bucket/root/doc/sub/a.txt", ignored: false},
+		//10
+		ignoreMatcherType{path: "bucket/root/a/tmp/b.txt", ignored: true},
+		//11 not under base dir
+		ignoreMatcherType{path: "bucket/other/a.log", ignored: false},
+		//12 globstar
+		ignoreMatcherType{path: "bucket/root/a/b/cache/c/d.txt", ignored: true},
+		//13
+		ignoreMatcherType{path: "bucket/root/cache/d.txt", ignored: true},
+	}
+
+	for i, tCase := range testCases {
+		ignored, err := matcher.isIgnored(tCase.path, "/")
+		util.ExpectEqual("ignore_strategy isIgnored I", i+1, t.Errorf, true, err == nil)
+		util.ExpectEqual("ignore_strategy isIgnored II", i+1, t.Errorf, tCase.ignored, ignored)
+	}
+}
+
+func TestIsIgnoredByMatchers(t *testing.T) {
+	matchers := []*ignoreMatcher{
+		&ignoreMatcher{
+			baseDir: "/root/",
+			rules:   parseIgnoreRules([]string{"*.log", "data/"}),
+		},
+		&ignoreMatcher{
+			baseDir: "/root/sub/",
+			rules:   parseIgnoreRules([]string{"!*.log", "*.tmp"}),
+		},
+	}
+
+	testCases := []ignoreMatcherType{
+		//1
+		ignoreMatcherType{path: "/root/a.log", ignored: true},
+		//2 deeper ignore file take precedence
+		ignoreMatcherType{path: "/root/sub/a.log", ignored: false},
+		//3
+		ignoreMatcherType{path: "/root/a.tmp", ignored: false},
+		//4
+		ignoreMatcherType{path: "/root/sub/a.tmp", ignored: true},
+		//5
+		ignoreMatcherType{path: "/root/sub/data/", ignored: true},
+		//6
+		ignoreMatcherType{path: "/root/sub/data", ignored: false},
+	}
+
+	for i, tCase := range testCases {
+		ignored, err := isIgnoredByMatchers(matchers, tCase.path, "/")
+		util.ExpectEqual("ignore_strategy isIgnoredByMatchers I", i+1, t.Errorf, true, err == nil)
+		util.ExpectEqual("ignore_strategy isIgnoredByMatchers II", i+1, t.Errorf, tCase.ignored,
+			ignored)
+	}
+}
+
+func TestLocalFileIteratorWithIgnoreFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "bcecmd.ignore.")
+	if err != nil {
+		t.Errorf("create temp dir failed, error: %s", err)
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		IGNORE_FILE_NAME:          "*.log\nbuild/\n",
+		"a.txt":                   "a",
+		"a.log":                   "a",
+		"build/b.txt":             "b",
+		"sub/" + IGNORE_FILE_NAME: "!keep.log\n",
+		"sub/c.log":               "c",
+		"sub/keep.log":            "c",
+		"sub/d.txt":               "d",
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Errorf("create dir failed, error: %s", err)
+			return
+		}
+		if err := ioutil.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Errorf("create file failed, error: %s", err)
+			return
+		}
+	}
+
+	//1 the ignore files are not listed
+	expected := []string{"a.txt", "sub/d.txt", "sub/keep.log"}
+	util.ExpectEqual("ignore_strategy LocalFileIterator", 1, t.Errorf, expected,
+		listLocalFileKeys(t, tmpDir, true))
+
+	//2 --no-ignore-file
+	expected = []string{IGNORE_FILE_NAME, "a.log", "a.txt", "build/b.txt",
+		"sub/" + IGNORE_FILE_NAME, "sub/c.log", "sub/d.txt", "sub/keep.log"}
+	util.ExpectEqual("ignore_strategy LocalFileIterator", 2, t.Errorf, expected,
+		listLocalFileKeys(t, tmpDir, false))
+
+	//3 the ignore file is listed when it is the source
+	util.ExpectEqual("ignore_strategy LocalFileIterator", 3, t.Errorf,
+		[]string{IGNORE_FILE_NAME},
+		listLocalFileKeys(t, filepath.Join(tmpDir, IGNORE_FILE_NAME), true))
+}
+
+// list the keys of local files
+func listLocalFileKeys(t *testing.T, localPath string, useIgnoreFile bool) []string {
+	listed := []string{}
+	list := NewLocalFileIterator(localPath, nil, true, useIgnoreFile)
+	for {
+		fileInfo, err := list.next()
+		if err != nil {
+			t.Errorf("list local files failed, error: %s", err)
+			return listed
+		}
+		if fileInfo.err != nil {
+			t.Errorf("list local files failed, error: %s", fileInfo.err)
+			return listed
+		}
+		if fileInfo.ended {
+			return listed
+		}
+		listed = append(listed, filepath.ToSlash(fileInfo.file.key))
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the parsing and validation of bucket lifecycle configuration.
+
+package boscli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	LIFECYCLE_MAX_RULES   = 1000
+	LIFECYCLE_MAX_ID_SIZE = 255
+)
+
+// starter lifecycle configuration printed by put-lifecycle --template
+const LIFECYCLE_TEMPLATE = `{
+  "Rules": [
+    {
+      "ID": "transition-and-expire-logs",
+      "Status": "Enabled",
+      "Filter": {
+        "Prefix": "logs/"
+      },
+      "Transitions": [
+        {
+          "Days": 30,
+          "StorageClass": "STANDARD_IA"
+        }
+      ],
+      "Expiration": {
+        "Days": 365
+      }
+    },
+    {
+      "ID": "abort-incomplete-uploads",
+      "Status": "Enabled",
+      "Filter": {
+        "Prefix": ""
+      },
+      "AbortIncompleteMultipartUpload": {
+        "DaysAfterInitiation": 7
+      }
+    }
+  ]
+}`
+
+// Parse lifecycle configuration in json, the field names are the same as S3 lifecycle API.
+// Unknown fields are rejected, so that typos are found before the configuration is put.
+func parseLifecycleConfig(lifecycle []byte) (*s3.BucketLifecycleConfiguration, error) {
+	config := &s3.BucketLifecycleConfiguration{}
+	decoder := json.NewDecoder(bytes.NewReader(lifecycle))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid lifecycle configuration: %s", err)
+	}
+	if err := validateLifecycleConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// validate lifecycle configuration locally
+func validateLifecycleConfig(config *s3.BucketLifecycleConfiguration) error {
+	if len(config.Rules) == 0 {
+		return fmt.Errorf("lifecycle configuration must have at least one rule")
+	} else if len(config.Rules) > LIFECYCLE_MAX_RULES {
+		return fmt.Errorf("lifecycle configuration can have at most %d rules",
+			LIFECYCLE_MAX_RULES)
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid lifecycle configuration: %s", err)
+	}
+
+	ids := make(map[string]bool, len(config.Rules))
+	for i, rule := range config.Rules {
+		if rule == nil {
+			return fmt.Errorf("rule %d is empty", i+1)
+		}
+		if rule.ID != nil {
+			if len(*rule.ID) > LIFECYCLE_MAX_ID_SIZE {
+				return fmt.Errorf("rule %d: ID is longer than %d", i+1, LIFECYCLE_MAX_ID_SIZE)
+			} else if ids[*rule.ID] {
+				return fmt.Errorf("rule %d: ID '%s' is duplicate", i+1, *rule.ID)
+			}
+			ids[*rule.ID] = true
+		}
+		if err := validateLifecycleRule(rule); err != nil {
+			return fmt.Errorf("rule %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validate a lifecycle rule
+func validateLifecycleRule(rule *s3.LifecycleRule) error {
+	if status := *rule.Status; status != s3.ExpirationStatusEnabled &&
+		status != s3.ExpirationStatusDisabled {
+		return fmt.Errorf("Status must be '%s' or '%s'", s3.ExpirationStatusEnabled,
+			s3.ExpirationStatusDisabled)
+	}
+	if rule.Prefix != nil && rule.Filter != nil {
+		return fmt.Errorf("Prefix and Filter can't be set at the same time")
+	}
+	if filter := rule.Filter; filter != nil {
+		set := 0
+		for _, ok := range []bool{filter.Prefix != nil, filter.Tag != nil, filter.And != nil} {
+			if ok {
+				set++
+			}
+		}
+		if set > 1 {
+			return fmt.Errorf("Filter can only have one of Prefix, Tag and And")
+		}
+	}
+
+	if rule.Expiration == nil && len(rule.Transitions) == 0 &&
+		rule.NoncurrentVersionExpiration == nil && len(rule.NoncurrentVersionTransitions) == 0 &&
+		rule.AbortIncompleteMultipartUpload == nil {
+		return fmt.Errorf("there is no action, one of Expiration, Transitions, " +
+			"NoncurrentVersionExpiration, NoncurrentVersionTransitions and " +
+			"AbortIncompleteMultipartUpload is required")
+	}
+
+	if exp := rule.Expiration; exp != nil {
+		set := 0
+		for _, ok := range []bool{exp.Days != nil, exp.Date != nil,
+			exp.ExpiredObjectDeleteMarker != nil} {
+			if ok {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("Expiration must have one of Days, Date and " +
+				"ExpiredObjectDeleteMarker")
+		} else if exp.Days != nil && *exp.Days <= 0 {
+			return fmt.Errorf("Days of Expiration must be greater than 0")
+		}
+	}
+	for _, trans := range rule.Transitions {
+		if trans == nil {
+			return fmt.Errorf("transition is empty")
+		} else if trans.StorageClass == nil || *trans.StorageClass == "" {
+			return fmt.Errorf("StorageClass of transition is required")
+		} else if (trans.Days == nil) == (trans.Date == nil) {
+			return fmt.Errorf("transition must have one of Days and Date")
+		} else if trans.Days != nil && *trans.Days < 0 {
+			return fmt.Errorf("Days of transition can't be negative")
+		}
+	}
+	if exp := rule.NoncurrentVersionExpiration; exp != nil {
+		if exp.NoncurrentDays == nil || *exp.NoncurrentDays <= 0 {
+			return fmt.Errorf("NoncurrentDays of NoncurrentVersionExpiration must be greater " +
+				"than 0")
+		}
+	}
+	for _, trans := range rule.NoncurrentVersionTransitions {
+		if trans == nil || trans.StorageClass == nil || *trans.StorageClass == "" ||
+			trans.NoncurrentDays == nil || *trans.NoncurrentDays < 0 {
+			return fmt.Errorf("NoncurrentVersionTransitions must have StorageClass and " +
+				"non-negative NoncurrentDays")
+		}
+	}
+	if abort := rule.AbortIncompleteMultipartUpload; abort != nil {
+		if abort.DaysAfterInitiation == nil || *abort.DaysAfterInitiation <= 0 {
+			return fmt.Errorf("DaysAfterInitiation of AbortIncompleteMultipartUpload must be " +
+				"greater than 0")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+)
+
+import (
+	"utils/util"
+)
+
+type parseLifecycleConfigType struct {
+	lifecycle string
+	isSuc     bool
+}
+
+func TestParseLifecycleConfig(t *testing.T) {
+	testCases := []parseLifecycleConfigType{
+		//1
+		parseLifecycleConfigType{lifecycle: LIFECYCLE_TEMPLATE, isSuc: true},
+		//2 field names are case insensitive
+		parseLifecycleConfigType{lifecycle: `{"rules": [{"id": "a", "status": "Disabled",
+			"expiration": {"date": "2030-01-01T00:00:00Z"}}]}`, isSuc: true},
+		//3 not json
+		parseLifecycleConfigType{lifecycle: "rules"},
+		//4 unknown field
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled",
+			"Expire": {"Days": 1}}]}`},
+		//5 no rule
+		parseLifecycleConfigType{lifecycle: `{"Rules": []}`},
+		//6 no status
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Expiration": {"Days": 1}}]}`},
+		//7 invalid status
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "enabled",
+			"Expiration": {"Days": 1}}]}`},
+		//8 no action
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled"}]}`},
+		//9 days of expiration is 0
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled",
+			"Expiration": {"Days": 0}}]}`},
+		//10 transition without storage class
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled",
+			"Transitions": [{"Days": 1}]}]}`},
+		//11 duplicate id
+		parseLifecycleConfigType{lifecycle: `{"Rules": [
+			{"ID": "a", "Status": "Enabled", "Expiration": {"Days": 1}},
+			{"ID": "a", "Status": "Enabled", "Expiration": {"Days": 2}}]}`},
+		//12 prefix and filter
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled", "Prefix": "a",
+			"Filter": {"Prefix": "b"}, "Expiration": {"Days": 1}}]}`},
+		//13 filter with prefix and tag
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled",
+			"Filter": {"Prefix": "b", "Tag": {"Key": "k", "Value": "v"}},
+			"Expiration": {"Days": 1}}]}`},
+		//14 abort incomplete uploads
+		parseLifecycleConfigType{lifecycle: `{"Rules": [{"Status": "Enabled",
+			"AbortIncompleteMultipartUpload": {"DaysAfterInitiation": 0}}]}`},
+	}
+	for i, tCase := range testCases {
+		_, err := parseLifecycleConfig([]byte(tCase.lifecycle))
+		util.ExpectEqual("parse lifecycle", i+1, t.Errorf, tCase.isSuc, err == nil)
+	}
+}
+
+func TestFormatApiJson(t *testing.T) {
+	config, err := parseLifecycleConfig([]byte(LIFECYCLE_TEMPLATE))
+	util.ExpectEqual("format api json", 1, t.Errorf, nil, err)
+	out, err := formatApiJson(config)
+	util.ExpectEqual("format api json", 2, t.Errorf, nil, err)
+
+	// the output can be put again
+	again, err := parseLifecycleConfig([]byte(out))
+	util.ExpectEqual("format api json", 3, t.Errorf, nil, err)
+	util.ExpectEqual("format api json", 4, t.Errorf, config.String(), again.String())
+}
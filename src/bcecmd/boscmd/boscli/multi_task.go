@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -37,6 +38,9 @@ const (
 	SRC_IS_STREAM = "srcIsStream"
 	MD5_CAlC_SIZE = 1024 * 1024
 	FLUSH_PERIOD  = 20 * time.Second
+
+	BREAK_POINT_RECORD_VERSION = 1
+	BREAK_POINT_TEMP_SUFFIX    = ".tmp"
 )
 
 type CompletePartInfo struct {
@@ -45,8 +49,17 @@ type CompletePartInfo struct {
 }
 
 type BreakPointRecord struct {
+	Version             int                `json:"version"`
+	Checksum            string             `json:"checksum"` // md5 of record without checksum
+	SrcType             string             `json:"srcType"`
+	DstType             string             `json:"dstType"`
+	SrcPath             string             `json:"srcPath"`
+	DstPath             string             `json:"dstPath"`
+	DstRemote           string             `json:"dstRemote,omitempty"` // empty for default
+	DstBucketName       string             `json:"dstBucketName"`
+	DstObjectKey        string             `json:"dstObjectKey"`
 	Md5Val              string             `json:"md5"`
-	UploadId            string             `json:"uploadId"`
+	UploadId            string             `json:"uploadId"` // temp file path of download
 	SrcFileSize         int64              `json:"fileSize"`
 	SrcFileLastModified int64              `json:"fileModifyTime"`
 	PartsNum            int64              `json:"partsNum"`
@@ -61,6 +74,8 @@ type MultiTaskContent struct {
 	dstType             string
 	srcFilePath         string
 	dstFilePath         string
+	srcRemote           string // the remote of source object, empty for default
+	dstRemote           string // the remote of destination object, empty for default
 	srcBucketName       string
 	srcObjectKey        string
 	dstBucketName       string
@@ -101,20 +116,20 @@ func (m *MultiTaskContent) init(srcBucketName, srcObjectKey, dstBucketName, dstO
 	if srcType == IS_LOCAL {
 		m.srcFilePath = srcObjectKey
 	} else if srcType == IS_BOS {
-		m.srcFilePath = BOS_PATH_PREFIX + srcBucketName + util.BOS_PATH_SEPARATOR + srcObjectKey
+		m.srcFilePath = joinRemotePath(m.srcRemote, srcBucketName, srcObjectKey)
 	}
 
 	if dstType == IS_LOCAL {
 		m.dstFilePath = dstObjectKey
 	} else if dstType == IS_BOS {
-		m.dstFilePath = BOS_PATH_PREFIX + dstBucketName + util.BOS_PATH_SEPARATOR + dstObjectKey
+		m.dstFilePath = joinRemotePath(m.dstRemote, dstBucketName, dstObjectKey)
 	}
 
 	m.srcFileSize = fileSize
 	m.srcFileLastModified = fileMtime
 	m.md5Val = md5Val
 
-	// calc the file path of breakPointRecord
+	// calc the file path of breakPointRecord, the paths of objects include their remotes
 	m.contentId = util.StringMd5(m.srcFilePath + "_" + m.dstFilePath)
 	m.breakPointPath = filepath.Join(bceconf.MultiuploadFolder, m.contentId)
 
@@ -129,16 +144,12 @@ func (m *MultiTaskContent) init(srcBucketName, srcObjectKey, dstBucketName, dstO
 	// otherwise, reading info from breakPointRecord
 	m.partSize = -1
 	if !restart && util.DoesFileExist(m.breakPointPath) {
-		breakPointTemp := &BreakPointRecord{}
-
-		fd, err := os.Open(m.breakPointPath)
+		breakPointTemp, err := readBreakPointRecord(m.breakPointPath)
 		if err != nil {
-			return err
-		}
-
-		breakPointDecoder := json.NewDecoder(fd)
-		if err = breakPointDecoder.Decode(breakPointTemp); err != nil {
-			return fmt.Errorf("can not get breakPointRecord from path %s", m.breakPointPath)
+			// the record is corrupt, e.g. bcecmd crashed when writing it, rebuild it
+			log.Debugf("%s => %s, discard breakpoint record %s: %s", m.srcFilePath,
+				m.dstFilePath, m.breakPointPath, err)
+			breakPointTemp = &BreakPointRecord{}
 		}
 
 		m.uploadId = breakPointTemp.UploadId
@@ -218,7 +229,8 @@ func (m *MultiTaskContent) breakPointRecordIsValid(record *BreakPointRecord) boo
 
 func (m *MultiTaskContent) finishPart(partNumber int64, eTag string) error {
 	if partNumber < 1 || partNumber > m.partsNum {
-		return fmt.Errorf("part number %d is invalid! part number not in [1, %d]", m.partsNum)
+		return fmt.Errorf("part number %d is invalid! part number not in [1, %d]", partNumber,
+			m.partsNum)
 	} else if eTag == "" {
 		return fmt.Errorf("the etag of part %d is empty", partNumber)
 	}
@@ -314,6 +326,13 @@ func (m *MultiTaskContent) snapshot() *BreakPointRecord {
 	defer m.rwmutex.RUnlock()
 
 	breakPointTemp := &BreakPointRecord{
+		SrcType:             m.srcType,
+		DstType:             m.dstType,
+		SrcPath:             m.srcFilePath,
+		DstPath:             m.dstFilePath,
+		DstRemote:           m.dstRemote,
+		DstBucketName:       m.dstBucketName,
+		DstObjectKey:        m.dstObjectKey,
 		Md5Val:              m.md5Val,
 		UploadId:            m.uploadId,
 		SrcFileSize:         m.srcFileSize,
@@ -337,17 +356,7 @@ func (m *MultiTaskContent) flush(breakPointTemp *BreakPointRecord) error {
 	m.flushLock.Lock()
 	defer m.flushLock.Unlock()
 
-	fd, err := os.OpenFile(m.breakPointPath, os.O_RDWR|os.O_CREATE, 0755)
-	if err != nil {
-		return err
-	}
-	defer fd.Close()
-
-	bytes, err := json.Marshal(breakPointTemp)
-	if err != nil {
-		return err
-	}
-	if _, err = fd.Write(bytes); err != nil {
+	if err := writeBreakPointRecord(m.breakPointPath, breakPointTemp); err != nil {
 		return err
 	}
 	m.lastFlushTime = breakPointTemp.RecordTime
@@ -382,14 +391,86 @@ func (m *MultiTaskContent) Remove() error {
 
 	m.isDirty = false
 
+	var err error
 	if util.DoesFileExist(m.breakPointPath) {
-		return os.Remove(m.breakPointPath)
+		err = os.Remove(m.breakPointPath)
 	}
 
-	if m.dstType == IS_LOCAL && util.DoesFileExist(m.uploadId) {
-		return os.Remove(m.uploadId)
+	// the temp file of download
+	if m.dstType == IS_LOCAL && m.uploadId != "" && util.DoesFileExist(m.uploadId) {
+		if removeErr := os.Remove(m.uploadId); err == nil {
+			err = removeErr
+		}
 	}
-	return nil
+	return err
+}
+
+// Read the record of breakpoint transmission from path, and check its version and checksum.
+func readBreakPointRecord(path string) (*BreakPointRecord, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	record := &BreakPointRecord{}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("can not decode breakPointRecord: %s", err)
+	}
+	if record.Version != BREAK_POINT_RECORD_VERSION {
+		return nil, fmt.Errorf("unsupported version %d of breakPointRecord", record.Version)
+	}
+	checksum, err := record.calcChecksum()
+	if err != nil {
+		return nil, err
+	}
+	if checksum != record.Checksum {
+		return nil, fmt.Errorf("checksum of breakPointRecord mismatch")
+	}
+	return record, nil
+}
+
+// Write the record of breakpoint transmission to path atomically: write it to a temp file,
+// sync and then rename the temp file to path, so that a crash never leaves a half written
+// record.
+func writeBreakPointRecord(path string, record *BreakPointRecord) error {
+	record.Version = BREAK_POINT_RECORD_VERSION
+	checksum, err := record.calcChecksum()
+	if err != nil {
+		return err
+	}
+	record.Checksum = checksum
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	tempPath := path + BREAK_POINT_TEMP_SUFFIX
+	fd, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err = fd.Write(data); err == nil {
+		err = fd.Sync()
+	}
+	if closeErr := fd.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
+
+// md5 of the record whose checksum is empty
+func (r *BreakPointRecord) calcChecksum() (string, error) {
+	temp := *r
+	temp.Checksum = ""
+	data, err := json.Marshal(&temp)
+	if err != nil {
+		return "", err
+	}
+	md5Val := md5.Sum(data)
+	return hex.EncodeToString(md5Val[:]), nil
 }
 
 // flush the record of breakpoint transmission to disk when bcecmd non-mormal exit
@@ -0,0 +1,79 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+import (
+	"utils/util"
+)
+
+func TestWriteAndReadBreakPointRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bcecmd.breakpoint.")
+	if err != nil {
+		t.Fatalf("create temp dir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "record")
+
+	long := &BreakPointRecord{
+		Md5Val:   "etag",
+		UploadId: "upload-id-long",
+		PartsNum: 3,
+		PartSize: 1024,
+		CompltePartList: []CompletePartInfo{
+			CompletePartInfo{PartNumberId: 1, ETag: "a"},
+			CompletePartInfo{PartNumberId: 2, ETag: "b"},
+		},
+	}
+	short := &BreakPointRecord{Md5Val: "etag", UploadId: "id", PartsNum: 3, PartSize: 1024}
+
+	//1 a shorter record overwrites a longer one
+	util.ExpectEqual("breakpoint record I", 1, t.Errorf, nil, writeBreakPointRecord(path, long))
+	util.ExpectEqual("breakpoint record I", 2, t.Errorf, nil, writeBreakPointRecord(path, short))
+	record, err := readBreakPointRecord(path)
+	util.ExpectEqual("breakpoint record I", 3, t.Errorf, nil, err)
+	if record != nil {
+		util.ExpectEqual("breakpoint record I", 4, t.Errorf, "id", record.UploadId)
+		util.ExpectEqual("breakpoint record I", 5, t.Errorf, 0, len(record.CompltePartList))
+		util.ExpectEqual("breakpoint record I", 6, t.Errorf, BREAK_POINT_RECORD_VERSION,
+			record.Version)
+	}
+	util.ExpectEqual("breakpoint record I", 7, t.Errorf, false,
+		util.DoesFileExist(path+BREAK_POINT_TEMP_SUFFIX))
+
+	data, _ := ioutil.ReadFile(path)
+	corrupts := [][]byte{
+		//2 truncated
+		data[:len(data)/2],
+		//3 trailing garbage
+		append(append([]byte{}, data...), []byte("d\":1}")...),
+		//4 content is changed
+		bytes.Replace(data, []byte(`"uploadId":"id"`), []byte(`"uploadId":"ix"`), 1),
+		//5 record without version and checksum
+		[]byte(`{"md5":"etag","uploadId":"id","partsNum":3,"partSize":1024}`),
+	}
+	for i, corrupt := range corrupts {
+		if err := ioutil.WriteFile(path, corrupt, 0644); err != nil {
+			t.Fatalf("write record failed: %s", err)
+		}
+		_, err := readBreakPointRecord(path)
+		util.ExpectEqual("breakpoint record II", i+2, t.Errorf, true, err != nil)
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the resume of multipart upload from the parts uploaded to server.
+
+package boscli
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"github.com/baidubce/bce-sdk-go/util/log"
+)
+
+const (
+	LIST_PARTS_MAX_NUM   = 1000
+	LIST_UPLOADS_MAX_NUM = 1000
+)
+
+// whether to discover an in-progress upload of the same object when there is no breakpoint record
+var resumeFromServer bool
+
+// SetResumeFromServer - when there is no valid breakpoint record of a multipart upload, resume
+// the latest in-progress upload of the same object on server instead of starting a new one.
+func (b *BosCli) SetResumeFromServer(resume bool) {
+	resumeFromServer = resume
+}
+
+// Whether an upload without breakpoint record can resume the in-progress upload on server.
+// The acl, tags, server-side encryption and metadata of an upload are set when it is initiated,
+// and they can't be got from server, so the upload with any of them isn't resumed from server,
+// otherwise the object may be completed with the ones of another upload. The upload of encrypted
+// file is never resumed, as the data key is new every time.
+func canResumeFromServer(restart bool, meta map[string]*string) bool {
+	return resumeFromServer && !restart && clientEncryption == nil && len(meta) == 0 &&
+		uploadAcl == "" && uploadTagging == "" && transferSse.sse == "" &&
+		transferSse.customerKey == ""
+}
+
+// List all parts of a multipart upload
+func listAllParts(bosClient bosClientInterface, bucketName, objectKey,
+	uploadId string) ([]*s3.Part, error) {
+
+	var (
+		parts  []*s3.Part
+		marker int64
+	)
+	for {
+		ret, err := bosClient.ListParts(bucketName, objectKey, uploadId, marker,
+			LIST_PARTS_MAX_NUM)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, ret.Parts...)
+		if !aws.BoolValue(ret.IsTruncated) || ret.NextPartNumberMarker == nil {
+			return parts, nil
+		}
+		marker = *ret.NextPartNumberMarker
+	}
+}
+
+// List all in-progress multipart uploads whose key has prefix
+func listAllUploads(bosClient bosClientInterface, bucketName,
+	prefix string) ([]*s3.MultipartUpload, error) {
+
+	var uploads []*s3.MultipartUpload
+	err := walkUploads(bosClient, bucketName, prefix, func(upload *s3.MultipartUpload) bool {
+		uploads = append(uploads, upload)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// Call fn on the in-progress multipart uploads whose key has prefix, in the order of listing,
+// until fn returns false. The uploads are listed in the order of key.
+func walkUploads(bosClient bosClientInterface, bucketName, prefix string,
+	fn func(*s3.MultipartUpload) bool) error {
+
+	var (
+		keyMarker      string
+		uploadIdMarker string
+	)
+	for {
+		ret, err := bosClient.ListMultipartUploads(bucketName, prefix, keyMarker,
+			uploadIdMarker, LIST_UPLOADS_MAX_NUM)
+		if err != nil {
+			return err
+		}
+		for _, upload := range ret.Uploads {
+			if !fn(upload) {
+				return nil
+			}
+		}
+		if !aws.BoolValue(ret.IsTruncated) || ret.NextKeyMarker == nil {
+			return nil
+		}
+		keyMarker = *ret.NextKeyMarker
+		uploadIdMarker = aws.StringValue(ret.NextUploadIdMarker)
+	}
+}
+
+// Find the latest in-progress multipart upload of object, storage class is ignored when it is
+// empty. The listing stops at the first upload of another key after objectKey, as all uploads
+// of objectKey have been listed.
+// RETURN: nil when there is no such upload
+func findLatestUpload(bosClient bosClientInterface, bucketName, objectKey,
+	storageClass string) (*s3.MultipartUpload, error) {
+
+	var latest *s3.MultipartUpload
+	err := walkUploads(bosClient, bucketName, objectKey, func(upload *s3.MultipartUpload) bool {
+		key := aws.StringValue(upload.Key)
+		if key > objectKey {
+			return false
+		}
+		if key != objectKey || upload.UploadId == nil {
+			return true
+		}
+		if storageClass != "" && upload.StorageClass != nil &&
+			*upload.StorageClass != storageClass {
+			return true
+		}
+		if latest == nil || aws.TimeValue(upload.Initiated).After(
+			aws.TimeValue(latest.Initiated)) {
+			latest = upload
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// Resume the latest in-progress upload of the same object on server, the part size is the size
+// of part 1 of that upload.
+// RETURN: the parts of the resumed upload, content.needRestart is still true when there is no
+// upload which can be resumed
+func (m *MultiTaskContent) resumeFromServerUpload(bosClient bosClientInterface,
+	storageClass string) ([]*s3.Part, error) {
+
+	upload, err := findLatestUpload(bosClient, m.dstBucketName, m.dstObjectKey, storageClass)
+	if err != nil || upload == nil {
+		return nil, err
+	}
+	uploadId := *upload.UploadId
+	parts, err := listAllParts(bosClient, m.dstBucketName, m.dstObjectKey, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	partSize := int64(0)
+	for _, part := range parts {
+		if aws.Int64Value(part.PartNumber) == 1 {
+			partSize = aws.Int64Value(part.Size)
+		}
+	}
+	if partSize <= 0 || (m.srcFileSize+partSize-1)/partSize > MAX_PARTS {
+		log.Debugf("%s => %s, can't resume upload %s on server, because the part size is "+
+			"unknown or too small", m.srcFilePath, m.dstFilePath, uploadId)
+		return nil, nil
+	}
+
+	log.Debugf("%s => %s, resume upload %s on server, part size is %d", m.srcFilePath,
+		m.dstFilePath, uploadId, partSize)
+	m.clear()
+	m.uploadId = uploadId
+	m.partSize = partSize
+	m.partsNum = (m.srcFileSize + partSize - 1) / partSize
+	m.needRestart = false
+	return parts, nil
+}
+
+// Make the finished parts consistent with the parts on server: a finished part which isn't on
+// server or whose etag or size is different is uploaded again, and a part on server which isn't
+// finished locally is used when its size and md5 match the local file.
+func (m *MultiTaskContent) reconcileParts(parts []*s3.Part, fd *os.File) error {
+	serverParts := make(map[int64]*s3.Part, len(parts))
+	for _, part := range parts {
+		serverParts[aws.Int64Value(part.PartNumber)] = part
+	}
+
+	m.rwmutex.Lock()
+	defer m.rwmutex.Unlock()
+
+	changed := false
+	for partNumber, local := range m.compltePartList {
+		server, ok := serverParts[partNumber]
+		if ok && trimETag(aws.StringValue(server.ETag)) == trimETag(local.ETag) &&
+			aws.Int64Value(server.Size) == m.getPartSize(partNumber) {
+			continue
+		}
+		log.Debugf("%s => %s, part %d isn't uploaded to server, upload it again",
+			m.srcFilePath, m.dstFilePath, partNumber)
+		delete(m.compltePartList, partNumber)
+		m.compltePartNum--
+		changed = true
+	}
+
+	for partNumber, server := range serverParts {
+		if _, ok := m.compltePartList[partNumber]; ok {
+			continue
+		}
+		if partNumber < 1 || partNumber > m.partsNum {
+			continue
+		}
+		size := m.getPartSize(partNumber)
+		etag := aws.StringValue(server.ETag)
+		if aws.Int64Value(server.Size) != size {
+			continue
+		}
+		match, err := partMatchesETag(fd, (partNumber-1)*m.partSize, size, etag)
+		if err != nil {
+			return err
+		}
+		if !match {
+			continue
+		}
+		log.Debugf("%s => %s, part %d has been uploaded to server", m.srcFilePath,
+			m.dstFilePath, partNumber)
+		m.compltePartList[partNumber] = CompletePartInfo{
+			PartNumberId: partNumber,
+			ETag:         etag,
+		}
+		m.compltePartNum++
+		changed = true
+	}
+
+	if changed {
+		m.isDirty = true
+		m.lastFinshPartTime = time.Now().Unix()
+	}
+	return nil
+}
+
+// the size of part partNumber, the last part may be smaller than part size
+func (m *MultiTaskContent) getPartSize(partNumber int64) int64 {
+	if partNumber == m.partsNum {
+		return m.srcFileSize - (m.partsNum-1)*m.partSize
+	}
+	return m.partSize
+}
+
+// Whether the md5 of size bytes of fd from offset is etag, etag which isn't md5 (e.g. the part
+// is encrypted by server) never matches.
+func partMatchesETag(fd *os.File, offset, size int64, etag string) (bool, error) {
+	etag = strings.ToLower(trimETag(etag))
+	if len(etag) != md5.Size*2 {
+		return false, nil
+	}
+	md5New := md5.New()
+	copied, err := io.Copy(md5New, io.NewSectionReader(fd, offset, size))
+	if err != nil {
+		return false, err
+	} else if copied != size {
+		return false, nil
+	}
+	return hex.EncodeToString(md5New.Sum(nil)) == etag, nil
+}
+
+// etag of server may be quoted
+func trimETag(etag string) string {
+	return strings.Trim(etag, "\"")
+}
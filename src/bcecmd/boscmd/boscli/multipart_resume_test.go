@@ -0,0 +1,264 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"utils/util"
+)
+
+// client which lists parts and uploads from memory, one item a page
+type fakeMultipartListClient struct {
+	bosClientInterface
+	parts   []*s3.Part
+	uploads []*s3.MultipartUpload
+	listed  int // the number of uploads listed
+}
+
+func (f *fakeMultipartListClient) ListParts(bucket, object, uploadId string,
+	partNumberMarker int64, maxParts int) (*s3.ListPartsOutput, error) {
+
+	ret := &s3.ListPartsOutput{IsTruncated: aws.Bool(false)}
+	for i, part := range f.parts {
+		if *part.PartNumber > partNumberMarker {
+			ret.Parts = []*s3.Part{part}
+			ret.IsTruncated = aws.Bool(i < len(f.parts)-1)
+			ret.NextPartNumberMarker = part.PartNumber
+			break
+		}
+	}
+	return ret, nil
+}
+
+func (f *fakeMultipartListClient) ListMultipartUploads(bucket, prefix, keyMarker,
+	uploadIdMarker string, maxUploads int) (*s3.ListMultipartUploadsOutput, error) {
+
+	ret := &s3.ListMultipartUploadsOutput{IsTruncated: aws.Bool(false)}
+	for i, upload := range f.uploads {
+		if *upload.Key > keyMarker || (*upload.Key == keyMarker &&
+			*upload.UploadId > uploadIdMarker) {
+			ret.Uploads = []*s3.MultipartUpload{upload}
+			f.listed++
+			ret.IsTruncated = aws.Bool(i < len(f.uploads)-1)
+			ret.NextKeyMarker = upload.Key
+			ret.NextUploadIdMarker = upload.UploadId
+			break
+		}
+	}
+	return ret, nil
+}
+
+func newTestPart(partNumber, size int64, etag string) *s3.Part {
+	return &s3.Part{PartNumber: aws.Int64(partNumber), Size: aws.Int64(size),
+		ETag: aws.String(etag)}
+}
+
+func newTestUpload(key, uploadId, storageClass string, initiated int64) *s3.MultipartUpload {
+	return &s3.MultipartUpload{Key: aws.String(key), UploadId: aws.String(uploadId),
+		StorageClass: aws.String(storageClass), Initiated: aws.Time(time.Unix(initiated, 0))}
+}
+
+func TestListAllParts(t *testing.T) {
+	client := &fakeMultipartListClient{parts: []*s3.Part{
+		newTestPart(1, 10, "a"), newTestPart(2, 10, "b"), newTestPart(5, 3, "c"),
+	}}
+	parts, err := listAllParts(client, "bucket", "object", "id")
+	util.ExpectEqual("list all parts", 1, t.Errorf, nil, err)
+	util.ExpectEqual("list all parts", 2, t.Errorf, 3, len(parts))
+}
+
+type findLatestUploadType struct {
+	storageClass string
+	uploadId     string
+	listed       int
+}
+
+func TestFindLatestUpload(t *testing.T) {
+	client := &fakeMultipartListClient{uploads: []*s3.MultipartUpload{
+		newTestUpload("object", "a", "STANDARD", 100),
+		newTestUpload("object", "b", "COLD", 300),
+		newTestUpload("object", "c", "STANDARD", 200),
+		newTestUpload("object1", "d", "STANDARD", 400),
+		newTestUpload("object2", "e", "STANDARD", 500),
+	}}
+	testCases := []findLatestUploadType{
+		//1 any storage class
+		findLatestUploadType{uploadId: "b", listed: 4},
+		//2 filter by storage class
+		findLatestUploadType{storageClass: "STANDARD", uploadId: "c", listed: 4},
+		//3 no upload
+		findLatestUploadType{storageClass: "ARCHIVE", listed: 4},
+	}
+	for i, tCase := range testCases {
+		client.listed = 0
+		upload, err := findLatestUpload(client, "bucket", "object", tCase.storageClass)
+		util.ExpectEqual("find latest upload", i+1, t.Errorf, nil, err)
+		uploadId := ""
+		if upload != nil {
+			uploadId = *upload.UploadId
+		}
+		util.ExpectEqual("find latest upload", i+1, t.Errorf, tCase.uploadId, uploadId)
+		// the uploads after the first one of another key aren't listed
+		util.ExpectEqual("find latest upload", i+1, t.Errorf, tCase.listed, client.listed)
+	}
+}
+
+func TestReconcileParts(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxy")
+	fd, err := ioutil.TempFile("", "bcecmd.resume.")
+	if err != nil {
+		t.Fatalf("create temp file failed: %s", err)
+	}
+	defer os.Remove(fd.Name())
+	defer fd.Close()
+	fd.Write(data)
+
+	partMd5 := func(start, end int) string {
+		val := md5.Sum(data[start:end])
+		return "\"" + hex.EncodeToString(val[:]) + "\""
+	}
+
+	content := &MultiTaskContent{
+		srcFileSize: int64(len(data)),
+		partSize:    10,
+		partsNum:    4,
+		compltePartList: map[int64]CompletePartInfo{
+			1: CompletePartInfo{PartNumberId: 1, ETag: partMd5(0, 10)},
+			2: CompletePartInfo{PartNumberId: 2, ETag: "stale"},
+			3: CompletePartInfo{PartNumberId: 3, ETag: partMd5(20, 30)},
+		},
+		compltePartNum: 3,
+	}
+	parts := []*s3.Part{
+		// same as local
+		newTestPart(1, 10, partMd5(0, 10)),
+		// etag is different from local
+		newTestPart(2, 10, partMd5(10, 20)),
+		// last part isn't finished locally
+		newTestPart(4, 5, partMd5(30, 35)),
+	}
+
+	err = content.reconcileParts(parts, fd)
+	util.ExpectEqual("reconcile parts", 1, t.Errorf, nil, err)
+	util.ExpectEqual("reconcile parts", 2, t.Errorf, 3, content.compltePartNum)
+	_, ok := content.compltePartList[1]
+	util.ExpectEqual("reconcile parts", 3, t.Errorf, true, ok)
+	// the etag on server is md5 of local data
+	info := content.compltePartList[2]
+	util.ExpectEqual("reconcile parts", 4, t.Errorf, partMd5(10, 20), info.ETag)
+	// part 3 isn't on server
+	_, ok = content.compltePartList[3]
+	util.ExpectEqual("reconcile parts", 5, t.Errorf, false, ok)
+	_, ok = content.compltePartList[4]
+	util.ExpectEqual("reconcile parts", 6, t.Errorf, true, ok)
+	util.ExpectEqual("reconcile parts", 7, t.Errorf, true, content.isDirty)
+}
+
+type partMatchesETagType struct {
+	offset int64
+	size   int64
+	etag   string
+	match  bool
+}
+
+func TestPartMatchesETag(t *testing.T) {
+	data := []byte("0123456789")
+	fd, err := ioutil.TempFile("", "bcecmd.resume.")
+	if err != nil {
+		t.Fatalf("create temp file failed: %s", err)
+	}
+	defer os.Remove(fd.Name())
+	defer fd.Close()
+	fd.Write(data)
+
+	val := md5.Sum(data[2:6])
+	etag := hex.EncodeToString(val[:])
+	testCases := []partMatchesETagType{
+		//1 match
+		partMatchesETagType{offset: 2, size: 4, etag: etag, match: true},
+		//2 quoted etag
+		partMatchesETagType{offset: 2, size: 4, etag: "\"" + etag + "\"", match: true},
+		//3 different data
+		partMatchesETagType{offset: 3, size: 4, etag: etag},
+		//4 etag isn't md5
+		partMatchesETagType{offset: 2, size: 4, etag: etag + "-2"},
+		//5 beyond end of file
+		partMatchesETagType{offset: 8, size: 4, etag: etag},
+	}
+	for i, tCase := range testCases {
+		match, err := partMatchesETag(fd, tCase.offset, tCase.size, tCase.etag)
+		util.ExpectEqual("part matches etag", i+1, t.Errorf, nil, err)
+		util.ExpectEqual("part matches etag", i+1, t.Errorf, tCase.match, match)
+	}
+}
+
+type canResumeFromServerType struct {
+	restart bool
+	meta    map[string]*string
+	acl     string
+	tagging string
+	sse     sseArgs
+	resume  bool
+}
+
+func TestCanResumeFromServer(t *testing.T) {
+	oldResume, oldAcl, oldTagging, oldSse := resumeFromServer, uploadAcl, uploadTagging,
+		transferSse
+	defer func() {
+		resumeFromServer, uploadAcl, uploadTagging, transferSse = oldResume, oldAcl,
+			oldTagging, oldSse
+	}()
+	resumeFromServer = true
+
+	testCases := []canResumeFromServerType{
+		//1
+		canResumeFromServerType{resume: true},
+		//2 restart
+		canResumeFromServerType{restart: true},
+		//3 metadata
+		canResumeFromServerType{meta: map[string]*string{"k": aws.String("v")}},
+		//4 acl
+		canResumeFromServerType{acl: "public-read"},
+		//5 tags
+		canResumeFromServerType{tagging: "env=prod"},
+		//6 sse
+		canResumeFromServerType{sse: sseArgs{sse: "AES256"}},
+		//7 SSE-C
+		canResumeFromServerType{sse: sseArgs{customerKey: "key"}},
+		//8 the copy source key isn't used by upload
+		canResumeFromServerType{sse: sseArgs{copySourceKey: "key"}, resume: true},
+	}
+	for i, tCase := range testCases {
+		uploadAcl, uploadTagging, transferSse = tCase.acl, tCase.tagging, tCase.sse
+		util.ExpectEqual("can resume from server", i+1, t.Errorf, tCase.resume,
+			canResumeFromServer(tCase.restart, tCase.meta))
+	}
+
+	//9 not enabled
+	resumeFromServer = false
+	uploadAcl, uploadTagging, transferSse = "", "", sseArgs{}
+	util.ExpectEqual("can resume from server", 9, t.Errorf, false, canResumeFromServer(false, nil))
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides a bounded worker pool for the batch operations of cp.
+
+package boscli
+
+import (
+	"fmt"
+	"sync"
+)
+
+import (
+	"bceconf"
+)
+
+// Get the concurrency of batch operations, the sync processing num in config file is used when
+// concurrency is not specified (equal to 0).
+func getConcurrency(concurrency int) (int, BosCliErrorCode, error) {
+	if concurrency < 0 {
+		return 0, BOSCLI_SYNC_PROCESS_NUM_LESS_ZERO, fmt.Errorf("concurrency must be greater " +
+			"than zero")
+	} else if concurrency == 0 {
+		var ok bool
+		concurrency, ok = bceconf.ServerConfigProvider.GetSyncProcessingNum()
+		if !ok {
+			return 0, BOSCLI_GET_SYNC_PROCESSING_NUM_FAILED, fmt.Errorf("There is no info " +
+				"about sync processing num found!")
+		}
+	}
+	return concurrency, BOSCLI_OK, nil
+}
+
+// opExecutor executes operations concurrently, at most concurrency operations are running at
+// the same time, and counts the number of successful and failed operations.
+type opExecutor struct {
+	opPool    chan int
+	wg        sync.WaitGroup
+	lock      sync.Mutex
+	failed    int
+	successed int
+}
+
+func newOpExecutor(concurrency int) *opExecutor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &opExecutor{
+		opPool: make(chan int, concurrency),
+	}
+}
+
+// Execute op in a new goroutine, block when the number of running operations reaches the
+// concurrency. op should print its error by itself.
+func (e *opExecutor) execute(op func() error) {
+	e.opPool <- 1
+	e.wg.Add(1)
+	go func() {
+		defer func() {
+			e.wg.Done()
+			<-e.opPool
+		}()
+		e.addResult(op() == nil)
+	}()
+}
+
+// Count the result of an operation which isn't executed by executor
+func (e *opExecutor) addResult(success bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if success {
+		e.successed++
+	} else {
+		e.failed++
+	}
+}
+
+// Wait for all operations finish, and return the result
+func (e *opExecutor) wait() *executeResult {
+	e.wg.Wait()
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return &executeResult{failed: e.failed, successed: e.successed}
+}
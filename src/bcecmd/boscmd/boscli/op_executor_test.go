@@ -0,0 +1,92 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"utils/util"
+)
+
+type opExecutorType struct {
+	concurrency int
+	opNum       int
+	failedEvery int
+	successed   int
+	failed      int
+}
+
+func TestOpExecutor(t *testing.T) {
+	testCases := []opExecutorType{
+		//1
+		opExecutorType{
+			concurrency: 1,
+			opNum:       10,
+			successed:   10,
+		},
+		//2
+		opExecutorType{
+			concurrency: 4,
+			opNum:       30,
+			failedEvery: 3,
+			successed:   20,
+			failed:      10,
+		},
+		//3 concurrency less than 1 is treated as 1
+		opExecutorType{
+			concurrency: 0,
+			opNum:       5,
+			failedEvery: 1,
+			failed:      5,
+		},
+	}
+
+	for i, tCase := range testCases {
+		var (
+			running    int32
+			maxRunning int32
+		)
+		executor := newOpExecutor(tCase.concurrency)
+		for j := 0; j < tCase.opNum; j++ {
+			id := j
+			executor.execute(func() error {
+				cur := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&maxRunning)
+					if cur <= old || atomic.CompareAndSwapInt32(&maxRunning, old, cur) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				if tCase.failedEvery > 0 && id%tCase.failedEvery == 0 {
+					return fmt.Errorf("failed")
+				}
+				return nil
+			})
+		}
+		ret := executor.wait()
+		util.ExpectEqual("op_executor I", i+1, t.Errorf, tCase.successed, ret.successed)
+		util.ExpectEqual("op_executor II", i+1, t.Errorf, tCase.failed, ret.failed)
+		expectedMax := int32(tCase.concurrency)
+		if expectedMax < 1 {
+			expectedMax = 1
+		}
+		util.ExpectEqual("op_executor III", i+1, t.Errorf, true, maxRunning <= expectedMax)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the reusable buffers of multipart upload.
+
+package boscli
+
+import (
+	"sync"
+)
+
+// buffers of parts, shared by all multipart uploads
+var partBuffers = newPartBufferPool()
+
+// partBufferPool reuses buffers of the same size, so that uploading large files doesn't
+// allocate a new buffer for every part.
+// The total size of buffers in use is limited by the memory budget of transferSched.
+type partBufferPool struct {
+	lock  sync.Mutex
+	pools map[int]*sync.Pool
+}
+
+func newPartBufferPool() *partBufferPool {
+	return &partBufferPool{pools: map[int]*sync.Pool{}}
+}
+
+// Get a buffer whose length is size
+func (p *partBufferPool) get(size int) []byte {
+	buf := p.getPool(size).Get().(*[]byte)
+	return *buf
+}
+
+// Put the buffer back to pool, buf must be got from get and can't be used any more
+func (p *partBufferPool) put(buf []byte) {
+	buf = buf[:cap(buf)]
+	p.getPool(len(buf)).Put(&buf)
+}
+
+func (p *partBufferPool) getPool(size int) *sync.Pool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	pool, ok := p.pools[size]
+	if !ok {
+		pool = &sync.Pool{
+			New: func() interface{} {
+				buf := make([]byte, size)
+				return &buf
+			},
+		}
+		p.pools[size] = pool
+	}
+	return pool
+}
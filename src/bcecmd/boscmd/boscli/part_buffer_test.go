@@ -0,0 +1,105 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"utils/util"
+)
+
+func TestPartBufferPool(t *testing.T) {
+	pool := newPartBufferPool()
+
+	buf := pool.get(16)
+	util.ExpectEqual("part_buffer get I", 1, t.Errorf, 16, len(buf))
+	pool.put(buf[:5])
+
+	buf = pool.get(16)
+	util.ExpectEqual("part_buffer get II", 1, t.Errorf, 16, len(buf))
+	buf = pool.get(32)
+	util.ExpectEqual("part_buffer get III", 1, t.Errorf, 32, len(buf))
+}
+
+// fake client which records the body of uploaded parts
+type fakePartUploader struct {
+	bosClientInterface
+	bodies map[int]string
+}
+
+func (f *fakePartUploader) UploadPartFromReader(bucket, object, uploadId string, partNumber int,
+	content io.ReadSeeker, input *s3.UploadPartInput) (string, error) {
+	body, err := ioutil.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	f.bodies[partNumber] = string(body)
+	return "etag", nil
+}
+
+type uploadPartFromFileType struct {
+	partNumber int64
+	offset     int64
+	size       int64
+	buffered   bool
+	body       string
+}
+
+func TestUploadPartFromFile(t *testing.T) {
+	fd, err := ioutil.TempFile("", "bcecmd.part.")
+	if err != nil {
+		t.Errorf("create temp file failed, error: %s", err)
+		return
+	}
+	defer os.Remove(fd.Name())
+	defer fd.Close()
+	if _, err := fd.WriteString("0123456789abcdef"); err != nil {
+		t.Errorf("write temp file failed, error: %s", err)
+		return
+	}
+
+	testCases := []uploadPartFromFileType{
+		//1
+		uploadPartFromFileType{partNumber: 1, offset: 0, size: 6, buffered: true,
+			body: "012345"},
+		//2 last part is smaller than buffer
+		uploadPartFromFileType{partNumber: 3, offset: 12, size: 4, buffered: true,
+			body: "cdef"},
+		//3 streamed
+		uploadPartFromFileType{partNumber: 2, offset: 6, size: 6, buffered: false,
+			body: "6789ab"},
+	}
+
+	client := &fakePartUploader{bodies: map[int]string{}}
+	for i, tCase := range testCases {
+		etag, err := uploadPartFromFile(client, fd, "bucket", "object", "id", tCase.partNumber,
+			tCase.offset, tCase.size, 6, tCase.buffered)
+		util.ExpectEqual("uploadPartFromFile I", i+1, t.Errorf, nil, err)
+		util.ExpectEqual("uploadPartFromFile II", i+1, t.Errorf, "etag", etag)
+		util.ExpectEqual("uploadPartFromFile III", i+1, t.Errorf, tCase.body,
+			client.bodies[int(tCase.partNumber)])
+	}
+
+	// read beyond the end of file
+	_, err = uploadPartFromFile(client, fd, "bucket", "object", "id", 4, 14, 6, 6, true)
+	util.ExpectEqual("uploadPartFromFile IV", 1, t.Errorf, true, err != nil)
+}
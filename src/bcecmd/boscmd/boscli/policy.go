@@ -0,0 +1,225 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the syntax check and templates of bucket policy.
+
+package boscli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	POLICY_VERSION               = "2012-10-17"
+	POLICY_TEMPLATE_READ_ONLY    = "read-only"
+	POLICY_TEMPLATE_CROSSACCOUNT = "cross-account"
+)
+
+// versions of policy language
+var policyVersions = []string{"2012-10-17", "2008-10-17"}
+
+type policyStatement struct {
+	Sid       string      `json:"Sid"`
+	Effect    string      `json:"Effect"`
+	Principal interface{} `json:"Principal"`
+	Action    []string    `json:"Action"`
+	Resource  []string    `json:"Resource"`
+}
+
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+// Generate a policy of template name for bucket, <bucket> is used when bucket is empty.
+func policyTemplate(name, bucketName string) (string, error) {
+	if bucketName == "" {
+		bucketName = "<bucket>"
+	}
+	bucketArn := "arn:aws:s3:::" + bucketName
+	var policy policyDocument
+
+	switch name {
+	case POLICY_TEMPLATE_READ_ONLY:
+		// anyone can list bucket and read objects
+		policy = policyDocument{
+			Version: POLICY_VERSION,
+			Statement: []policyStatement{
+				policyStatement{
+					Sid:       "PublicReadOnly",
+					Effect:    "Allow",
+					Principal: "*",
+					Action:    []string{"s3:GetObject", "s3:ListBucket"},
+					Resource:  []string{bucketArn, bucketArn + "/*"},
+				},
+			},
+		}
+	case POLICY_TEMPLATE_CROSSACCOUNT:
+		// another account can read and write objects
+		policy = policyDocument{
+			Version: POLICY_VERSION,
+			Statement: []policyStatement{
+				policyStatement{
+					Sid:       "CrossAccountReadWrite",
+					Effect:    "Allow",
+					Principal: map[string][]string{"AWS": {"arn:aws:iam::<account-id>:root"}},
+					Action: []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject",
+						"s3:ListBucket"},
+					Resource: []string{bucketArn, bucketArn + "/*"},
+				},
+			},
+		}
+	default:
+		return "", fmt.Errorf("unknown policy template '%s'", name)
+	}
+
+	return marshalJsonIndent(policy)
+}
+
+// Check the syntax of bucket policy locally. The value of Action, Resource and Principal can
+// be a string or a list, as the policy language allows.
+func checkBucketPolicy(policy []byte) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(policy, &doc); err != nil {
+		return fmt.Errorf("policy is not a json object: %s", err)
+	}
+	for key := range doc {
+		if key != "Version" && key != "Id" && key != "Statement" {
+			return fmt.Errorf("unknown field '%s' of policy", key)
+		}
+	}
+	if version, ok := doc["Version"]; ok {
+		if val, isStr := version.(string); !isStr || !isStringIn(val, policyVersions) {
+			return fmt.Errorf("Version of policy must be one of %v", policyVersions)
+		}
+	}
+
+	var statements []interface{}
+	switch val := doc["Statement"].(type) {
+	case []interface{}:
+		statements = val
+	case map[string]interface{}:
+		statements = []interface{}{val}
+	}
+	if len(statements) == 0 {
+		return fmt.Errorf("policy must have at least one statement")
+	}
+	for i, item := range statements {
+		statement, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("statement %d is not a json object", i+1)
+		}
+		if err := checkPolicyStatement(statement); err != nil {
+			return fmt.Errorf("statement %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+// check a statement of bucket policy
+func checkPolicyStatement(statement map[string]interface{}) error {
+	known := []string{"Sid", "Effect", "Principal", "NotPrincipal", "Action", "NotAction",
+		"Resource", "NotResource", "Condition"}
+	for key := range statement {
+		if !isStringIn(key, known) {
+			return fmt.Errorf("unknown field '%s'", key)
+		}
+	}
+	if effect, _ := statement["Effect"].(string); effect != "Allow" && effect != "Deny" {
+		return fmt.Errorf("Effect must be 'Allow' or 'Deny'")
+	}
+
+	// exactly one of each pair is required
+	pairs := [][2]string{{"Principal", "NotPrincipal"}, {"Action", "NotAction"},
+		{"Resource", "NotResource"}}
+	for _, pair := range pairs {
+		val, ok := statement[pair[0]]
+		notVal, notOk := statement[pair[1]]
+		if ok == notOk {
+			return fmt.Errorf("one of %s and %s is required", pair[0], pair[1])
+		} else if notOk {
+			val = notVal
+		}
+		if pair[0] == "Principal" {
+			if err := checkPolicyPrincipal(val); err != nil {
+				return err
+			}
+			continue
+		}
+		values, err := policyStringList(val)
+		if err != nil {
+			return fmt.Errorf("%s %s", pair[0], err)
+		}
+		if pair[0] != "Action" {
+			continue
+		}
+		for _, action := range values {
+			if action != "*" && !strings.HasPrefix(action, "s3:") {
+				return fmt.Errorf("action '%s' must start with 's3:'", action)
+			}
+		}
+	}
+
+	if condition, ok := statement["Condition"]; ok {
+		if _, isMap := condition.(map[string]interface{}); !isMap {
+			return fmt.Errorf("Condition must be a json object")
+		}
+	}
+	return nil
+}
+
+// principal is "*" or a json object whose values are string or list of string
+func checkPolicyPrincipal(principal interface{}) error {
+	switch val := principal.(type) {
+	case string:
+		if val != "*" {
+			return fmt.Errorf("Principal must be \"*\" or a json object")
+		}
+		return nil
+	case map[string]interface{}:
+		if len(val) == 0 {
+			return fmt.Errorf("Principal can't be empty")
+		}
+		for key, ids := range val {
+			if _, err := policyStringList(ids); err != nil {
+				return fmt.Errorf("Principal %s %s", key, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("Principal must be \"*\" or a json object")
+}
+
+// value of policy which is a non-empty string or a non-empty list of non-empty strings
+func policyStringList(val interface{}) ([]string, error) {
+	switch v := val.(type) {
+	case string:
+		if v != "" {
+			return []string{v}, nil
+		}
+	case []interface{}:
+		ret := make([]string, 0, len(v))
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok || str == "" {
+				return nil, fmt.Errorf("must be a non-empty string or a list of them")
+			}
+			ret = append(ret, str)
+		}
+		if len(ret) > 0 {
+			return ret, nil
+		}
+	}
+	return nil, fmt.Errorf("must be a non-empty string or a list of them")
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"utils/util"
+)
+
+func TestPolicyTemplate(t *testing.T) {
+	for i, name := range []string{POLICY_TEMPLATE_READ_ONLY, POLICY_TEMPLATE_CROSSACCOUNT} {
+		policy, err := policyTemplate(name, "bucket1")
+		util.ExpectEqual("policy template", i+1, t.Errorf, nil, err)
+		util.ExpectEqual("policy template", i+1, t.Errorf, true,
+			strings.Contains(policy, "arn:aws:s3:::bucket1/*"))
+		util.ExpectEqual("policy template", i+1, t.Errorf, false,
+			strings.Contains(policy, "\\u003c"))
+		// templates pass the syntax check
+		util.ExpectEqual("policy template", i+1, t.Errorf, nil,
+			checkBucketPolicy([]byte(policy)))
+	}
+	_, err := policyTemplate("write-only", "")
+	util.ExpectEqual("policy template", 3, t.Errorf, true, err != nil)
+}
+
+type checkBucketPolicyType struct {
+	policy string
+	isSuc  bool
+}
+
+func TestCheckBucketPolicy(t *testing.T) {
+	testCases := []checkBucketPolicyType{
+		//1 single statement and string values
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Deny", "Principal": "*",
+			"Action": "s3:*", "Resource": "arn:aws:s3:::b/*"}}`, isSuc: true},
+		//2 not principal, not action and condition
+		checkBucketPolicyType{policy: `{"Version": "2008-10-17", "Statement": [{
+			"Effect": "Allow", "NotPrincipal": {"AWS": "arn:aws:iam::1:root"},
+			"NotAction": ["s3:DeleteObject"], "Resource": ["arn:aws:s3:::b/*"],
+			"Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}}]}`, isSuc: true},
+		//3 not json
+		checkBucketPolicyType{policy: `Statement`},
+		//4 no statement
+		checkBucketPolicyType{policy: `{"Statement": []}`},
+		//5 invalid version
+		checkBucketPolicyType{policy: `{"Version": "2020-01-01", "Statement": {
+			"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": "*"}}`},
+		//6 invalid effect
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "allow", "Principal": "*",
+			"Action": "s3:*", "Resource": "*"}}`},
+		//7 no principal
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Allow",
+			"Action": "s3:*", "Resource": "*"}}`},
+		//8 both action and not action
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Allow", "Principal": "*",
+			"Action": "s3:*", "NotAction": "s3:GetObject", "Resource": "*"}}`},
+		//9 action of other service
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Allow", "Principal": "*",
+			"Action": "iam:*", "Resource": "*"}}`},
+		//10 empty resource
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Allow", "Principal": "*",
+			"Action": "s3:*", "Resource": []}}`},
+		//11 unknown field
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Allow", "Principal": "*",
+			"Actions": "s3:*", "Resource": "*"}}`},
+		//12 invalid principal
+		checkBucketPolicyType{policy: `{"Statement": {"Effect": "Allow", "Principal": "all",
+			"Action": "s3:*", "Resource": "*"}}`},
+	}
+	for i, tCase := range testCases {
+		err := checkBucketPolicy([]byte(tCase.policy))
+		util.ExpectEqual("check bucket policy", i+1, t.Errorf, tCase.isSuc, err == nil)
+	}
+}
@@ -16,16 +16,16 @@ package boscli
 
 // Interface for bos cli handler
 type handlerInterface interface {
-	multiDeleteDir(bosClientInterface, string, string) (int, error)
+	multiDeleteDir(bosClientInterface, *bosFilter, string, string) (int, error)
 	multiDeleteObjectsWithRetry(bosClientInterface, []string, string) ([]DeleteObjectResult,
 		error)
 	utilDeleteObject(bosClientInterface, string, string) error
 	utilCopyObject(bosClientInterface, bosClientInterface, string, string, string, string, string,
-		int64, int64, int64, bool) error
-	utilDownloadObject(bosClientInterface, string, string, string, string, bool, int64, int64, int64,
-		bool) error
-	utilUploadFile(bosClientInterface, string, string, string, string, string, int64, int64,
-		int64, bool) error
+		string, int64, int64, int64, bool) error
+	utilDownloadObject(bosClientInterface, string, string, string, string, string, bool, int64,
+		int64, int64, bool) error
+	utilUploadFile(bosClientInterface, string, string, string, string, string, string, int64,
+		int64, int64, bool) error
 	utilDeleteLocalFile(string) error
 	doesBucketExist(bosClientInterface, string) (bool, error)
 	CopySuperFile(bosClientInterface, bosClientInterface, string, string, string, string,
@@ -40,9 +40,11 @@ type fileDetail struct {
 	realPath     string // local file, real path of symbolic link
 	storageClass string // bos object
 	crc32        string
-	size         int64 // both
-	mtime        int64 // both, last Modified time
-	gtime        int64 // both, the time of get info of this object
+	etag         string             // bos object
+	metadata     map[string]*string // bos object and the file to upload, user metadata
+	size         int64              // both
+	mtime        int64              // both, last Modified time
+	gtime        int64              // both, the time of get info of this object
 	isDir        bool
 	err          error // both
 }
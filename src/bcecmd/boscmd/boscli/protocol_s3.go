@@ -14,6 +14,10 @@
 
 package boscli
 
+import (
+	"io"
+)
+
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -81,6 +85,8 @@ func getErrorMsg(err error) string {
 
 // Interface for wrap go sdk
 type bosClientInterface interface {
+	// the name of remote which the client belongs to, empty for the default remote
+	RemoteName() string
 	HeadBucket(bucket string) error
 	ListBuckets() (*s3.ListBucketsOutput, error)
 	ListObjects(string, string, string, string, int) (*s3.ListObjectsOutput, error)
@@ -90,26 +96,63 @@ type bosClientInterface interface {
 	BasicGeneratePresignedUrl(string, string, int) string
 	DeleteMultipleObjectsFromKeyList(string, []string) (*DeleteMultipleObjectsResult, error)
 	DeleteObject(string, string) error
+	DeleteObjectVersion(bucket, object, versionId string) error
+	ListObjectVersions(bucket, prefix, delimiter, keyMarker, versionIdMarker string,
+		maxKeys int) (*s3.ListObjectVersionsOutput, error)
 	GetObjectMeta(string, string) (*s3.HeadObjectOutput, error)
+	// the meta of the destination object of cp and sync
+	GetDestinationObjectMeta(string, string) (*s3.HeadObjectOutput, error)
 	CopyObject(bucket, object, srcBucket, srcObject, storageClass string,
 	) (*s3.CopyObjectOutput, error)
-	BasicGetObjectToFile(string, string, string) error
-	PutObjectFromFile(string, string, string, string) (string, error)
+	// the user metadata of object is returned
+	BasicGetObjectToFile(bucket, object, localPath string) (map[string]*string, error)
+	PutObjectFromFile(bucket, object, fileName, storageClass string,
+		meta map[string]*string) (string, error)
 	PutBucketLifecycleFromString(string, string) error
-	GetBucketLifecycle(bucket string) (*s3.GetBucketLifecycleOutput, error)
+	GetBucketLifecycle(bucket string) (*s3.GetBucketLifecycleConfigurationOutput, error)
 	DeleteBucketLifecycle(string) error
+	PutBucketLogging(bucket, targetBucket, targetPrefix string) error
+	GetBucketLogging(bucket string) (*s3.GetBucketLoggingOutput, error)
+	DeleteBucketLogging(bucket string) error
+	PutBucketPolicy(bucket, policy string) error
+	GetBucketPolicy(bucket string) (string, error)
+	DeleteBucketPolicy(bucket string) error
+	PutBucketCors(bucket string, config *s3.CORSConfiguration) error
+	GetBucketCors(bucket string) (*s3.GetBucketCorsOutput, error)
+	DeleteBucketCors(bucket string) error
+	PutBucketTagging(bucket string, tagSet []*s3.Tag) error
+	GetBucketTagging(bucket string) (*s3.GetBucketTaggingOutput, error)
+	DeleteBucketTagging(bucket string) error
+	PutObjectTagging(bucket, object string, tagSet []*s3.Tag) error
+	GetObjectTagging(bucket, object string) (*s3.GetObjectTaggingOutput, error)
+	PutBucketVersioning(bucket, status string) error
+	GetBucketVersioning(bucket string) (*s3.GetBucketVersioningOutput, error)
+	PutBucketEncryption(bucket string, config *s3.ServerSideEncryptionConfiguration) error
+	GetBucketEncryption(bucket string) (*s3.GetBucketEncryptionOutput, error)
 	PutBucketStorageclass(string, string) error
 	GetBucketStorageclass(string) (string, error)
 	PutBucketAclFromCanned(string, string) error
 	PutBucketAclFromString(string, string) error
 	GetBucketAcl(string) (*s3.GetBucketAclOutput, error)
+	PutObjectAclFromCanned(bucket, object, cannedAcl string) error
+	PutObjectAclFromString(bucket, object, acl string) error
+	GetObjectAcl(bucket, object string) (*s3.GetObjectAclOutput, error)
 	UploadPartCopy(string, string, string, string, string, string,
 		int64) (*s3.CopyPartResult, error)
 	UploadPartFromBytes(bucket, object, uploadId string, partNumber int, content []byte,
 		input *s3.UploadPartInput) (string, error)
-	InitiateMultipartUpload(string, string, string, string) (string, error)
+	UploadPartFromReader(bucket, object, uploadId string, partNumber int, content io.ReadSeeker,
+		input *s3.UploadPartInput) (string, error)
+	InitiateMultipartUpload(bucket, object, contentType, storageClass string,
+		meta map[string]*string) (string, error)
 	AbortMultipartUpload(bucket, object, uploadId string) error
+	ListParts(bucket, object, uploadId string, partNumberMarker int64,
+		maxParts int) (*s3.ListPartsOutput, error)
+	ListMultipartUploads(bucket, prefix, keyMarker, uploadIdMarker string,
+		maxUploads int) (*s3.ListMultipartUploadsOutput, error)
 	CompleteMultipartUploadFromStruct(string, string, string,
 		*s3.CompletedMultipartUpload) (*s3.CompleteMultipartUploadOutput, error)
 	GetObject(string, string, map[string]string, ...int64) (*s3.GetObjectOutput, error)
+	GetObjectIfMatch(bucket, object, etag string, rangeStart,
+		rangeEnd int64) (*s3.GetObjectOutput, error)
 }
@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 import (
@@ -28,17 +29,32 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-import (
-	"utils/util"
+const (
+	PARALLEL_DELETE_NUM             = 50
+	EACH_ROUTHINE_MIN_OBJECTS       = 10
+	MAX_DELETE_OBJECTS_EACH_REQUEST = 1000 // the limit of DeleteObjects
+	PARALLEL_DELETE_REQUEST_NUM     = 10
 )
 
-const (
-	PARALLEL_DELETE_NUM       = 50
-	EACH_ROUTHINE_MIN_OBJECTS = 10
+var (
+	// error codes returned by the backends which don't support DeleteObjects
+	multiDeleteNotSupportedCodes = map[string]bool{
+		"NotImplemented":   true,
+		"MethodNotAllowed": true,
+	}
 )
 
 type s3ClientWrapper struct {
-	s3Client *s3.S3
+	s3Client   *s3.S3
+	remoteName string // empty for the default remote
+
+	// set to 1 when the backend don't support DeleteObjects, then delete objects one by one
+	multiDeleteNotSupported int32
+}
+
+// RemoteName - the name of remote which the client belongs to
+func (b *s3ClientWrapper) RemoteName() string {
+	return b.remoteName
 }
 
 // Wrapper head bucket
@@ -126,7 +142,25 @@ func getObjectNumOfEachRoutine(total, routineNum, minNum int) int {
 	return each + 1
 }
 
+// generate the result of a failed deletion
+func newDeleteObjectResult(key string, err error) DeleteObjectResult {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return DeleteObjectResult{
+			Key:     key,
+			Code:    awsErr.Code(),
+			Message: awsErr.Message(),
+		}
+	}
+	return DeleteObjectResult{
+		Key:     key,
+		Code:    "None",
+		Message: err.Error(),
+	}
+}
+
 // Wrapper DeleteMultipleObjectsFromKeyList - delete a list of objects with given key string array
+// Keys are deleted by DeleteObjects in batches of 1000, and batches are processed in parallel.
+// RETURN: io.EOF when all objects have been deleted, otherwise the failed objects.
 func (b *s3ClientWrapper) DeleteMultipleObjectsFromKeyList(bucket string,
 	keyList []string) (*DeleteMultipleObjectsResult, error) {
 
@@ -140,79 +174,160 @@ func (b *s3ClientWrapper) DeleteMultipleObjectsFromKeyList(bucket string,
 		return nil, io.EOF
 	}
 
-	// init channel
-	syncOpPool := make(chan int, PARALLEL_DELETE_NUM)
-	executeResultChan := make(chan []DeleteObjectResult, PARALLEL_DELETE_NUM)
+	// The keys of batches which are not deleted as backend don't support DeleteObjects, they
+	// are deleted one by one after all batches finish, so that there are at most
+	// PARALLEL_DELETE_NUM single delete requests at a time.
+	var notDeletedKeys []string
+	if atomic.LoadInt32(&b.multiDeleteNotSupported) == 1 {
+		notDeletedKeys = keyList
+	} else {
+		batchNum := (objectNum + MAX_DELETE_OBJECTS_EACH_REQUEST - 1) /
+			MAX_DELETE_OBJECTS_EACH_REQUEST
+
+		// init channel
+		syncOpPool := make(chan int, PARALLEL_DELETE_REQUEST_NUM)
+		executeResultChan := make(chan []DeleteObjectResult, batchNum)
+		notDeletedChan := make(chan []string, batchNum)
+
+		for start := 0; start < objectNum; start += MAX_DELETE_OBJECTS_EACH_REQUEST {
+			end := start + MAX_DELETE_OBJECTS_EACH_REQUEST
+			if end > objectNum {
+				end = objectNum
+			}
+			syncOpPool <- 1
+			// the previous batches may find that DeleteObjects isn't supported
+			if atomic.LoadInt32(&b.multiDeleteNotSupported) == 1 {
+				<-syncOpPool
+				notDeletedChan <- keyList[start:end]
+				continue
+			}
+			opSync.Add(1)
+			go func(batch []string) {
+				defer func() {
+					opSync.Done()
+					<-syncOpPool
+				}()
+				if fails, ok := b.deleteObjectsBatch(bucket, batch); ok {
+					executeResultChan <- fails
+				} else {
+					notDeletedChan <- batch
+				}
+			}(keyList[start:end])
+		}
+
+		// waiting for all delete operation finish
+		opSync.Wait()
+		close(executeResultChan)
+		close(notDeletedChan)
+		for ret := range executeResultChan {
+			unDelLists = append(unDelLists, ret...)
+		}
+		for batch := range notDeletedChan {
+			notDeletedKeys = append(notDeletedKeys, batch...)
+		}
+	}
+	if len(notDeletedKeys) > 0 {
+		unDelLists = append(unDelLists, b.deleteObjectsOneByOne(bucket, notDeletedKeys)...)
+	}
+
+	if len(unDelLists) == 0 {
+		return nil, io.EOF
+	}
+	return &DeleteMultipleObjectsResult{
+		Errors: unDelLists,
+	}, nil
+}
+
+// Delete a batch of objects by DeleteObjects, return the objects failed to delete.
+// Return false when backend don't support DeleteObjects, and then no object is deleted.
+func (b *s3ClientWrapper) deleteObjectsBatch(bucket string,
+	keyList []string) ([]DeleteObjectResult, bool) {
 
-	// this function is used to execute sync operation
-	cpOpFunc := func(bucket string, keyList []string, start, end int,
-		retChan chan []DeleteObjectResult, wg *sync.WaitGroup) {
+	objects := make([]*s3.ObjectIdentifier, len(keyList))
+	for i, key := range keyList {
+		objects[i] = &s3.ObjectIdentifier{
+			Key: aws.String(key),
+		}
+	}
+	input := &s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+	}
+
+	output, err := b.s3Client.DeleteObjects(input)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && multiDeleteNotSupportedCodes[awsErr.Code()] {
+			atomic.StoreInt32(&b.multiDeleteNotSupported, 1)
+			return nil, false
+		}
+		// the whole batch failed
+		fails := make([]DeleteObjectResult, len(keyList))
+		for i, key := range keyList {
+			fails[i] = newDeleteObjectResult(key, err)
+		}
+		return fails, true
+	}
+
+	fails := make([]DeleteObjectResult, 0, len(output.Errors))
+	for _, deleteErr := range output.Errors {
+		fails = append(fails, DeleteObjectResult{
+			Key:     aws.StringValue(deleteErr.Key),
+			Code:    aws.StringValue(deleteErr.Code),
+			Message: aws.StringValue(deleteErr.Message),
+		})
+	}
+	return fails, true
+}
+
+// delete objects by single DeleteObject requests, which are sent in parallel
+func (b *s3ClientWrapper) deleteObjectsOneByOne(bucket string,
+	keyList []string) []DeleteObjectResult {
+
+	var (
+		unDelLists []DeleteObjectResult
+		opSync     sync.WaitGroup
+	)
+
+	objectNum := len(keyList)
+	each := getObjectNumOfEachRoutine(objectNum, PARALLEL_DELETE_NUM, EACH_ROUTHINE_MIN_OBJECTS)
+	routineNum := (objectNum + each - 1) / each
+	executeResultChan := make(chan []DeleteObjectResult, routineNum)
+
+	// this function is used to delete keyList[start:end]
+	delOpFunc := func(start, end int) {
 		var (
 			fails []DeleteObjectResult
 		)
 
-		defer func() {
-			wg.Done()
-			<-syncOpPool
-		}()
+		defer opSync.Done()
 
-		retry := 0
-		for i := start; i < end && retry < 3; i++ {
-			input := &s3.DeleteObjectInput{
-				Bucket: aws.String(bucket),
-				Key:    aws.String(keyList[i]),
-			}
-			_, err := b.s3Client.DeleteObject(input)
-			if err == nil {
-				continue
-			}
-			if awsErr, ok := err.(awserr.Error); ok {
-				retry++
-				fails = append(fails, DeleteObjectResult{
-					Key:     keyList[i],
-					Code:    awsErr.Code(),
-					Message: awsErr.Message(),
-				})
-			} else {
-				retry++
-				fails = append(fails, DeleteObjectResult{
-					Key:     keyList[i],
-					Code:    "None",
-					Message: err.Error(),
-				})
+		for i := start; i < end; i++ {
+			if err := b.DeleteObject(bucket, keyList[i]); err != nil {
+				fails = append(fails, newDeleteObjectResult(keyList[i], err))
 			}
 		}
-		retChan <- fails
+		executeResultChan <- fails
 	}
 
-	// upload from file
-	each := getObjectNumOfEachRoutine(objectNum, PARALLEL_DELETE_NUM, EACH_ROUTHINE_MIN_OBJECTS)
-	run_routine := 0
 	for start := 0; start < objectNum; start += each {
 		end := start + each
 		if end > objectNum {
 			end = objectNum
 		}
 		opSync.Add(1)
-		go cpOpFunc(bucket, keyList, start, end, executeResultChan, &opSync)
-		run_routine++
-	}
-
-	for i := 0; i < run_routine; i++ {
-		ret := <-executeResultChan
-		if len(ret) != 0 {
-			unDelLists = append(unDelLists, ret...)
-		}
+		go delOpFunc(start, end)
 	}
 
-	// waiting for all sync operation finish
+	// waiting for all delete operation finish
 	opSync.Wait()
-	if len(unDelLists) == 0 {
-		return nil, io.EOF
+	close(executeResultChan)
+	for ret := range executeResultChan {
+		unDelLists = append(unDelLists, ret...)
 	}
-	return &DeleteMultipleObjectsResult{
-		Errors: unDelLists,
-	}, fmt.Errorf("failed delete some objects")
+	return unDelLists
 }
 
 // Wrapper DeleteObject - delete the given object
@@ -227,10 +342,25 @@ func (b *s3ClientWrapper) DeleteObject(bucket, object string) error {
 
 // Wrapper GetObjectMeta
 func (b *s3ClientWrapper) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput, error) {
+	input := &s3.HeadObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(object),
+		VersionId: versionIdOf(bucket, object),
+	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.readKeyArgs()
+	return b.s3Client.HeadObject(input)
+}
+
+// Wrapper GetObjectMeta of the destination object of cp and sync, which is written with the
+// SSE-C key, while the source object of copy is read with the copy source key.
+func (b *s3ClientWrapper) GetDestinationObjectMeta(bucket, object string) (*s3.HeadObjectOutput,
+	error) {
+
 	input := &s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(object),
 	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.customerKeyArgs()
 	return b.s3Client.HeadObject(input)
 }
 
@@ -239,45 +369,59 @@ func (b *s3ClientWrapper) CopyObject(bucket, object, srcBucket, srcObject, stora
 ) (*s3.CopyObjectOutput, error) {
 	input := &s3.CopyObjectInput{
 		Bucket:     aws.String(bucket),
-		CopySource: aws.String(srcBucket + util.BOS_PATH_SEPARATOR + srcObject),
+		CopySource: aws.String(copySourceOf(srcBucket, srcObject)),
 		Key:        aws.String(object),
 	}
 	if storageClass != "" {
 		input.StorageClass = aws.String(storageClass)
 	}
+	if uploadAcl != "" {
+		input.SetACL(uploadAcl)
+	}
+	if uploadTagging != "" {
+		input.SetTagging(uploadTagging)
+		input.SetTaggingDirective(s3.TaggingDirectiveReplace)
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = transferSse.encryptionArgs()
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.customerKeyArgs()
+	input.CopySourceSSECustomerAlgorithm, input.CopySourceSSECustomerKey =
+		transferSse.copySourceKeyArgs()
 	return b.s3Client.CopyObject(input)
 }
 
-// Wrapper of BasiGetObjectToFile
-func (b *s3ClientWrapper) BasicGetObjectToFile(bucket, object, localPath string) error {
+// Wrapper of BasiGetObjectToFile, return the user metadata of object
+func (b *s3ClientWrapper) BasicGetObjectToFile(bucket, object, localPath string) (
+	map[string]*string, error) {
 	input := &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(object),
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(object),
+		VersionId: versionIdOf(bucket, object),
 	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.readKeyArgs()
 	res, err := b.s3Client.GetObject(input)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer res.Body.Close()
 	file, fileErr := os.OpenFile(localPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
 	if fileErr != nil {
-		return fileErr
+		return nil, fileErr
 	}
 	defer file.Close()
 
 	written, writeErr := io.CopyN(file, res.Body, *res.ContentLength)
 	if writeErr != nil {
-		return writeErr
+		return nil, writeErr
 	}
 	if written != *res.ContentLength {
-		return fmt.Errorf("written content size does not match the response content")
+		return nil, fmt.Errorf("written content size does not match the response content")
 	}
-	return nil
+	return res.Metadata, nil
 }
 
-// Wrapper of PutObjectFromFile
-func (b *s3ClientWrapper) PutObjectFromFile(bucket, object, fileName, storageClass string) (string,
-	error) {
+// Wrapper of PutObjectFromFile, meta is the user metadata of object
+func (b *s3ClientWrapper) PutObjectFromFile(bucket, object, fileName, storageClass string,
+	meta map[string]*string) (string, error) {
 	file, err := os.Open(fileName)
 	if err != nil {
 		return "", err
@@ -285,10 +429,20 @@ func (b *s3ClientWrapper) PutObjectFromFile(bucket, object, fileName, storageCla
 	defer file.Close()
 
 	input := &s3.PutObjectInput{
-		Body:   file,
-		Bucket: aws.String(bucket),
-		Key:    aws.String(object),
+		Body:     file,
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(object),
+		Metadata: meta,
+	}
+	input.ContentEncoding = contentEncodingOf(input.Metadata)
+	if uploadAcl != "" {
+		input.SetACL(uploadAcl)
+	}
+	if uploadTagging != "" {
+		input.SetTagging(uploadTagging)
 	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = transferSse.encryptionArgs()
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.customerKeyArgs()
 	res, err := b.s3Client.PutObject(input)
 	if err != nil {
 		return "", err
@@ -306,9 +460,25 @@ func (b *s3ClientWrapper) PutBucketAclFromCanned(bucket, cannedAcl string) error
 	return err
 }
 
-// Wrapper of PutBucketAcl
+// Wrapper of PutBucketAcl, acl is in json, the owner of bucket is used when acl has no owner
 func (b *s3ClientWrapper) PutBucketAclFromString(bucket string, acl string) error {
-	return fmt.Errorf("not support !")
+	policy, err := parseAclConfig([]byte(acl))
+	if err != nil {
+		return err
+	}
+	if policy.Owner == nil {
+		ret, err := b.GetBucketAcl(bucket)
+		if err != nil {
+			return err
+		}
+		policy.Owner = ret.Owner
+	}
+	input := &s3.PutBucketAclInput{
+		Bucket:              aws.String(bucket),
+		AccessControlPolicy: policy,
+	}
+	_, err = b.s3Client.PutBucketAcl(input)
+	return err
 }
 
 // Wrapper of GetBucketAcl
@@ -319,20 +489,294 @@ func (b *s3ClientWrapper) GetBucketAcl(bucket string) (*s3.GetBucketAclOutput, e
 	return b.s3Client.GetBucketAcl(input)
 }
 
-// Wrapper of PutBucketLifecycleFromString
+// Wrapper of PutObjectAcl with canned ACL
+func (b *s3ClientWrapper) PutObjectAclFromCanned(bucket, object, cannedAcl string) error {
+	input := &s3.PutObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+		ACL:    aws.String(cannedAcl),
+	}
+	_, err := b.s3Client.PutObjectAcl(input)
+	return err
+}
+
+// Wrapper of PutObjectAcl, acl is in json, the owner of object is used when acl has no owner
+func (b *s3ClientWrapper) PutObjectAclFromString(bucket, object, acl string) error {
+	policy, err := parseAclConfig([]byte(acl))
+	if err != nil {
+		return err
+	}
+	if policy.Owner == nil {
+		ret, err := b.GetObjectAcl(bucket, object)
+		if err != nil {
+			return err
+		}
+		policy.Owner = ret.Owner
+	}
+	input := &s3.PutObjectAclInput{
+		Bucket:              aws.String(bucket),
+		Key:                 aws.String(object),
+		AccessControlPolicy: policy,
+	}
+	_, err = b.s3Client.PutObjectAcl(input)
+	return err
+}
+
+// Wrapper of GetObjectAcl
+func (b *s3ClientWrapper) GetObjectAcl(bucket, object string) (*s3.GetObjectAclOutput, error) {
+	input := &s3.GetObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	}
+	return b.s3Client.GetObjectAcl(input)
+}
+
+// Wrapper of PutBucketLifecycleFromString, lifecycle is in json
 func (b *s3ClientWrapper) PutBucketLifecycleFromString(bucket, lifecycle string) error {
-	return fmt.Errorf("not support !")
+	config, err := parseLifecycleConfig([]byte(lifecycle))
+	if err != nil {
+		return err
+	}
+	input := &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucket),
+		LifecycleConfiguration: config,
+	}
+	_, err = b.s3Client.PutBucketLifecycleConfiguration(input)
+	return err
 }
 
 // Wrapper of GetBucketLifecycle
-func (b *s3ClientWrapper) GetBucketLifecycle(bucket string) (*s3.GetBucketLifecycleOutput,
-	error) {
-	return nil, fmt.Errorf("not support !")
+func (b *s3ClientWrapper) GetBucketLifecycle(bucket string) (
+	*s3.GetBucketLifecycleConfigurationOutput, error) {
+
+	input := &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	}
+	return b.s3Client.GetBucketLifecycleConfiguration(input)
 }
 
 // Wrapper of DeleteBucketLifecycle
 func (b *s3ClientWrapper) DeleteBucketLifecycle(bucket string) error {
-	return fmt.Errorf("not support !")
+	input := &s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(bucket),
+	}
+	_, err := b.s3Client.DeleteBucketLifecycle(input)
+	return err
+}
+
+// Wrapper of PutBucketLogging, the access logs of bucket are put to targetBucket with
+// targetPrefix
+func (b *s3ClientWrapper) PutBucketLogging(bucket, targetBucket, targetPrefix string) error {
+	input := &s3.PutBucketLoggingInput{
+		Bucket: aws.String(bucket),
+		BucketLoggingStatus: &s3.BucketLoggingStatus{
+			LoggingEnabled: &s3.LoggingEnabled{
+				TargetBucket: aws.String(targetBucket),
+				TargetPrefix: aws.String(targetPrefix),
+			},
+		},
+	}
+	_, err := b.s3Client.PutBucketLogging(input)
+	return err
+}
+
+// Wrapper of GetBucketLogging
+func (b *s3ClientWrapper) GetBucketLogging(bucket string) (*s3.GetBucketLoggingOutput, error) {
+	input := &s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucket),
+	}
+	return b.s3Client.GetBucketLogging(input)
+}
+
+// Wrapper of DeleteBucketLogging, S3 disables logging by putting an empty logging status
+func (b *s3ClientWrapper) DeleteBucketLogging(bucket string) error {
+	input := &s3.PutBucketLoggingInput{
+		Bucket:              aws.String(bucket),
+		BucketLoggingStatus: &s3.BucketLoggingStatus{},
+	}
+	_, err := b.s3Client.PutBucketLogging(input)
+	return err
+}
+
+// Wrapper of PutBucketPolicy, policy is in json
+func (b *s3ClientWrapper) PutBucketPolicy(bucket, policy string) error {
+	input := &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(policy),
+	}
+	_, err := b.s3Client.PutBucketPolicy(input)
+	return err
+}
+
+// Wrapper of GetBucketPolicy
+func (b *s3ClientWrapper) GetBucketPolicy(bucket string) (string, error) {
+	input := &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	}
+	res, err := b.s3Client.GetBucketPolicy(input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(res.Policy), nil
+}
+
+// Wrapper of DeleteBucketPolicy
+func (b *s3ClientWrapper) DeleteBucketPolicy(bucket string) error {
+	input := &s3.DeleteBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	}
+	_, err := b.s3Client.DeleteBucketPolicy(input)
+	return err
+}
+
+// Wrapper of PutBucketCors
+func (b *s3ClientWrapper) PutBucketCors(bucket string, config *s3.CORSConfiguration) error {
+	input := &s3.PutBucketCorsInput{
+		Bucket:            aws.String(bucket),
+		CORSConfiguration: config,
+	}
+	_, err := b.s3Client.PutBucketCors(input)
+	return err
+}
+
+// Wrapper of GetBucketCors
+func (b *s3ClientWrapper) GetBucketCors(bucket string) (*s3.GetBucketCorsOutput, error) {
+	input := &s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	}
+	return b.s3Client.GetBucketCors(input)
+}
+
+// Wrapper of DeleteBucketCors
+func (b *s3ClientWrapper) DeleteBucketCors(bucket string) error {
+	input := &s3.DeleteBucketCorsInput{
+		Bucket: aws.String(bucket),
+	}
+	_, err := b.s3Client.DeleteBucketCors(input)
+	return err
+}
+
+// Wrapper of PutBucketTagging
+func (b *s3ClientWrapper) PutBucketTagging(bucket string, tagSet []*s3.Tag) error {
+	input := &s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucket),
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	}
+	_, err := b.s3Client.PutBucketTagging(input)
+	return err
+}
+
+// Wrapper of GetBucketTagging
+func (b *s3ClientWrapper) GetBucketTagging(bucket string) (*s3.GetBucketTaggingOutput, error) {
+	input := &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	}
+	return b.s3Client.GetBucketTagging(input)
+}
+
+// Wrapper of DeleteBucketTagging
+func (b *s3ClientWrapper) DeleteBucketTagging(bucket string) error {
+	input := &s3.DeleteBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	}
+	_, err := b.s3Client.DeleteBucketTagging(input)
+	return err
+}
+
+// Wrapper of PutObjectTagging
+func (b *s3ClientWrapper) PutObjectTagging(bucket, object string, tagSet []*s3.Tag) error {
+	input := &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(object),
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	}
+	_, err := b.s3Client.PutObjectTagging(input)
+	return err
+}
+
+// Wrapper of GetObjectTagging
+func (b *s3ClientWrapper) GetObjectTagging(bucket, object string) (*s3.GetObjectTaggingOutput,
+	error) {
+	input := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(object),
+	}
+	return b.s3Client.GetObjectTagging(input)
+}
+
+// Wrapper of PutBucketVersioning, status is Enabled or Suspended
+func (b *s3ClientWrapper) PutBucketVersioning(bucket, status string) error {
+	input := &s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(status),
+		},
+	}
+	_, err := b.s3Client.PutBucketVersioning(input)
+	return err
+}
+
+// Wrapper of GetBucketVersioning
+func (b *s3ClientWrapper) GetBucketVersioning(bucket string) (*s3.GetBucketVersioningOutput,
+	error) {
+	input := &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	}
+	return b.s3Client.GetBucketVersioning(input)
+}
+
+// Wrapper of ListObjectVersions
+func (b *s3ClientWrapper) ListObjectVersions(bucket, prefix, delimiter, keyMarker,
+	versionIdMarker string, maxKeys int) (*s3.ListObjectVersionsOutput, error) {
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket:  aws.String(bucket),
+		MaxKeys: aws.Int64(int64(maxKeys)),
+	}
+	if prefix != "" {
+		input.SetPrefix(prefix)
+	}
+	if delimiter != "" {
+		input.SetDelimiter(delimiter)
+	}
+	if keyMarker != "" {
+		input.SetKeyMarker(keyMarker)
+	}
+	if versionIdMarker != "" {
+		input.SetVersionIdMarker(versionIdMarker)
+	}
+	return b.s3Client.ListObjectVersions(input)
+}
+
+// Wrapper of DeleteObject with version id, the version is deleted permanently
+func (b *s3ClientWrapper) DeleteObjectVersion(bucket, object, versionId string) error {
+	input := &s3.DeleteObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(object),
+		VersionId: aws.String(versionId),
+	}
+	_, err := b.s3Client.DeleteObject(input)
+	return err
+}
+
+// Wrapper of PutBucketEncryption
+func (b *s3ClientWrapper) PutBucketEncryption(bucket string,
+	config *s3.ServerSideEncryptionConfiguration) error {
+	input := &s3.PutBucketEncryptionInput{
+		Bucket:                            aws.String(bucket),
+		ServerSideEncryptionConfiguration: config,
+	}
+	_, err := b.s3Client.PutBucketEncryption(input)
+	return err
+}
+
+// Wrapper of GetBucketEncryption
+func (b *s3ClientWrapper) GetBucketEncryption(bucket string) (*s3.GetBucketEncryptionOutput,
+	error) {
+	input := &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	}
+	return b.s3Client.GetBucketEncryption(input)
 }
 
 // Wrapper of PutBucketStorageclass
@@ -351,12 +795,15 @@ func (b *s3ClientWrapper) UploadPartCopy(bucket, object, srcBucket, srcObject, u
 
 	input := &s3.UploadPartCopyInput{
 		Bucket:          aws.String(bucket),
-		CopySource:      aws.String(srcBucket + util.BOS_PATH_SEPARATOR + srcObject),
+		CopySource:      aws.String(copySourceOf(srcBucket, srcObject)),
 		CopySourceRange: aws.String(sourceRange),
 		Key:             aws.String(object),
 		PartNumber:      aws.Int64(partNumber),
 		UploadId:        aws.String(uploadId),
 	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.customerKeyArgs()
+	input.CopySourceSSECustomerAlgorithm, input.CopySourceSSECustomerKey =
+		transferSse.copySourceKeyArgs()
 
 	res, err := b.s3Client.UploadPartCopy(input)
 	if err != nil {
@@ -369,14 +816,24 @@ func (b *s3ClientWrapper) UploadPartCopy(bucket, object, srcBucket, srcObject, u
 func (b *s3ClientWrapper) UploadPartFromBytes(bucket, object, uploadId string, partNumber int,
 	content []byte, input *s3.UploadPartInput) (string, error) {
 
+	return b.UploadPartFromReader(bucket, object, uploadId, partNumber, bytes.NewReader(content),
+		input)
+}
+
+// Upload part from reader, the body is read from the current offset of content, and content
+// is seeked back when the request is retried.
+func (b *s3ClientWrapper) UploadPartFromReader(bucket, object, uploadId string, partNumber int,
+	content io.ReadSeeker, input *s3.UploadPartInput) (string, error) {
+
 	if input == nil {
 		input = &s3.UploadPartInput{}
 	}
-	input.SetBody(bytes.NewReader(content))
+	input.SetBody(content)
 	input.SetBucket(bucket)
 	input.SetKey(object)
 	input.SetPartNumber(int64(partNumber))
 	input.SetUploadId(uploadId)
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.customerKeyArgs()
 	res, err := b.s3Client.UploadPart(input)
 	if err != nil {
 		return "", err
@@ -384,20 +841,30 @@ func (b *s3ClientWrapper) UploadPartFromBytes(bucket, object, uploadId string, p
 	return *res.ETag, nil
 }
 
-// Wrapper of GetBucketStorageclass
+// Wrapper of InitiateMultipartUpload, meta is the user metadata of object
 func (b *s3ClientWrapper) InitiateMultipartUpload(bucket, object, contentType,
-	storageClass string) (string, error) {
+	storageClass string, meta map[string]*string) (string, error) {
 
 	input := &s3.CreateMultipartUploadInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(object),
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(object),
+		Metadata: meta,
 	}
+	input.ContentEncoding = contentEncodingOf(input.Metadata)
 	if contentType != "" {
 		input.SetContentType(contentType)
 	}
 	if storageClass != "" {
 		input.SetStorageClass(storageClass)
 	}
+	if uploadAcl != "" {
+		input.SetACL(uploadAcl)
+	}
+	if uploadTagging != "" {
+		input.SetTagging(uploadTagging)
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = transferSse.encryptionArgs()
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.customerKeyArgs()
 	res, err := b.s3Client.CreateMultipartUpload(input)
 	if err != nil {
 		return "", err
@@ -427,12 +894,47 @@ func (b *s3ClientWrapper) CompleteMultipartUploadFromStruct(bucket, object, uplo
 	return b.s3Client.CompleteMultipartUpload(input)
 }
 
+// Wrapper ListParts - list at most maxParts parts of upload whose part number is greater
+// than partNumberMarker
+func (b *s3ClientWrapper) ListParts(bucket, object, uploadId string, partNumberMarker int64,
+	maxParts int) (*s3.ListPartsOutput, error) {
+
+	input := &s3.ListPartsInput{
+		Bucket:           aws.String(bucket),
+		Key:              aws.String(object),
+		UploadId:         aws.String(uploadId),
+		PartNumberMarker: aws.Int64(partNumberMarker),
+		MaxParts:         aws.Int64(int64(maxParts)),
+	}
+	return b.s3Client.ListParts(input)
+}
+
+// Wrapper ListMultipartUploads - list at most maxUploads in-progress multipart uploads whose
+// key has prefix, starting after keyMarker and uploadIdMarker
+func (b *s3ClientWrapper) ListMultipartUploads(bucket, prefix, keyMarker, uploadIdMarker string,
+	maxUploads int) (*s3.ListMultipartUploadsOutput, error) {
+
+	input := &s3.ListMultipartUploadsInput{
+		Bucket:     aws.String(bucket),
+		Prefix:     aws.String(prefix),
+		MaxUploads: aws.Int64(int64(maxUploads)),
+	}
+	if keyMarker != "" {
+		input.SetKeyMarker(keyMarker)
+	}
+	if uploadIdMarker != "" {
+		input.SetUploadIdMarker(uploadIdMarker)
+	}
+	return b.s3Client.ListMultipartUploads(input)
+}
+
 func (b *s3ClientWrapper) GetObject(bucket, object string, responseHeaders map[string]string,
 	ranges ...int64) (*s3.GetObjectOutput, error) {
 
 	input := &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(object),
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(object),
+		VersionId: versionIdOf(bucket, object),
 	}
 
 	if len(ranges) != 0 {
@@ -444,5 +946,24 @@ func (b *s3ClientWrapper) GetObject(bucket, object string, responseHeaders map[s
 		}
 		input.SetRange(rangeStr)
 	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.readKeyArgs()
+	return b.s3Client.GetObject(input)
+}
+
+// Wrapper GetObjectIfMatch, get bytes [rangeStart, rangeEnd] of object only when its etag is
+// still etag, the request fails with PreconditionFailed otherwise. No condition when etag is empty.
+func (b *s3ClientWrapper) GetObjectIfMatch(bucket, object, etag string, rangeStart,
+	rangeEnd int64) (*s3.GetObjectOutput, error) {
+
+	input := &s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(object),
+		Range:     aws.String(fmt.Sprintf("bytes=%d-%d", rangeStart, rangeEnd)),
+		VersionId: versionIdOf(bucket, object),
+	}
+	if etag != "" {
+		input.SetIfMatch(etag)
+	}
+	input.SSECustomerAlgorithm, input.SSECustomerKey = transferSse.readKeyArgs()
 	return b.s3Client.GetObject(input)
 }
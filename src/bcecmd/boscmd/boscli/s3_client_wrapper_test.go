@@ -0,0 +1,225 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"utils/util"
+)
+
+// the body of DeleteObjects request
+type fakeDeleteRequest struct {
+	Quiet   bool     `xml:"Quiet"`
+	Objects []string `xml:"Object>Key"`
+}
+
+// A fake S3 server which supports DeleteObjects and DeleteObject, the objects in failKeys can't
+// be deleted. DeleteObjects returns notSupportedCode when it is not empty.
+type fakeDeleteServer struct {
+	failKeys         map[string]bool
+	notSupportedCode string
+
+	lock          sync.Mutex
+	batchSizes    []int  // the number of keys in each DeleteObjects request
+	batchQuiet    []bool // quiet of each DeleteObjects request
+	singleDeletes int    // the number of DeleteObject requests
+	running       int    // the number of running DeleteObject requests
+	maxRunning    int
+}
+
+func (s *fakeDeleteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["delete"]; ok && r.Method == http.MethodPost {
+		s.deleteObjects(w, r)
+	} else if r.Method == http.MethodDelete {
+		s.deleteObject(w, r)
+	} else {
+		writeFakeS3Error(w, http.StatusBadRequest, "InvalidRequest")
+	}
+}
+
+func (s *fakeDeleteServer) deleteObjects(w http.ResponseWriter, r *http.Request) {
+	switch s.notSupportedCode {
+	case "NotImplemented":
+		writeFakeS3Error(w, http.StatusNotImplemented, s.notSupportedCode)
+		return
+	case "MethodNotAllowed":
+		writeFakeS3Error(w, http.StatusMethodNotAllowed, s.notSupportedCode)
+		return
+	}
+
+	request := &fakeDeleteRequest{}
+	if err := xml.NewDecoder(r.Body).Decode(request); err != nil {
+		writeFakeS3Error(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	s.lock.Lock()
+	s.batchSizes = append(s.batchSizes, len(request.Objects))
+	s.batchQuiet = append(s.batchQuiet, request.Quiet)
+	s.lock.Unlock()
+
+	result := "<DeleteResult>"
+	for _, key := range request.Objects {
+		if s.failKeys[key] {
+			result += fmt.Sprintf("<Error><Key>%s</Key><Code>AccessDenied</Code>"+
+				"<Message>Access Denied</Message></Error>", key)
+		} else if !request.Quiet {
+			result += fmt.Sprintf("<Deleted><Key>%s</Key></Deleted>", key)
+		}
+	}
+	result += "</DeleteResult>"
+	io.WriteString(w, result)
+}
+
+func (s *fakeDeleteServer) deleteObject(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.singleDeletes++
+	s.running++
+	if s.running > s.maxRunning {
+		s.maxRunning = s.running
+	}
+	s.lock.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	s.lock.Lock()
+	s.running--
+	s.lock.Unlock()
+
+	// path style url: /bucket/key
+	key := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[1]
+	if s.failKeys[key] {
+		writeFakeS3Error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeFakeS3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+type deleteMultipleObjectsType struct {
+	keyNum           int
+	failKeys         []string
+	notSupportedCode string
+	batchSizes       []int
+	singleDeletes    int
+}
+
+func TestDeleteMultipleObjectsFromKeyList(t *testing.T) {
+	testCases := []deleteMultipleObjectsType{
+		//1 one batch
+		deleteMultipleObjectsType{keyNum: 10, batchSizes: []int{10}},
+		//2 at most 1000 keys in a batch
+		deleteMultipleObjectsType{keyNum: 2500, batchSizes: []int{500, 1000, 1000}},
+		//3 errors of keys
+		deleteMultipleObjectsType{
+			keyNum:     1500,
+			failKeys:   []string{"key-1", "key-1200"},
+			batchSizes: []int{500, 1000},
+		},
+		//4 fallback to delete objects one by one
+		deleteMultipleObjectsType{
+			keyNum:           2500,
+			failKeys:         []string{"key-3"},
+			notSupportedCode: "NotImplemented",
+			singleDeletes:    2500,
+		},
+		//5 fallback to delete objects one by one
+		deleteMultipleObjectsType{
+			keyNum:           30,
+			notSupportedCode: "MethodNotAllowed",
+			singleDeletes:    30,
+		},
+	}
+	for i, tCase := range testCases {
+		server := &fakeDeleteServer{
+			failKeys:         make(map[string]bool),
+			notSupportedCode: tCase.notSupportedCode,
+		}
+		for _, key := range tCase.failKeys {
+			server.failKeys[key] = true
+		}
+		ts := httptest.NewServer(server)
+		client, err := newBosClient("ak", "sk", "", ts.URL, "bj", false)
+		util.ExpectEqual("delete multiple objects", i+1, t.Errorf, nil, err)
+
+		keyList := make([]string, tCase.keyNum)
+		for j := range keyList {
+			keyList[j] = fmt.Sprintf("key-%d", j)
+		}
+		ret, err := client.DeleteMultipleObjectsFromKeyList("bucket", keyList)
+		ts.Close()
+
+		if len(tCase.failKeys) == 0 {
+			util.ExpectEqual("delete multiple objects", i+1, t.Errorf, io.EOF, err)
+		} else {
+			util.ExpectEqual("delete multiple objects", i+1, t.Errorf, nil, err)
+			var failKeys []string
+			for _, fail := range ret.Errors {
+				util.ExpectEqual("delete multiple objects", i+1, t.Errorf, "AccessDenied",
+					fail.Code)
+				failKeys = append(failKeys, fail.Key)
+			}
+			sort.Strings(failKeys)
+			util.ExpectEqual("delete multiple objects", i+1, t.Errorf, tCase.failKeys,
+				failKeys)
+		}
+
+		sort.Ints(server.batchSizes)
+		if tCase.batchSizes == nil {
+			util.ExpectEqual("delete multiple objects", i+1, t.Errorf, 0,
+				len(server.batchSizes))
+		} else {
+			util.ExpectEqual("delete multiple objects", i+1, t.Errorf, tCase.batchSizes,
+				server.batchSizes)
+		}
+		for _, quiet := range server.batchQuiet {
+			util.ExpectEqual("delete multiple objects", i+1, t.Errorf, true, quiet)
+		}
+		util.ExpectEqual("delete multiple objects", i+1, t.Errorf, tCase.singleDeletes,
+			server.singleDeletes)
+		util.ExpectEqual("delete multiple objects", i+1, t.Errorf, true,
+			server.maxRunning <= PARALLEL_DELETE_NUM)
+		util.ExpectEqual("delete multiple objects", i+1, t.Errorf,
+			tCase.notSupportedCode != "", client.multiDeleteNotSupported == 1)
+	}
+}
+
+func TestDeleteMultipleObjectsNotSupported(t *testing.T) {
+	server := &fakeDeleteServer{notSupportedCode: "NotImplemented"}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client, err := newBosClient("ak", "sk", "", ts.URL, "bj", false)
+	util.ExpectEqual("delete multiple objects", 1, t.Errorf, nil, err)
+
+	// DeleteObjects isn't sent again when it is known not supported
+	client.multiDeleteNotSupported = 1
+	_, err = client.DeleteMultipleObjectsFromKeyList("bucket", []string{"a", "b", "c"})
+	util.ExpectEqual("delete multiple objects", 1, t.Errorf, io.EOF, err)
+	util.ExpectEqual("delete multiple objects", 1, t.Errorf, 3, server.singleDeletes)
+	util.ExpectEqual("delete multiple objects", 1, t.Errorf, 0, len(server.batchSizes))
+}
@@ -0,0 +1,124 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the global transfer scheduler, which owns the budget of in-flight
+// HTTP requests and buffered bytes shared by all object level and part level transfers.
+
+package boscli
+
+import (
+	"fmt"
+	"sync"
+)
+
+import (
+	"bceconf"
+)
+
+// the scheduler used by all transfers, it is unlimited until SetTransferBudget is called
+var transferSched = newTransferScheduler(0, 0)
+
+// SetTransferBudget - set the max number of in-flight requests and the max bytes buffered by
+// them for all transfers of cp and sync. maxRequests 0 means no limit, and the multi upload max
+// memory in config file is used when maxMemory is 0.
+func (b *BosCli) SetTransferBudget(maxRequests int, maxMemory int64) {
+	if maxMemory == 0 {
+		maxMemoryMB, ok := bceconf.ServerConfigProvider.GetMultiUploadMaxMemory()
+		if !ok {
+			bcecliAbnormalExistCodeErr(BOSCLI_TRANSFER_BUDGET_IS_INVALID,
+				fmt.Errorf("There is no info about multi upload max memory found!"))
+		}
+		maxMemory = maxMemoryMB << 20
+	}
+	if err := transferSched.setBudget(maxRequests, maxMemory); err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_TRANSFER_BUDGET_IS_INVALID, err)
+	}
+}
+
+// transferScheduler limits the number of in-flight requests and the bytes buffered by them,
+// 0 means no limit.
+// A transfer must acquire budget just before sending a request and release it as soon as the
+// request finishes, and never wait for other transfers while holding budget, so that object
+// level and part level work can share the same budget without deadlock.
+type transferScheduler struct {
+	lock        sync.Mutex
+	cond        *sync.Cond
+	maxRequests int
+	maxMemory   int64
+	requests    int
+	memory      int64
+}
+
+func newTransferScheduler(maxRequests int, maxMemory int64) *transferScheduler {
+	s := &transferScheduler{
+		maxRequests: maxRequests,
+		maxMemory:   maxMemory,
+	}
+	s.cond = sync.NewCond(&s.lock)
+	return s
+}
+
+// Change the budget, the transfers which are waiting for budget are woken up.
+func (s *transferScheduler) setBudget(maxRequests int, maxMemory int64) error {
+	if maxRequests < 0 {
+		return fmt.Errorf("max requests must not be less than zero")
+	}
+	if maxMemory < 0 {
+		return fmt.Errorf("max memory must not be less than zero")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.maxRequests = maxRequests
+	s.maxMemory = maxMemory
+	s.cond.Broadcast()
+	return nil
+}
+
+// Acquire one request and size bytes of buffer, block until the budget is available.
+// A request larger than the memory budget is allowed when no other buffer is in use.
+// RETURN: function to release the acquired budget, it must be called exactly once.
+func (s *transferScheduler) acquire(size int64) func() {
+	s.lock.Lock()
+	for !s.available(size) {
+		s.cond.Wait()
+	}
+	s.requests++
+	s.memory += size
+	s.lock.Unlock()
+
+	return func() {
+		s.lock.Lock()
+		s.requests--
+		s.memory -= size
+		s.cond.Broadcast()
+		s.lock.Unlock()
+	}
+}
+
+// Whether a buffer of size bytes fits in the memory budget, data larger than the budget
+// should be streamed instead of buffered.
+func (s *transferScheduler) canBuffer(size int64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.maxMemory == 0 || size <= s.maxMemory
+}
+
+// must be called with lock held
+func (s *transferScheduler) available(size int64) bool {
+	if s.maxRequests > 0 && s.requests >= s.maxRequests {
+		return false
+	}
+	if s.maxMemory > 0 && s.memory > 0 && s.memory+size > s.maxMemory {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"utils/util"
+)
+
+type transferSchedulerType struct {
+	maxRequests int
+	maxMemory   int64
+	size        int64
+	workers     int
+	maxInFlight int
+}
+
+func TestTransferSchedulerAcquire(t *testing.T) {
+	testCases := []transferSchedulerType{
+		//1 limited by requests
+		transferSchedulerType{maxRequests: 3, size: 10, workers: 20, maxInFlight: 3},
+		//2 limited by memory
+		transferSchedulerType{maxMemory: 50, size: 10, workers: 20, maxInFlight: 5},
+		//3 limited by both
+		transferSchedulerType{maxRequests: 4, maxMemory: 50, size: 20, workers: 20,
+			maxInFlight: 2},
+		//4 size is larger than memory budget, run one by one
+		transferSchedulerType{maxMemory: 50, size: 100, workers: 10, maxInFlight: 1},
+		//5 no limit
+		transferSchedulerType{size: 10, workers: 5, maxInFlight: 5},
+	}
+
+	for i, tCase := range testCases {
+		s := newTransferScheduler(tCase.maxRequests, tCase.maxMemory)
+		var (
+			wg       sync.WaitGroup
+			lock     sync.Mutex
+			inFlight int
+			maxSeen  int
+		)
+		for j := 0; j < tCase.workers; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release := s.acquire(tCase.size)
+				lock.Lock()
+				inFlight++
+				if inFlight > maxSeen {
+					maxSeen = inFlight
+				}
+				lock.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				lock.Lock()
+				inFlight--
+				lock.Unlock()
+				release()
+			}()
+		}
+		wg.Wait()
+		util.ExpectEqual("scheduler acquire I", i+1, t.Errorf, true, maxSeen <= tCase.maxInFlight)
+		util.ExpectEqual("scheduler acquire II", i+1, t.Errorf, 0, s.requests)
+		util.ExpectEqual("scheduler acquire III", i+1, t.Errorf, int64(0), s.memory)
+	}
+}
+
+func TestTransferSchedulerSetBudget(t *testing.T) {
+	s := newTransferScheduler(1, 0)
+	release := s.acquire(0)
+
+	acquired := make(chan struct{})
+	go func() {
+		s.acquire(0)()
+		close(acquired)
+	}()
+
+	// waiting transfer is woken up when budget is enlarged
+	util.ExpectEqual("scheduler setBudget I", 1, t.Errorf, nil, s.setBudget(2, 0))
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Errorf("scheduler setBudget: waiting transfer isn't woken up")
+	}
+	release()
+
+	util.ExpectEqual("scheduler setBudget II", 1, t.Errorf, true, s.setBudget(-1, 0) != nil)
+	util.ExpectEqual("scheduler setBudget III", 1, t.Errorf, true, s.setBudget(0, -1) != nil)
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the server-side encryption of cp and sync.
+// With SSE-S3 (AES256) or SSE-KMS (aws:kms), the objects created are encrypted by server and
+// nothing is needed to read them. With SSE-C, the key is sent with every request of object
+// content, including each part of multipart transfers. The SSE-C key is used to write objects,
+// and to read objects by upload and download; the source objects of copy between BOS paths
+// are read with the copy source key, while their destination objects are read with the SSE-C
+// key. No key is sent when the user doesn't give one.
+
+package boscli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	AES256_KEY_SIZE = 32
+)
+
+// server-side encryption supported
+var sseAlgorithms = []string{s3.ServerSideEncryptionAes256, s3.ServerSideEncryptionAwsKms}
+
+type sseArgs struct {
+	sse           string
+	kmsKeyId      string
+	customerKey   string // SSE-C key of objects written
+	copySourceKey string // SSE-C key of the source objects of copy
+	readKey       string // SSE-C key of objects read, it is one of the keys above
+}
+
+// server-side encryption of objects transferred by cp and sync
+var transferSse sseArgs
+
+// SetServerSideEncryption - set the server-side encryption of cp and sync
+func (b *BosCli) SetServerSideEncryption(sse, kmsKeyId, customerKeyFile,
+	copySourceKeyFile string) {
+
+	args, err := newSseArgs(sse, kmsKeyId, customerKeyFile, copySourceKeyFile)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_SSE_IS_INVALID, err)
+	}
+	transferSse = *args
+}
+
+func newSseArgs(sse, kmsKeyId, customerKeyFile, copySourceKeyFile string) (*sseArgs, error) {
+	if sse != "" && !isStringIn(sse, sseAlgorithms) {
+		return nil, fmt.Errorf("unsupported server-side encryption '%s'", sse)
+	} else if kmsKeyId != "" && sse != s3.ServerSideEncryptionAwsKms {
+		return nil, fmt.Errorf("KMS key id can only be used with --sse %s",
+			s3.ServerSideEncryptionAwsKms)
+	} else if customerKeyFile != "" && sse != "" {
+		return nil, fmt.Errorf("SSE-C can't be used with --sse")
+	}
+
+	args := &sseArgs{sse: sse, kmsKeyId: kmsKeyId}
+	var err error
+	if args.customerKey, err = readCustomerKey(customerKeyFile); err != nil {
+		return nil, err
+	}
+	if args.copySourceKey, err = readCustomerKey(copySourceKeyFile); err != nil {
+		return nil, fmt.Errorf("copy source: %s", err)
+	}
+	return args, nil
+}
+
+// read SSE-C key from file, it is empty when there is no file
+func readCustomerKey(keyFile string) (string, error) {
+	if keyFile == "" {
+		return "", nil
+	}
+	content, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", fmt.Errorf("read SSE-C key file failed, error: %s", err)
+	}
+	key, err := parseAes256Key(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid SSE-C key: %s", err)
+	}
+	return string(key), nil
+}
+
+// Choose the key of objects read by cp or sync from srcPath to dstPath. The objects read by
+// copy between BOS paths are the source objects, otherwise they are the objects written or
+// downloaded with the SSE-C key.
+func (s *sseArgs) bindReadKey(srcPath, dstPath string) {
+	if isRemotePath(srcPath) && isRemotePath(dstPath) {
+		s.readKey = s.copySourceKey
+	} else {
+		s.readKey = s.customerKey
+	}
+}
+
+// Parse AES-256 key, the key file contains 32 bytes of raw key or the key encoded by base64.
+func parseAes256Key(content []byte) ([]byte, error) {
+	if len(content) == AES256_KEY_SIZE {
+		return content, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(content)))
+	if err != nil || len(key) != AES256_KEY_SIZE {
+		return nil, fmt.Errorf("key must be %d bytes or encoded by base64", AES256_KEY_SIZE)
+	}
+	return key, nil
+}
+
+// the algorithm and key of SSE-C for objects written, nil when SSE-C isn't used
+func (s *sseArgs) customerKeyArgs() (*string, *string) {
+	return customerKeyArgsOf(s.customerKey)
+}
+
+// the algorithm and key of SSE-C for the source object of copy
+func (s *sseArgs) copySourceKeyArgs() (*string, *string) {
+	return customerKeyArgsOf(s.copySourceKey)
+}
+
+// the algorithm and key of SSE-C for objects read
+func (s *sseArgs) readKeyArgs() (*string, *string) {
+	return customerKeyArgsOf(s.readKey)
+}
+
+func customerKeyArgsOf(key string) (*string, *string) {
+	if key == "" {
+		return nil, nil
+	}
+	return aws.String(s3.ServerSideEncryptionAes256), aws.String(key)
+}
+
+// the encryption of objects created, nil when it isn't set
+func (s *sseArgs) encryptionArgs() (*string, *string) {
+	var sse, kmsKeyId *string
+	if s.sse != "" {
+		sse = aws.String(s.sse)
+	}
+	if s.kmsKeyId != "" {
+		kmsKeyId = aws.String(s.kmsKeyId)
+	}
+	return sse, kmsKeyId
+}
+
+// Parse the rule of bucket default encryption, kmsKeyId can only be used with aws:kms.
+func newBucketEncryption(sse, kmsKeyId string) (*s3.ServerSideEncryptionConfiguration, error) {
+	if !isStringIn(sse, sseAlgorithms) {
+		return nil, fmt.Errorf("unsupported server-side encryption '%s'", sse)
+	} else if kmsKeyId != "" && sse != s3.ServerSideEncryptionAwsKms {
+		return nil, fmt.Errorf("KMS key id can only be used with %s",
+			s3.ServerSideEncryptionAwsKms)
+	}
+	rule := &s3.ServerSideEncryptionByDefault{SSEAlgorithm: aws.String(sse)}
+	if kmsKeyId != "" {
+		rule.KMSMasterKeyID = aws.String(kmsKeyId)
+	}
+	return &s3.ServerSideEncryptionConfiguration{
+		Rules: []*s3.ServerSideEncryptionRule{
+			&s3.ServerSideEncryptionRule{ApplyServerSideEncryptionByDefault: rule},
+		},
+	}, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+import (
+	"utils/util"
+)
+
+type newSseArgsType struct {
+	sse           string
+	kmsKeyId      string
+	keyContent    string
+	customerKey   string
+	srcKeyContent string
+	copySourceKey string
+	isSuc         bool
+}
+
+func TestNewSseArgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sse_test")
+	util.ExpectEqual("new sse args", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	rawKey := strings.Repeat("k", AES256_KEY_SIZE)
+	testCases := []newSseArgsType{
+		//1
+		newSseArgsType{isSuc: true},
+		//2
+		newSseArgsType{sse: "AES256", isSuc: true},
+		//3
+		newSseArgsType{sse: "aws:kms", kmsKeyId: "key-1", isSuc: true},
+		//4 unknown sse
+		newSseArgsType{sse: "DES"},
+		//5 kms key id without aws:kms
+		newSseArgsType{sse: "AES256", kmsKeyId: "key-1"},
+		//6 raw key
+		newSseArgsType{keyContent: rawKey, customerKey: rawKey, isSuc: true},
+		//7 base64 key
+		newSseArgsType{
+			keyContent:  base64.StdEncoding.EncodeToString([]byte(rawKey)) + "\n",
+			customerKey: rawKey,
+			isSuc:       true,
+		},
+		//8 key too short
+		newSseArgsType{keyContent: "short"},
+		//9 SSE-C with sse
+		newSseArgsType{sse: "AES256", keyContent: rawKey},
+		//10 copy source key only
+		newSseArgsType{srcKeyContent: rawKey, copySourceKey: rawKey, isSuc: true},
+		//11 copy source key with sse
+		newSseArgsType{sse: "AES256", srcKeyContent: rawKey, copySourceKey: rawKey,
+			isSuc: true},
+		//12 invalid copy source key
+		newSseArgsType{keyContent: rawKey, srcKeyContent: "short"},
+	}
+	for i, tCase := range testCases {
+		keyFile := ""
+		if tCase.keyContent != "" {
+			keyFile = filepath.Join(dir, "key")
+			err := ioutil.WriteFile(keyFile, []byte(tCase.keyContent), 0600)
+			util.ExpectEqual("new sse args", i+1, t.Errorf, nil, err)
+		}
+		srcKeyFile := ""
+		if tCase.srcKeyContent != "" {
+			srcKeyFile = filepath.Join(dir, "src_key")
+			err := ioutil.WriteFile(srcKeyFile, []byte(tCase.srcKeyContent), 0600)
+			util.ExpectEqual("new sse args", i+1, t.Errorf, nil, err)
+		}
+		args, err := newSseArgs(tCase.sse, tCase.kmsKeyId, keyFile, srcKeyFile)
+		util.ExpectEqual("new sse args", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if err != nil {
+			continue
+		}
+		algorithm, key := args.customerKeyArgs()
+		util.ExpectEqual("new sse args", i+1, t.Errorf, tCase.customerKey, aws.StringValue(key))
+		util.ExpectEqual("new sse args", i+1, t.Errorf, tCase.customerKey != "",
+			algorithm != nil)
+		_, key = args.copySourceKeyArgs()
+		util.ExpectEqual("new sse args", i+1, t.Errorf, tCase.copySourceKey,
+			aws.StringValue(key))
+		sse, kmsKeyId := args.encryptionArgs()
+		util.ExpectEqual("new sse args", i+1, t.Errorf, tCase.sse, aws.StringValue(sse))
+		util.ExpectEqual("new sse args", i+1, t.Errorf, tCase.kmsKeyId,
+			aws.StringValue(kmsKeyId))
+	}
+}
+
+func TestNewSseArgsNoKeyFile(t *testing.T) {
+	_, err := newSseArgs("", "", "/not/exist/sse/key", "")
+	util.ExpectEqual("new sse args", 1, t.Errorf, true, err != nil)
+	_, err = newSseArgs("", "", "", "/not/exist/sse/key")
+	util.ExpectEqual("new sse args", 2, t.Errorf, true, err != nil)
+}
+
+type bindReadKeyType struct {
+	srcPath string
+	dstPath string
+	readKey string
+}
+
+func TestBindReadKey(t *testing.T) {
+	testCases := []bindReadKeyType{
+		//1 upload
+		bindReadKeyType{srcPath: "./local", dstPath: "bos:/bucket/a", readKey: "dst"},
+		//2 download
+		bindReadKeyType{srcPath: "bos:/bucket/a", dstPath: "./local", readKey: "dst"},
+		//3 copy
+		bindReadKeyType{srcPath: "bos:/bucket/a", dstPath: "bos:/bucket/b", readKey: "src"},
+	}
+	for i, tCase := range testCases {
+		args := &sseArgs{customerKey: "dst", copySourceKey: "src"}
+		args.bindReadKey(tCase.srcPath, tCase.dstPath)
+		_, key := args.readKeyArgs()
+		util.ExpectEqual("bind read key", i+1, t.Errorf, tCase.readKey, aws.StringValue(key))
+	}
+
+	// no key is sent when the user doesn't give one
+	args := &sseArgs{customerKey: "dst"}
+	args.bindReadKey("bos:/bucket/a", "bos:/bucket/b")
+	algorithm, key := args.readKeyArgs()
+	util.ExpectEqual("bind read key", 4, t.Errorf, true, algorithm == nil && key == nil)
+}
+
+type newBucketEncryptionType struct {
+	sse      string
+	kmsKeyId string
+	isSuc    bool
+}
+
+func TestNewBucketEncryption(t *testing.T) {
+	testCases := []newBucketEncryptionType{
+		//1
+		newBucketEncryptionType{sse: "AES256", isSuc: true},
+		//2
+		newBucketEncryptionType{sse: "aws:kms", kmsKeyId: "key-1", isSuc: true},
+		//3 kms key id without aws:kms
+		newBucketEncryptionType{sse: "AES256", kmsKeyId: "key-1"},
+		//4 empty sse
+		newBucketEncryptionType{},
+	}
+	for i, tCase := range testCases {
+		config, err := newBucketEncryption(tCase.sse, tCase.kmsKeyId)
+		util.ExpectEqual("new bucket encryption", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if err != nil {
+			continue
+		}
+		util.ExpectEqual("new bucket encryption", i+1, t.Errorf, 1, len(config.Rules))
+		rule := config.Rules[0].ApplyServerSideEncryptionByDefault
+		util.ExpectEqual("new bucket encryption", i+1, t.Errorf, tCase.sse,
+			aws.StringValue(rule.SSEAlgorithm))
+		util.ExpectEqual("new bucket encryption", i+1, t.Errorf, tCase.kmsKeyId,
+			aws.StringValue(rule.KMSMasterKeyID))
+	}
+}
@@ -60,7 +60,7 @@ func (s *crc32Sync) shouldSync(src *fileDetail, dst *fileDetail) (bool, error) {
 			return false, err
 		}
 	} else {
-		if dstObjectMeta, err := getObjectMeta(s.dstBosClient, s.dstBucketName,
+		if dstObjectMeta, err := getDestinationObjectMeta(s.dstBosClient, s.dstBucketName,
 			dst.path); err != nil {
 			return false, err
 		} else {
@@ -123,9 +123,19 @@ func (s *sizeAndLastModifiedAndCrc32Sync) genSyncFunc() string {
 	return OPERATE_CMD_COPY
 }
 
-type sizeAndLastModifiedSync struct{}
+// the object types and clients are only used to get the original size of objects
+type sizeAndLastModifiedSync struct {
+	srcType       string
+	dstType       string
+	srcBucketName string
+	dstBucketName string
+	srcBosClient  bosClientInterface
+	dstBosClient  bosClientInterface
+}
 
-// Compares size and last modified time only
+// Compares size and last modified time only. The size of compressed or encrypted object differs
+// from its original file, so the original size and last modified time kept in the metadata of
+// objects are compared when the sizes are different.
 func (s *sizeAndLastModifiedSync) shouldSync(src *fileDetail, dst *fileDetail) (bool, error) {
 
 	srcMtime := src.mtime
@@ -133,6 +143,18 @@ func (s *sizeAndLastModifiedSync) shouldSync(src *fileDetail, dst *fileDetail) (
 	srcSize := src.size
 	dstSize := dst.size
 
+	if srcSize != dstSize {
+		var err error
+		if srcSize, srcMtime, err = originalOfFile(s.srcType, s.srcBosClient, s.srcBucketName,
+			src, false); err != nil {
+			return false, err
+		}
+		if dstSize, dstMtime, err = originalOfFile(s.dstType, s.dstBosClient, s.dstBucketName,
+			dst, true); err != nil {
+			return false, err
+		}
+	}
+
 	if srcMtime > dstMtime || (srcMtime == dstMtime && srcSize != dstSize) {
 
 		log.Debugf("src path: %s, dst path %s src size: %d, dst size %d src lastModified: %d, dst "+
@@ -148,6 +170,26 @@ func (s *sizeAndLastModifiedSync) genSyncFunc() string {
 	return OPERATE_CMD_COPY
 }
 
+// the original size and last modified time of file, they are got from the metadata of object,
+// isDestination is whether the file is the destination of sync
+func originalOfFile(fileType string, bosClient bosClientInterface, bucketName string,
+	file *fileDetail, isDestination bool) (int64, int64, error) {
+
+	if fileType != IS_BOS || bosClient == nil {
+		return file.size, file.mtime, nil
+	}
+	headObject := bosClient.GetObjectMeta
+	if isDestination {
+		headObject = bosClient.GetDestinationObjectMeta
+	}
+	ret, err := headObject(bucketName, file.path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return originalSizeOf(ret.Metadata, file.size), originalMtimeOf(ret.Metadata, file.mtime),
+		nil
+}
+
 // Deletes the sync dst
 type deleteDstSync struct {
 	deleteFilter  *bosFilter
@@ -0,0 +1,119 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the parsing of tags "k1=v1,k2=v2" used by bucket and object tagging.
+
+package boscli
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	TAG_SEPARATOR        = ","
+	TAG_KV_SEPARATOR     = "="
+	TAG_MAX_KEY_SIZE     = 128
+	TAG_MAX_VALUE_SIZE   = 256
+	BUCKET_MAX_TAG_COUNT = 50
+	OBJECT_MAX_TAG_COUNT = 10
+)
+
+// tags of objects created by cp and sync, in the query format of x-amz-tagging header
+var uploadTagging string
+
+// SetUploadTags - set the tags of objects created by cp and sync, tags is "k1=v1,k2=v2"
+func (b *BosCli) SetUploadTags(tags string) {
+	if tags == "" {
+		uploadTagging = ""
+		return
+	}
+	tagSet, err := parseTags(tags, OBJECT_MAX_TAG_COUNT)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_TAGS_IS_INVALID, err)
+	}
+	uploadTagging = encodeTagging(tagSet)
+}
+
+// Parse tags "k1=v1,k2=v2", the value can be empty and can contain '=', keys must be unique.
+func parseTags(tags string, maxCount int) ([]*s3.Tag, error) {
+	var tagSet []*s3.Tag
+	keys := make(map[string]bool)
+	for _, item := range strings.Split(tags, TAG_SEPARATOR) {
+		kv := strings.SplitN(item, TAG_KV_SEPARATOR, 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid tag '%s', tag must be 'key=value'", item)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if key == "" {
+			return nil, fmt.Errorf("key of tag '%s' is empty", item)
+		} else if utf8.RuneCountInString(key) > TAG_MAX_KEY_SIZE {
+			return nil, fmt.Errorf("key of tag '%s' is longer than %d", item, TAG_MAX_KEY_SIZE)
+		} else if utf8.RuneCountInString(value) > TAG_MAX_VALUE_SIZE {
+			return nil, fmt.Errorf("value of tag '%s' is longer than %d", item,
+				TAG_MAX_VALUE_SIZE)
+		} else if strings.HasPrefix(key, "aws:") {
+			return nil, fmt.Errorf("key of tag '%s' can't start with 'aws:'", item)
+		} else if keys[key] {
+			return nil, fmt.Errorf("key '%s' is duplicate", key)
+		}
+		keys[key] = true
+		tagSet = append(tagSet, &s3.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	if len(tagSet) > maxCount {
+		return nil, fmt.Errorf("there can be at most %d tags", maxCount)
+	}
+	return tagSet, nil
+}
+
+// Encode tags in the query format of x-amz-tagging header, spaces are encoded as %20 rather
+// than '+'.
+func encodeTagging(tagSet []*s3.Tag) string {
+	query := url.Values{}
+	for _, tag := range tagSet {
+		query.Set(aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+	}
+	return strings.Replace(query.Encode(), "+", "%20", -1)
+}
+
+// format tags as "key=value" lines sorted by key
+func formatTags(tagSet []*s3.Tag) string {
+	lines := make([]string, 0, len(tagSet))
+	for _, tag := range tagSet {
+		lines = append(lines, aws.StringValue(tag.Key)+TAG_KV_SEPARATOR+
+			aws.StringValue(tag.Value))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// whether tagSet has all the tags of want
+func hasAllTags(tagSet []*s3.Tag, want map[string]string) bool {
+	have := make(map[string]string, len(tagSet))
+	for _, tag := range tagSet {
+		have[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	for key, value := range want {
+		if val, ok := have[key]; !ok || val != value {
+			return false
+		}
+	}
+	return true
+}
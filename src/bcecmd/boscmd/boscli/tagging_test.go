@@ -0,0 +1,170 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"utils/util"
+)
+
+type parseTagsType struct {
+	tags     string
+	maxCount int
+	tagging  string
+	isSuc    bool
+}
+
+func TestParseTags(t *testing.T) {
+	testCases := []parseTagsType{
+		//1
+		parseTagsType{
+			tags:     "project=web, env=prod",
+			maxCount: OBJECT_MAX_TAG_COUNT,
+			tagging:  "env=prod&project=web",
+			isSuc:    true,
+		},
+		//2 empty value and '=' in value
+		parseTagsType{
+			tags:     "retention=,expr=a=b c",
+			maxCount: OBJECT_MAX_TAG_COUNT,
+			tagging:  "expr=a%3Db%20c&retention=",
+			isSuc:    true,
+		},
+		//3 no '='
+		parseTagsType{tags: "project", maxCount: OBJECT_MAX_TAG_COUNT},
+		//4 empty key
+		parseTagsType{tags: "=web", maxCount: OBJECT_MAX_TAG_COUNT},
+		//5 duplicate key
+		parseTagsType{tags: "env=prod,env=test", maxCount: OBJECT_MAX_TAG_COUNT},
+		//6 reserved prefix
+		parseTagsType{tags: "aws:env=prod", maxCount: OBJECT_MAX_TAG_COUNT},
+		//7 key too long
+		parseTagsType{tags: strings.Repeat("k", TAG_MAX_KEY_SIZE+1) + "=v",
+			maxCount: OBJECT_MAX_TAG_COUNT},
+		//8 value too long
+		parseTagsType{tags: "k=" + strings.Repeat("v", TAG_MAX_VALUE_SIZE+1),
+			maxCount: OBJECT_MAX_TAG_COUNT},
+		//9 too many tags
+		parseTagsType{tags: "a=1,b=2,c=3", maxCount: 2},
+		//10 empty
+		parseTagsType{tags: "", maxCount: OBJECT_MAX_TAG_COUNT},
+	}
+	for i, tCase := range testCases {
+		tagSet, err := parseTags(tCase.tags, tCase.maxCount)
+		util.ExpectEqual("parse tags", i+1, t.Errorf, tCase.isSuc, err == nil)
+		if err != nil {
+			continue
+		}
+		util.ExpectEqual("parse tags", i+1, t.Errorf, tCase.tagging, encodeTagging(tagSet))
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	tagSet, err := parseTags("project=web,env=prod", OBJECT_MAX_TAG_COUNT)
+	util.ExpectEqual("format tags", 1, t.Errorf, nil, err)
+	util.ExpectEqual("format tags", 1, t.Errorf, "env=prod\nproject=web", formatTags(tagSet))
+}
+
+type tagFilterType struct {
+	tags     string
+	filtered bool
+}
+
+func TestTagFilter(t *testing.T) {
+	filter := &bosFilter{}
+	retCode, err := filter.setTags([]string{"env=prod", "team=web"})
+	util.ExpectEqual("tag filter", 0, t.Errorf, BOSCLI_OK, retCode)
+	util.ExpectEqual("tag filter", 0, t.Errorf, nil, err)
+
+	testCases := []tagFilterType{
+		//1 has all tags
+		tagFilterType{tags: "team=web,env=prod,owner=a", filtered: false},
+		//2 missing a tag
+		tagFilterType{tags: "env=prod", filtered: true},
+		//3 different value
+		tagFilterType{tags: "env=test,team=web", filtered: true},
+	}
+	for i, tCase := range testCases {
+		tagSet, _ := parseTags(tCase.tags, OBJECT_MAX_TAG_COUNT)
+		util.ExpectEqual("tag filter", i+1, t.Errorf, tCase.filtered, filter.TagFilter(tagSet))
+	}
+	//4 no tags
+	util.ExpectEqual("tag filter", 4, t.Errorf, true, filter.TagFilter(nil))
+
+	//5 local source
+	_, retCode, _ = newFilterFromArgs(&FilterArgs{TagFilter: []string{"env=prod"}}, "./")
+	util.ExpectEqual("tag filter", 5, t.Errorf, BOSCLI_TAG_FILTER_ONLY_FOR_BOS, retCode)
+}
+
+// client which lists objects and records the objects whose tags are got
+type fakeTaggingClient struct {
+	fakeUnsupportedBosClient
+	objects []*s3.Object
+	tagged  []string
+}
+
+func (b *fakeTaggingClient) ListObjects(bucket, delimiter, marker, prefix string,
+	maxKeys int) (*s3.ListObjectsOutput, error) {
+	return &s3.ListObjectsOutput{Contents: b.objects, IsTruncated: aws.Bool(false)}, nil
+}
+
+func (b *fakeTaggingClient) GetObjectTagging(bucket, object string) (
+	*s3.GetObjectTaggingOutput, error) {
+	b.tagged = append(b.tagged, object)
+	tagSet, _ := parseTags("env=prod", OBJECT_MAX_TAG_COUNT)
+	return &s3.GetObjectTaggingOutput{TagSet: tagSet}, nil
+}
+
+// the tags are only got for the objects kept by the pattern and time filters
+func TestTagFilterAfterOtherFilters(t *testing.T) {
+	client := &fakeTaggingClient{
+		objects: []*s3.Object{
+			fakeObject("a.txt", "2020-01-01T00:00:00Z", 1, "STANDARD"),
+			fakeObject("b.log", "2020-01-01T00:00:00Z", 1, "STANDARD"),
+			fakeObject("old.txt", "1970-01-01T00:00:10Z", 1, "STANDARD"),
+		},
+	}
+	filter, retCode, err := newFilterFromArgs(&FilterArgs{
+		RegexExclude: []string{`\.log$`},
+		ExcludeTime:  []string{"0,1000"},
+		TagFilter:    []string{"env=prod"},
+	}, "bos:/bucket/")
+	util.ExpectEqual("tag filter order", 1, t.Errorf, BOSCLI_OK, retCode)
+	util.ExpectEqual("tag filter order", 1, t.Errorf, nil, err)
+
+	var listed []string
+	objectList := NewObjectListIterator(client, filter, "bucket", "", "", true, true, true,
+		false, 1000)
+	for {
+		listResult, err := objectList.next()
+		if err != nil || listResult.err != nil {
+			t.Errorf("list objects failed: %v %v", err, listResult)
+			break
+		}
+		if listResult.ended {
+			break
+		}
+		listed = append(listed, listResult.file.path)
+	}
+	util.ExpectEqual("tag filter order", 2, t.Errorf, []string{"a.txt"}, listed)
+	util.ExpectEqual("tag filter order", 3, t.Errorf, []string{"a.txt"}, client.tagged)
+}
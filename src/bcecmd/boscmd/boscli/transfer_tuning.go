@@ -0,0 +1,149 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the thresholds, part sizes and thread numbers of multipart upload,
+// download and copy.
+
+package boscli
+
+import (
+	"fmt"
+	"sync"
+)
+
+import (
+	"bceconf"
+)
+
+// MultipartTuning - options of multipart upload, download or copy.
+// Threshold and PartSize are in bytes, the value in config file is used when an option is 0.
+type MultipartTuning struct {
+	Threshold int64 // use multipart transfer when object is larger than threshold
+	PartSize  int64
+	ThreadNum int64
+}
+
+// TransferTuning - options of all kinds of multipart transfer
+type TransferTuning struct {
+	Upload   MultipartTuning
+	Download MultipartTuning
+	Copy     MultipartTuning
+	AutoTune bool // tune part size and the number of in-flight parts by throughput
+}
+
+// the min part size of each kind of multipart transfer
+var minPartSizes = map[string]int64{
+	"upload":   MIN_PART_SIZE,
+	"download": 1,
+	"copy":     MIN_PART_SIZE,
+}
+
+var (
+	transferTuning     *TransferTuning // resolved options, nil until they are used or set
+	transferTuningLock sync.Mutex
+)
+
+// SetTransferTuning - set the options of multipart transfers of cp and sync, options which are 0
+// are read from config file.
+func (b *BosCli) SetTransferTuning(tuning *TransferTuning) {
+	resolved, err := resolveTransferTuning(tuning)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(BOSCLI_TRANSFER_TUNING_IS_INVALID, err)
+	}
+	transferTuningLock.Lock()
+	transferTuning = resolved
+	transferTuningLock.Unlock()
+}
+
+// Get the options of multipart transfers, read them from config file when they are not set.
+func getTransferTuning() (*TransferTuning, error) {
+	transferTuningLock.Lock()
+	defer transferTuningLock.Unlock()
+	if transferTuning == nil {
+		resolved, err := resolveTransferTuning(&TransferTuning{})
+		if err != nil {
+			return nil, err
+		}
+		transferTuning = resolved
+	}
+	return transferTuning, nil
+}
+
+// Fill the options which are 0 with the value in config file, and check them.
+func resolveTransferTuning(tuning *TransferTuning) (*TransferTuning, error) {
+	if tuning == nil {
+		tuning = &TransferTuning{}
+	}
+	provider := bceconf.ServerConfigProvider
+
+	upload, err := resolveMultipartTuning("upload", tuning.Upload,
+		provider.GetMultiUploadThreshold, provider.GetMultiUploadPartSize,
+		provider.GetMultiUploadThreadNum)
+	if err != nil {
+		return nil, err
+	}
+	download, err := resolveMultipartTuning("download", tuning.Download,
+		provider.GetMultiDownloadThreshold, provider.GetMultiDownloadPartSize,
+		provider.GetMultiDownloadThreadNum)
+	if err != nil {
+		return nil, err
+	}
+	copyTuning, err := resolveMultipartTuning("copy", tuning.Copy,
+		provider.GetMultiCopyThreshold, provider.GetMultiCopyPartSize,
+		provider.GetMultiCopyThreadNum)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferTuning{Upload: *upload, Download: *download, Copy: *copyTuning,
+		AutoTune: tuning.AutoTune}, nil
+}
+
+// Fill the options of one kind of multipart transfer, the sizes in config file are in MB.
+func resolveMultipartTuning(name string, tuning MultipartTuning, getThreshold, getPartSize,
+	getThreadNum func() (int64, bool)) (*MultipartTuning, error) {
+
+	if tuning.Threshold < 0 || tuning.PartSize < 0 || tuning.ThreadNum < 0 {
+		return nil, fmt.Errorf("%s threshold, part size and thread num must not be less than "+
+			"zero", name)
+	}
+	if tuning.Threshold == 0 {
+		val, ok := getThreshold()
+		if !ok {
+			return nil, fmt.Errorf("There is no info about multi %s threshold found!", name)
+		}
+		tuning.Threshold = val << 20
+	}
+	if tuning.PartSize == 0 {
+		val, ok := getPartSize()
+		if !ok {
+			return nil, fmt.Errorf("There is no info about multi %s part size found!", name)
+		}
+		tuning.PartSize = val << 20
+	}
+	if tuning.ThreadNum == 0 {
+		val, ok := getThreadNum()
+		if !ok {
+			return nil, fmt.Errorf("There is no info about multi %s thread num found!", name)
+		}
+		tuning.ThreadNum = val
+	}
+
+	if tuning.PartSize < minPartSizes[name] || tuning.PartSize > MAX_PART_SIZE {
+		return nil, fmt.Errorf("%s part size must be between %d and %d bytes", name,
+			minPartSizes[name], MAX_PART_SIZE)
+	}
+	if tuning.Threshold > tuning.PartSize*MAX_PARTS {
+		return nil, fmt.Errorf("%s threshold must not be greater than %d times of part size",
+			name, MAX_PARTS)
+	}
+	return &tuning, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+)
+
+import (
+	"utils/util"
+)
+
+type resolveMultipartTuningType struct {
+	tuning MultipartTuning
+	ret    MultipartTuning
+	isSuc  bool
+}
+
+func TestResolveMultipartTuning(t *testing.T) {
+	getThreshold := func() (int64, bool) { return 100, true }
+	getPartSize := func() (int64, bool) { return 10, true }
+	getThreadNum := func() (int64, bool) { return 8, true }
+
+	testCases := []resolveMultipartTuningType{
+		//1 all from config
+		resolveMultipartTuningType{
+			ret:   MultipartTuning{Threshold: 100 << 20, PartSize: 10 << 20, ThreadNum: 8},
+			isSuc: true,
+		},
+		//2 flags take precedence
+		resolveMultipartTuningType{
+			tuning: MultipartTuning{Threshold: 1 << 20, PartSize: 5 << 20, ThreadNum: 2},
+			ret:    MultipartTuning{Threshold: 1 << 20, PartSize: 5 << 20, ThreadNum: 2},
+			isSuc:  true,
+		},
+		//3 part is too small
+		resolveMultipartTuningType{
+			tuning: MultipartTuning{PartSize: MIN_PART_SIZE - 1},
+			isSuc:  false,
+		},
+		//4 negative
+		resolveMultipartTuningType{
+			tuning: MultipartTuning{ThreadNum: -1},
+			isSuc:  false,
+		},
+		//5 part is too large
+		resolveMultipartTuningType{
+			tuning: MultipartTuning{PartSize: MAX_PART_SIZE + 1},
+			isSuc:  false,
+		},
+		//6 too many parts
+		resolveMultipartTuningType{
+			tuning: MultipartTuning{Threshold: 1 << 40, PartSize: MIN_PART_SIZE},
+			isSuc:  false,
+		},
+	}
+
+	for i, tCase := range testCases {
+		ret, err := resolveMultipartTuning("upload", tCase.tuning, getThreshold, getPartSize,
+			getThreadNum)
+		util.ExpectEqual("transfer_tuning resolveMultipartTuning I", i+1, t.Errorf, tCase.isSuc,
+			err == nil)
+		if tCase.isSuc && err == nil {
+			util.ExpectEqual("transfer_tuning resolveMultipartTuning II", i+1, t.Errorf,
+				tCase.ret, *ret)
+		}
+	}
+
+	// parts of download may be smaller than MIN_PART_SIZE
+	ret, err := resolveMultipartTuning("download", MultipartTuning{PartSize: 1 << 20},
+		getThreshold, getPartSize, getThreadNum)
+	util.ExpectEqual("transfer_tuning resolveMultipartTuning IV", 1, t.Errorf, nil, err)
+	if err == nil {
+		util.ExpectEqual("transfer_tuning resolveMultipartTuning IV", 2, t.Errorf,
+			MultipartTuning{Threshold: 100 << 20, PartSize: 1 << 20, ThreadNum: 8}, *ret)
+	}
+
+	// no config
+	noConfig := func() (int64, bool) { return 0, false }
+	_, err = resolveMultipartTuning("copy", MultipartTuning{}, noConfig, getPartSize,
+		getThreadNum)
+	util.ExpectEqual("transfer_tuning resolveMultipartTuning III", 1, t.Errorf, true,
+		err != nil)
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the transformation of content by cp and sync. The file to be uploaded
+// is compressed and then encrypted to temporary files, the metadata of transformation is sent
+// with the upload; the downloaded file is decrypted and then decompressed by the metadata of
+// object.
+
+package boscli
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+import (
+	"utils/util"
+)
+
+const (
+	TRANSFORM_TMP_FILE_NAME = "bcecmd.transform."
+)
+
+// whether the files uploaded by cp and sync are transformed
+func uploadTransformEnabled() bool {
+	return uploadCompress != "" || clientEncryption != nil
+}
+
+// Transform the file srcPath to be uploaded to dstPath, the transformed files are staged in
+// tmpDir. The metadata of transformation is returned as the metadata of the transformed file,
+// which is sent with the upload. The returned function removes the temporary files.
+// The temporary files are named by source and destination and keep the modify time of source,
+// so that the breakpoint record of a failed upload is found again by the next upload of the
+// same file, and it is resumed when the transformed content doesn't change.
+func stageUpload(srcPath, dstPath, tmpDir string) (*fileDetail, func(), error) {
+	srcInfo, err := os.Stat(srcPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpFilePrefix := filepath.Join(tmpDir,
+		TRANSFORM_TMP_FILE_NAME+util.StringMd5(srcPath+"_"+dstPath)+".")
+
+	var tmpFiles []string
+	cleanup := func() {
+		for _, tmpFilePath := range tmpFiles {
+			os.Remove(tmpFilePath)
+		}
+	}
+	fail := func(err error) (*fileDetail, func(), error) {
+		cleanup()
+		return nil, nil, err
+	}
+
+	meta := make(map[string]*string)
+	stage := func(transform func(src, dst string) (map[string]*string, error)) error {
+		tmpFilePath := tmpFilePrefix + strconv.Itoa(len(tmpFiles))
+		tmpFiles = append(tmpFiles, tmpFilePath)
+		stageMeta, err := transform(srcPath, tmpFilePath)
+		if err != nil {
+			return err
+		}
+		err = os.Chtimes(tmpFilePath, srcInfo.ModTime(), srcInfo.ModTime())
+		if err != nil {
+			return err
+		}
+		for key, val := range stageMeta {
+			meta[key] = val
+		}
+		srcPath = tmpFilePath
+		return nil
+	}
+
+	if uploadCompress != "" {
+		err := stage(func(src, dst string) (map[string]*string, error) {
+			return compressFile(src, dst, uploadCompress)
+		})
+		if err != nil {
+			return fail(err)
+		}
+	}
+	if clientEncryption != nil {
+		if err := stage(clientEncryption.encryptFile); err != nil {
+			return fail(err)
+		}
+	}
+
+	fileInfo, err := getFileMate(srcPath)
+	if err != nil {
+		return fail(err)
+	}
+	fileInfo.metadata = meta
+	return fileInfo, cleanup, nil
+}
+
+// Restore the object downloaded to fileName by the user metadata got with the object. The
+// object encrypted by client is kept as it is when the key of client-side encryption isn't set.
+func restoreDownload(meta map[string]*string, fileName string) error {
+	if metadataValue(meta, CSE_META_KEY) != "" {
+		if clientEncryption == nil {
+			return nil
+		}
+		if err := clientEncryption.decryptFile(fileName, meta); err != nil {
+			return err
+		}
+	}
+	if metadataValue(meta, COMPRESS_META_ALGORITHM) != "" {
+		return decompressFile(fileName, meta)
+	}
+	return nil
+}
+
+// the size of the original file of object, which is the size before compression and
+// encryption
+func originalSizeOf(meta map[string]*string, size int64) int64 {
+	for _, name := range []string{COMPRESS_META_ORIGINAL_SIZE, CSE_META_SIZE} {
+		if val, err := strconv.ParseInt(metadataValue(meta, name), 10, 64); err == nil {
+			return val
+		}
+	}
+	return size
+}
+
+// the last modified time of the original file of compressed object
+func originalMtimeOf(meta map[string]*string, mtime int64) int64 {
+	val, err := strconv.ParseInt(metadataValue(meta, COMPRESS_META_ORIGINAL_MTIME), 10, 64)
+	if err != nil {
+		return mtime
+	}
+	return val
+}
+
+// the value of user metadata, the name of metadata got from server is canonicalized
+func metadataValue(meta map[string]*string, name string) string {
+	for key, val := range meta {
+		if strings.EqualFold(key, name) {
+			return aws.StringValue(val)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"utils/util"
+)
+
+// client which returns the metadata of object
+type fakeMetadataClient struct {
+	bosClientInterface
+	meta             map[string]*string
+	destinationHeads int
+}
+
+func (f *fakeMetadataClient) GetObjectMeta(bucket, object string) (*s3.HeadObjectOutput,
+	error) {
+	return &s3.HeadObjectOutput{Metadata: f.meta}, nil
+}
+
+func (f *fakeMetadataClient) GetDestinationObjectMeta(bucket,
+	object string) (*s3.HeadObjectOutput, error) {
+	f.destinationHeads++
+	return f.GetObjectMeta(bucket, object)
+}
+
+// metadata got from server, whose names are canonicalized
+func serverMetadata(meta map[string]*string) map[string]*string {
+	ret := make(map[string]*string)
+	for key, val := range meta {
+		ret[strings.ToUpper(key)] = aws.String(aws.StringValue(val))
+	}
+	return ret
+}
+
+type stageUploadType struct {
+	compress string
+	encrypt  bool
+}
+
+func TestStageAndRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transform_test")
+	util.ExpectEqual("stage upload", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+	defer func() {
+		uploadCompress = ""
+		clientEncryption = nil
+	}()
+
+	content := strings.Repeat("log line\n", 1000)
+	src := filepath.Join(dir, "src")
+	ioutil.WriteFile(src, []byte(content), 0644)
+	srcInfo, _ := os.Stat(src)
+	encryptor := newTestEncryptor(t, dir, strings.Repeat("m", AES256_KEY_SIZE))
+
+	testCases := []stageUploadType{
+		//1
+		stageUploadType{compress: COMPRESS_GZIP},
+		//2
+		stageUploadType{encrypt: true},
+		//3
+		stageUploadType{compress: COMPRESS_GZIP, encrypt: true},
+		//4
+		stageUploadType{compress: COMPRESS_ZSTD, encrypt: true},
+	}
+	for i, tCase := range testCases {
+		uploadCompress = tCase.compress
+		clientEncryption = nil
+		if tCase.encrypt {
+			clientEncryption = encryptor
+		}
+		util.ExpectEqual("stage upload", i+1, t.Errorf, true, uploadTransformEnabled())
+
+		fileInfo, cleanup, err := stageUpload(src, "bos:/bucket/object", dir)
+		util.ExpectEqual("stage upload", i+1, t.Errorf, nil, err)
+		// the staged file is found again by the next upload of the same source
+		util.ExpectEqual("stage upload", i+1, t.Errorf, dir, filepath.Dir(fileInfo.path))
+		util.ExpectEqual("stage upload", i+1, t.Errorf, srcInfo.ModTime().Unix(), fileInfo.mtime)
+		meta := fileInfo.metadata
+		util.ExpectEqual("stage upload", i+1, t.Errorf, int64(len(content)),
+			originalSizeOf(meta, fileInfo.size))
+		util.ExpectEqual("stage upload", i+1, t.Errorf, tCase.compress != "" && !tCase.encrypt,
+			contentEncodingOf(meta) != nil)
+		staged, _ := ioutil.ReadFile(fileInfo.path)
+		cleanup()
+		util.ExpectEqual("stage upload", i+1, t.Errorf, false, util.DoesFileExist(fileInfo.path))
+		nextInfo, nextCleanup, err := stageUpload(src, "bos:/bucket/object", dir)
+		util.ExpectEqual("stage upload", i+1, t.Errorf, nil, err)
+		util.ExpectEqual("stage upload", i+1, t.Errorf, fileInfo.path, nextInfo.path)
+		nextCleanup()
+
+		dst := filepath.Join(dir, "dst")
+		ioutil.WriteFile(dst, staged, 0644)
+		err = restoreDownload(serverMetadata(meta), dst)
+		util.ExpectEqual("restore download", i+1, t.Errorf, nil, err)
+		restored, _ := ioutil.ReadFile(dst)
+		util.ExpectEqual("restore download", i+1, t.Errorf, content, string(restored))
+	}
+}
+
+func TestRestoreDownloadUntransformed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transform_test")
+	util.ExpectEqual("restore download", 0, t.Errorf, nil, err)
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "dst")
+	ioutil.WriteFile(dst, []byte("hello"), 0644)
+
+	//1 no metadata
+	err = restoreDownload(nil, dst)
+	util.ExpectEqual("restore download", 1, t.Errorf, nil, err)
+
+	//2 encrypted by client, but the key isn't set
+	clientEncryption = nil
+	err = restoreDownload(map[string]*string{CSE_META_KEY: aws.String("key")}, dst)
+	util.ExpectEqual("restore download", 2, t.Errorf, nil, err)
+	restored, _ := ioutil.ReadFile(dst)
+	util.ExpectEqual("restore download", 2, t.Errorf, "hello", string(restored))
+}
+
+func TestOriginalOfFile(t *testing.T) {
+	client := &fakeMetadataClient{meta: map[string]*string{
+		"Bcecmd-Original-Size":  aws.String("100"),
+		"Bcecmd-Original-Mtime": aws.String("5")}}
+	//1 object
+	size, mtime, err := originalOfFile(IS_BOS, client, "bucket", &fileDetail{size: 10,
+		mtime: 9}, false)
+	util.ExpectEqual("original", 1, t.Errorf, nil, err)
+	util.ExpectEqual("original", 1, t.Errorf, int64(100), size)
+	util.ExpectEqual("original", 1, t.Errorf, int64(5), mtime)
+	//2 local file
+	size, mtime, err = originalOfFile(IS_LOCAL, nil, "", &fileDetail{size: 10, mtime: 9},
+		false)
+	util.ExpectEqual("original", 2, t.Errorf, nil, err)
+	util.ExpectEqual("original", 2, t.Errorf, int64(10), size)
+	util.ExpectEqual("original", 2, t.Errorf, int64(9), mtime)
+	//3 object without metadata
+	size, mtime, err = originalOfFile(IS_BOS, &fakeMetadataClient{}, "bucket",
+		&fileDetail{size: 10, mtime: 9}, false)
+	util.ExpectEqual("original", 3, t.Errorf, nil, err)
+	util.ExpectEqual("original", 3, t.Errorf, int64(10), size)
+	util.ExpectEqual("original", 3, t.Errorf, int64(9), mtime)
+
+	strategy := &sizeAndLastModifiedSync{srcType: IS_LOCAL, dstType: IS_BOS,
+		dstBucketName: "bucket", dstBosClient: client}
+	//4 the file isn't changed after it is compressed and uploaded
+	ret, err := strategy.shouldSync(&fileDetail{size: 100, mtime: 5},
+		&fileDetail{size: 10, mtime: 9})
+	util.ExpectEqual("original", 4, t.Errorf, nil, err)
+	util.ExpectEqual("original", 4, t.Errorf, false, ret)
+	// the destination object is got with the SSE-C key of destination
+	util.ExpectEqual("original", 4, t.Errorf, 1, client.destinationHeads)
+	//5 the file is changed, but its last modified time is kept
+	ret, err = strategy.shouldSync(&fileDetail{size: 99, mtime: 5},
+		&fileDetail{size: 10, mtime: 9})
+	util.ExpectEqual("original", 5, t.Errorf, nil, err)
+	util.ExpectEqual("original", 5, t.Errorf, true, ret)
+	//6 the file is modified after upload
+	ret, err = strategy.shouldSync(&fileDetail{size: 100, mtime: 6},
+		&fileDetail{size: 10, mtime: 9})
+	util.ExpectEqual("original", 6, t.Errorf, nil, err)
+	util.ExpectEqual("original", 6, t.Errorf, true, ret)
+}
@@ -13,6 +13,8 @@
 package boscli
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -25,6 +27,7 @@ import (
 
 import (
 	"bcecmd/boscmd"
+	"bceconf"
 	"github.com/baidubce/bce-sdk-go/services/bos/api"
 	"utils/util"
 )
@@ -71,15 +74,56 @@ func splitBosBucketKey(bosPath string) (string, string) {
 	if bosPath == "" {
 		return "", ""
 	}
+	_, bosPath, _ = splitRemotePath(bosPath)
+	return extractBucketNameAndKey(bosPath)
+}
 
-	if strings.HasPrefix(bosPath, BOS_PATH_PREFIX_DOUBLE) {
-		return extractBucketNameAndKey(bosPath[len(BOS_PATH_PREFIX_DOUBLE):])
+// Split remote path into the name of remote and the path in remote.
+// Path start with "bos:/", "bos://" or "s3://" belongs to the default remote, whose name is
+// empty, and path like "name:bucket/key" belongs to remote "name" when it is configured.
+// RETURN: the name of remote, the path without prefix, whether it is a remote path
+func splitRemotePath(remotePath string) (string, string, bool) {
+	switch {
+	case strings.HasPrefix(remotePath, BOS_PATH_PREFIX_DOUBLE):
+		return "", remotePath[len(BOS_PATH_PREFIX_DOUBLE):], true
+	case strings.HasPrefix(remotePath, BOS_PATH_PREFIX):
+		return "", remotePath[len(BOS_PATH_PREFIX):], true
+	case strings.HasPrefix(remotePath, S3_PATH_PREFIX):
+		return "", remotePath[len(S3_PATH_PREFIX):], true
+	}
+	if index := strings.Index(remotePath, REMOTE_NAME_SEPARATOR); index > 0 {
+		remoteName := remotePath[:index]
+		if !strings.Contains(remoteName, boscmd.BOS_PATH_SEPARATOR) &&
+			!strings.Contains(remoteName, util.OsPathSeparator) && isRemoteConfigured(remoteName) {
+			return remoteName, remotePath[index+len(REMOTE_NAME_SEPARATOR):], true
+		}
 	}
+	return "", remotePath, false
+}
 
-	if strings.HasPrefix(bosPath, BOS_PATH_PREFIX) {
-		return extractBucketNameAndKey(bosPath[len(BOS_PATH_PREFIX):])
+// Join the name of remote, bucket name and object key into a remote path, the path of default
+// remote starts with BOS_PATH_PREFIX.
+func joinRemotePath(remoteName, bucketName, objectKey string) string {
+	prefix := BOS_PATH_PREFIX
+	if remoteName != "" {
+		prefix = remoteName + REMOTE_NAME_SEPARATOR
 	}
-	return extractBucketNameAndKey(bosPath)
+	return prefix + bucketName + boscmd.BOS_PATH_SEPARATOR + objectKey
+}
+
+// Check whether there is a remote named remoteName in config file
+func isRemoteConfigured(remoteName string) bool {
+	if bceconf.ServerConfigProvider == nil {
+		return false
+	}
+	_, ok := bceconf.ServerConfigProvider.GetRemote(remoteName)
+	return ok
+}
+
+// Check whether path is a remote path, e.g. bos:/bucket, s3://bucket or remote:bucket
+func isRemotePath(inputPath string) bool {
+	_, _, ok := splitRemotePath(inputPath)
+	return ok
 }
 
 func extractBucketNameAndKey(bosPath string) (string, string) {
@@ -214,8 +258,10 @@ func splitPathAndFile(inputPath string) (string, string) {
 // localPath must be absolute path
 // file name is sorted by lexicographical ordeor
 // NOTICE: when sort file names, os separator is replace to bos separator
+// when useIgnoreFile is true, the files ignored by .bcecmdignore and the .bcecmdignore files in
+// directories are not listed.
 func NewLocalFileIterator(localPath string, filter *bosFilter,
-	followSymlinks bool) *LocalFileIterator {
+	followSymlinks, useIgnoreFile bool) *LocalFileIterator {
 
 	localPathPrefix := ""
 	if util.DoesDirExist(localPath) {
@@ -228,6 +274,7 @@ func NewLocalFileIterator(localPath string, filter *bosFilter,
 		filter:             filter,
 		filesChan:          make(chan listFileResult, 100),
 		followSymlinks:     followSymlinks,
+		useIgnoreFile:      useIgnoreFile,
 		localPathPrefixLen: len(localPathPrefix),
 	}
 	go files.localWalk(localPath)
@@ -238,6 +285,7 @@ type LocalFileIterator struct {
 	filter             *bosFilter
 	filesChan          chan listFileResult
 	followSymlinks     bool
+	useIgnoreFile      bool
 	localPathPrefixLen int
 }
 
@@ -260,14 +308,17 @@ func (l *LocalFileIterator) localWalk(localPath string) {
 		return
 	}
 
-	l.listAllFiles(localPath, fileInfo)
+	l.listAllFiles(localPath, fileInfo, nil)
 
 	// have get all files
 	l.filesChan <- listFileResult{ended: true}
 }
 
 // lInfo is got by Lstat (don't follow symbolic link)
-func (l *LocalFileIterator) listAllFiles(localPath string, lInfo os.FileInfo) {
+// ignores are the ignore files of localPath's parent directories.
+func (l *LocalFileIterator) listAllFiles(localPath string, lInfo os.FileInfo,
+	ignores []*ignoreMatcher) {
+
 	isSymbol := ((lInfo.Mode() & os.ModeSymlink) != 0)
 
 	info, err := os.Stat(localPath)
@@ -299,6 +350,33 @@ func (l *LocalFileIterator) listAllFiles(localPath string, lInfo os.FileInfo) {
 			}
 		}
 
+		// ignored by .bcecmdignore of parent directories
+		if ignored, err := isIgnoredByMatchers(ignores, localPath,
+			util.OsPathSeparator); ignored || err != nil {
+			if err != nil {
+				l.filesChan <- listFileResult{err: err}
+			}
+			return
+		}
+
+		// load .bcecmdignore of this directory, which is used by its children
+		if l.useIgnoreFile {
+			matcher, err := newIgnoreMatcherFromFile(filepath.Join(localPath, IGNORE_FILE_NAME),
+				localPath)
+			if err != nil {
+				l.filesChan <- listFileResult{
+					file: &fileDetail{
+						path: localPath,
+						err:  err,
+					},
+				}
+				return
+			}
+			if matcher != nil {
+				ignores = append(ignores[:len(ignores):len(ignores)], matcher)
+			}
+		}
+
 		// when sort file names, os separator is replace to bos separator
 		names, err := util.ReadSortedDirNames(localPath)
 		if err != nil {
@@ -311,6 +389,10 @@ func (l *LocalFileIterator) listAllFiles(localPath string, lInfo os.FileInfo) {
 			return
 		}
 		for _, name := range names {
+			// the ignore file in directory isn't listed when it is used
+			if l.useIgnoreFile && name == IGNORE_FILE_NAME {
+				continue
+			}
 			fileName := filepath.Join(localPath, name)
 			fileInfo, err := os.Lstat(fileName)
 			if err != nil {
@@ -321,7 +403,7 @@ func (l *LocalFileIterator) listAllFiles(localPath string, lInfo os.FileInfo) {
 					},
 				}
 			} else {
-				l.listAllFiles(fileName, fileInfo)
+				l.listAllFiles(fileName, fileInfo, ignores)
 			}
 		}
 	} else {
@@ -340,6 +422,15 @@ func (l *LocalFileIterator) listAllFiles(localPath string, lInfo os.FileInfo) {
 				gtime:    time.Now().Unix(),
 			},
 		}
+		// ignored by .bcecmdignore
+		if ignored, err := isIgnoredByMatchers(ignores, localPath,
+			util.OsPathSeparator); ignored || err != nil {
+			if err != nil {
+				l.filesChan <- listFileResult{err: err}
+			}
+			return
+		}
+
 		// Filter file by patterns
 		if l.filter != nil {
 			if filtered, err := l.filter.PatternFilter(localPath); filtered || err != nil {
@@ -399,10 +490,10 @@ func checkBosPath(bosPath string) (BosCliErrorCode, error) {
 	if bosPath == "" {
 		return BOSCLI_OK, nil
 	}
-	if !strings.HasPrefix(bosPath, BOS_PATH_PREFIX) {
+	if !isRemotePath(bosPath) {
 		if strings.HasPrefix(bosPath, boscmd.BOS_PATH_SEPARATOR) {
 			return BOSCLI_BOSPATH_IS_INVALID, fmt.Errorf("Invaild BOS path: %s, BOS path must "+
-				"start with bos:/ or bos://", bosPath)
+				"start with bos:/, bos://, s3:// or remote:", bosPath)
 		}
 	}
 	return BOSCLI_OK, nil
@@ -451,11 +542,7 @@ func ErrIsNotExist(err error) bool {
 
 // remove prefix of bosPath
 func FilterPrefixOfBosPath(bosPath string) string {
-	if strings.HasPrefix(bosPath, BOS_PATH_PREFIX_DOUBLE) {
-		return bosPath[len(BOS_PATH_PREFIX_DOUBLE):]
-	} else if strings.HasPrefix(bosPath, BOS_PATH_PREFIX) {
-		return bosPath[len(BOS_PATH_PREFIX):]
-	}
+	_, bosPath, _ = splitRemotePath(bosPath)
 	return bosPath
 }
 
@@ -490,3 +577,49 @@ func getCrc32OfLocalFile(localPath string) (string, error) {
 	crc32Val := hash.Sum32()
 	return strconv.FormatUint(uint64(crc32Val), 10), nil
 }
+
+// Format a struct of S3 API as indented json without empty fields, so that the output of
+// get-xxx can be used as the input of put-xxx.
+func formatApiJson(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	var val interface{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return "", err
+	}
+	return marshalJsonIndent(dropJsonNull(val))
+}
+
+// Marshal v into indented json, characters like '<' are not escaped, as the output is for
+// people rather than html.
+func marshalJsonIndent(v interface{}) (string, error) {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
+
+// remove null values from decoded json recursively
+func dropJsonNull(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if item == nil {
+				delete(v, key)
+			} else {
+				v[key] = dropJsonNull(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = dropJsonNull(item)
+		}
+	}
+	return val
+}
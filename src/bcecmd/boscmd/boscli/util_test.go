@@ -21,6 +21,11 @@ import (
 	"testing"
 )
 
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
 import (
 	"bceconf"
 	"utils/util"
@@ -49,9 +54,11 @@ func initConfig() error {
 }
 
 func TestPrintIfNotQuiet(t *testing.T) {
-	printIfNotQuiet("%s", 123, "345")
-	printIfNotQuiet("%s", 123)
-	printIfNotQuiet("%s")
+	// arguments which don't match the format are printed too
+	format := "%s"
+	printIfNotQuiet(format, 123, "345")
+	printIfNotQuiet(format, 123)
+	printIfNotQuiet(format, []interface{}{}...)
 }
 
 type getStorageClassFromStrType struct {
@@ -284,6 +291,16 @@ func TestSplitBosBucketKey(t *testing.T) {
 			bucketName: "",
 			objectKey:  "",
 		},
+		splitBosBucketKeyType{
+			bosPath:    "s3://bucket/object/key",
+			bucketName: "bucket",
+			objectKey:  "object/key",
+		},
+		splitBosBucketKeyType{
+			bosPath:    "s3://bucket/",
+			bucketName: "bucket",
+			objectKey:  "",
+		},
 	}
 	for i, tCase := range testCases {
 		bucket, object := splitBosBucketKey(tCase.bosPath)
@@ -292,6 +309,84 @@ func TestSplitBosBucketKey(t *testing.T) {
 	}
 }
 
+type splitRemotePathType struct {
+	remotePath string
+	remoteName string
+	path       string
+	isRemote   bool
+}
+
+func TestSplitRemotePath(t *testing.T) {
+	fd, confPath, err := util.CreateAnRandomFileWithContent("[remotes \"backup\"]\n" +
+		"Endpoint = s3.example.com\n")
+	if err != nil {
+		t.Errorf("create config file failed: %s", err)
+		return
+	}
+	fd.Close()
+	defer os.Remove(confPath)
+	fileProvider, err := bceconf.NewFileServerConfigProvider(confPath)
+	if err != nil {
+		t.Errorf("load config file failed: %s", err)
+		return
+	}
+	oldProvider := bceconf.ServerConfigProvider
+	bceconf.ServerConfigProvider = bceconf.NewChainServerConfigProvider(
+		[]bceconf.ServerConfigProviderInterface{fileProvider})
+	defer func() {
+		bceconf.ServerConfigProvider = oldProvider
+	}()
+
+	testCases := []splitRemotePathType{
+		splitRemotePathType{
+			remotePath: "bos:/bucket/object",
+			path:       "bucket/object",
+			isRemote:   true,
+		},
+		splitRemotePathType{
+			remotePath: "bos://bucket/object",
+			path:       "bucket/object",
+			isRemote:   true,
+		},
+		splitRemotePathType{
+			remotePath: "s3://bucket/object",
+			path:       "bucket/object",
+			isRemote:   true,
+		},
+		splitRemotePathType{
+			remotePath: "backup:bucket/object",
+			remoteName: "backup",
+			path:       "bucket/object",
+			isRemote:   true,
+		},
+		splitRemotePathType{
+			remotePath: "unknown:bucket/object",
+			path:       "unknown:bucket/object",
+		},
+		splitRemotePathType{
+			remotePath: "./backup:bucket/object",
+			path:       "./backup:bucket/object",
+		},
+		splitRemotePathType{
+			remotePath: "local/path",
+			path:       "local/path",
+		},
+	}
+	for i, tCase := range testCases {
+		remoteName, path, isRemote := splitRemotePath(tCase.remotePath)
+		util.ExpectEqual("util.go splitRemotePath I", i+1, t.Errorf, tCase.remoteName, remoteName)
+		util.ExpectEqual("util.go splitRemotePath II", i+1, t.Errorf, tCase.path, path)
+		util.ExpectEqual("util.go splitRemotePath III", i+1, t.Errorf, tCase.isRemote, isRemote)
+	}
+}
+
+func TestJoinRemotePath(t *testing.T) {
+	util.ExpectEqual("util.go joinRemotePath", 1, t.Errorf, "bos:/bucket/dir/object",
+		joinRemotePath("", "bucket", "dir/object"))
+	util.ExpectEqual("util.go joinRemotePath", 2, t.Errorf, "backup:bucket/dir/object",
+		joinRemotePath("backup", "bucket", "dir/object"))
+}
+
 type calcDstObjectKeyType struct {
 	src  string
 	dst  string
@@ -842,7 +937,7 @@ func TestListLocalFiles(t *testing.T) {
 			}
 		}
 
-		list := NewLocalFileIterator(tCase.path, filter, tCase.followSymlinks)
+		list := NewLocalFileIterator(tCase.path, filter, tCase.followSymlinks, false)
 		fileNum := 0
 		haveFileErr := false
 		if tCase.isSuc {
@@ -945,6 +1040,14 @@ func TestCheckBosPath(t *testing.T) {
 			src: "bos://liup",
 			ret: BOSCLI_OK,
 		},
+		checkBosPathType{
+			src: "s3://liup",
+			ret: BOSCLI_OK,
+		},
+		checkBosPathType{
+			src: "s3:/liup",
+			ret: BOSCLI_OK,
+		},
 	}
 	for i, tCase := range testCases {
 		ret, _ := checkBosPath(tCase.src)
@@ -1032,3 +1135,10 @@ func TestReplaceToOsPath(t *testing.T) {
 		util.ExpectEqual("tools.go replaceToOsPathType I", i+1, t.Errorf, eRet, ret)
 	}
 }
+
+func TestFormatApiJsonDropNull(t *testing.T) {
+	// empty fields are dropped, and '<' isn't escaped
+	out, err := formatApiJson(&s3.Tag{Key: aws.String("a<b")})
+	util.ExpectEqual("util.go formatApiJson", 1, t.Errorf, nil, err)
+	util.ExpectEqual("util.go formatApiJson", 1, t.Errorf, "{\n  \"Key\": \"a<b\"\n}", out)
+}
@@ -0,0 +1,365 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+// This module provides the version-aware operations of objects: ls --versions, undelete and
+// --version-id of cp, rm and get-object-meta.
+// The version of --version-id is bound to a single object, and only the requests reading this
+// object use the version, so that the destination of cp is never affected.
+
+package boscli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"bcecmd/boscmd"
+	"utils/util"
+)
+
+const (
+	LIST_VERSIONS_MAX_NUM = 1000
+)
+
+type versionedObject struct {
+	bucketName string
+	objectKey  string
+	versionId  string
+}
+
+// the version set by --version-id, bucketName and objectKey are empty until it is bound
+var targetVersion versionedObject
+
+// SetVersionId - set the version of the object read by cp or deleted by rm
+func (b *BosCli) SetVersionId(versionId string) {
+	targetVersion = versionedObject{versionId: versionId}
+}
+
+// bind the version to the object
+func bindVersion(bucketName, objectKey, versionId string) {
+	targetVersion = versionedObject{
+		bucketName: bucketName,
+		objectKey:  objectKey,
+		versionId:  versionId,
+	}
+}
+
+// check the path of --version-id is a single object on BOS and bind the version to it
+func bindVersionOfPath(bosPath string, recursive bool) (BosCliErrorCode, error) {
+	if targetVersion.versionId == "" {
+		return BOSCLI_OK, nil
+	}
+	bucketName, objectKey := splitBosBucketKey(bosPath)
+	if !isRemotePath(bosPath) || recursive || bucketName == "" || objectKey == "" ||
+		strings.HasSuffix(objectKey, boscmd.BOS_PATH_SEPARATOR) {
+		return BOSCLI_VERSION_ID_ONLY_FOR_OBJECT, fmt.Errorf("version id only works for a " +
+			"single object on BOS")
+	}
+	bindVersion(bucketName, objectKey, targetVersion.versionId)
+	return BOSCLI_OK, nil
+}
+
+// the version bound to the object, nil when there is no version bound to it
+func versionIdOf(bucketName, objectKey string) *string {
+	if targetVersion.versionId == "" || targetVersion.bucketName != bucketName ||
+		targetVersion.objectKey != objectKey {
+		return nil
+	}
+	return aws.String(targetVersion.versionId)
+}
+
+// the copy source of object, with the version bound to it
+func copySourceOf(bucketName, objectKey string) string {
+	source := bucketName + util.BOS_PATH_SEPARATOR + objectKey
+	if versionId := versionIdOf(bucketName, objectKey); versionId != nil {
+		source += "?versionId=" + url.QueryEscape(*versionId)
+	}
+	return source
+}
+
+// ListVersions - list the versions and delete markers of objects, the latest version of an
+// object is marked with (latest).
+func (b *BosCli) ListVersions(bosPath string, all, recursive, summary bool,
+	filterArgs *FilterArgs) {
+
+	retCode, err := checkBosPath(bosPath)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	bucketName, objectKey := splitBosBucketKey(bosPath)
+	if bucketName == "" {
+		bcecliAbnormalExistCode(BOSCLI_BUCKETNAME_IS_EMPTY)
+	}
+	if filterArgs != nil && len(filterArgs.TagFilter) > 0 {
+		bcecliAbnormalExistMsg("--tag-filter can't be used with --versions")
+	}
+	filter, retCode, err := newFilterFromArgs(filterArgs, bosPath)
+	if retCode != BOSCLI_OK {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+
+	if err := b.cliOfPath(bosPath).listVersions(bucketName, objectKey, all, recursive, summary,
+		filter); err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+}
+
+// implement list versions
+func (b *BosCli) listVersions(bucketName, objectKey string, all, recursive, summary bool,
+	filter *bosFilter) error {
+
+	var (
+		delimiter       string = boscmd.BOS_PATH_SEPARATOR
+		keyMarker       string
+		versionIdMarker string
+		preNum          int64
+		versionNum      int64
+		markerNum       int64
+	)
+	if recursive {
+		delimiter = ""
+	}
+	trimPos := strings.LastIndex(objectKey, boscmd.BOS_PATH_SEPARATOR) + 1
+
+	for {
+		ret, err := b.bosClient.ListObjectVersions(bucketName, objectKey, delimiter, keyMarker,
+			versionIdMarker, LIST_VERSIONS_MAX_NUM)
+		if err != nil {
+			return err
+		}
+		for _, item := range ret.CommonPrefixes {
+			preNum++
+			fmt.Printf("  %19s %15s  %11s  %32s  %s\n", "", "", "", "PRE",
+				aws.StringValue(item.Prefix)[trimPos:])
+		}
+		for _, version := range sortVersions(ret) {
+			// the object may be an empty dir
+			if !recursive && strings.HasSuffix(version.key, boscmd.BOS_PATH_SEPARATOR) {
+				continue
+			}
+			if filtered, err := version.filtered(bucketName, filter); filtered || err != nil {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			version.print(trimPos)
+			if version.isDeleteMarker {
+				markerNum++
+			} else {
+				versionNum++
+			}
+		}
+
+		if !aws.BoolValue(ret.IsTruncated) {
+			break
+		} else if !all {
+			fmt.Println("more......")
+			break
+		}
+		keyMarker = aws.StringValue(ret.NextKeyMarker)
+		versionIdMarker = aws.StringValue(ret.NextVersionIdMarker)
+	}
+
+	if summary {
+		fmt.Printf("Total PRE(s): %d\n", preNum)
+		fmt.Printf("Total Version(s): %d\n", versionNum)
+		fmt.Printf("Total Delete Marker(s): %d\n", markerNum)
+	}
+	return nil
+}
+
+// a version or a delete marker of object
+type versionDetail struct {
+	key            string
+	versionId      string
+	mtime          int64
+	size           int64
+	storageClass   string
+	isLatest       bool
+	isDeleteMarker bool
+}
+
+// Merge the versions and delete markers of a page of ListObjectVersions, the result is in the
+// order of key and then from the latest to the oldest, just like the response of server.
+func sortVersions(ret *s3.ListObjectVersionsOutput) []*versionDetail {
+	versions := make([]*versionDetail, 0, len(ret.Versions)+len(ret.DeleteMarkers))
+	markers := ret.DeleteMarkers
+	for _, version := range ret.Versions {
+		for len(markers) > 0 && isBefore(markers[0].Key, markers[0].LastModified, version.Key,
+			version.LastModified) {
+			versions = append(versions, deleteMarkerDetail(markers[0]))
+			markers = markers[1:]
+		}
+		versions = append(versions, &versionDetail{
+			key:          aws.StringValue(version.Key),
+			versionId:    aws.StringValue(version.VersionId),
+			mtime:        aws.TimeValue(version.LastModified).Unix(),
+			size:         aws.Int64Value(version.Size),
+			storageClass: aws.StringValue(version.StorageClass),
+			isLatest:     aws.BoolValue(version.IsLatest),
+		})
+	}
+	for _, marker := range markers {
+		versions = append(versions, deleteMarkerDetail(marker))
+	}
+	return versions
+}
+
+func deleteMarkerDetail(marker *s3.DeleteMarkerEntry) *versionDetail {
+	return &versionDetail{
+		key:            aws.StringValue(marker.Key),
+		versionId:      aws.StringValue(marker.VersionId),
+		mtime:          aws.TimeValue(marker.LastModified).Unix(),
+		isLatest:       aws.BoolValue(marker.IsLatest),
+		isDeleteMarker: true,
+	}
+}
+
+// whether the version of key1 is listed before the version of key2
+func isBefore(key1 *string, mtime1 *time.Time, key2 *string, mtime2 *time.Time) bool {
+	k1, k2 := aws.StringValue(key1), aws.StringValue(key2)
+	if k1 != k2 {
+		return k1 < k2
+	}
+	return aws.TimeValue(mtime1).After(aws.TimeValue(mtime2))
+}
+
+// whether the version is filtered by path filter or time filter
+func (v *versionDetail) filtered(bucketName string, filter *bosFilter) (bool, error) {
+	if filter == nil {
+		return false, nil
+	}
+	filtered, err := filter.PatternFilter(bucketName + boscmd.BOS_PATH_SEPARATOR + v.key)
+	if filtered || err != nil {
+		return filtered, err
+	}
+	return filter.TimeFilter(v.mtime), nil
+}
+
+func (v *versionDetail) print(trimPos int) {
+	localTime := util.TranTimestamptoLocalTime(v.mtime, LOCAL_TIME_FROMT)
+	size := fmt.Sprintf("%d", v.size)
+	storageClass := v.storageClass
+	if v.isDeleteMarker {
+		size = "DELETE-MARKER"
+		storageClass = ""
+	}
+	latest := ""
+	if v.isLatest {
+		latest = " (latest)"
+	}
+	fmt.Printf("  %s %15s  %11s  %32s  %s%s\n", localTime, size, storageClass, v.versionId,
+		v.key[trimPos:], latest)
+}
+
+// Undelete - remove the latest delete markers of the object, or of all the objects under the
+// prefix when recursive, so that the previous versions become the current versions again.
+func (b *BosCli) Undelete(bosPath string, recursive, yes, dryrun, quiet bool) {
+	Quiet = quiet
+
+	retCode, err := checkBosPath(bosPath)
+	if err != nil {
+		bcecliAbnormalExistCodeErr(retCode, err)
+	}
+	bucketName, objectKey := splitBosBucketKey(bosPath)
+	if bucketName == "" {
+		bcecliAbnormalExistCode(BOSCLI_BUCKETNAME_IS_EMPTY)
+	}
+	if !recursive && (objectKey == "" ||
+		strings.HasSuffix(objectKey, boscmd.BOS_PATH_SEPARATOR)) {
+		bcecliAbnormalExistMsg("Please use -r to undelete the objects under a prefix")
+	}
+
+	bosClient := b.cliOfPath(bosPath).bosClient
+	markers, err := listLatestDeleteMarkers(bosClient, bucketName, objectKey, recursive)
+	if err != nil {
+		bcecliAbnormalExistErr(err)
+	}
+	if len(markers) == 0 {
+		bcecliAbnormalExistCodeErr(BOSCLI_NO_DELETE_MARKER, fmt.Errorf("there is no delete "+
+			"marker of %s", bosPath))
+	}
+
+	if dryrun {
+		for _, marker := range markers {
+			printIfNotQuiet("(dryrun) Undelete object: %s%s/%s\n", BOS_PATH_PREFIX, bucketName,
+				aws.StringValue(marker.Key))
+		}
+		printIfNotQuiet("[%d] objects would be undeleted.\n", len(markers))
+		return
+	}
+	if recursive && !yes && !util.PromptConfirm("Do you really want to undelete %d objects "+
+		"under %s?", len(markers), bosPath) {
+		return
+	}
+
+	undeleted, failed := 0, 0
+	for _, marker := range markers {
+		key := aws.StringValue(marker.Key)
+		release := transferSched.acquire(0)
+		err := bosClient.DeleteObjectVersion(bucketName, key, aws.StringValue(marker.VersionId))
+		release()
+		if err != nil {
+			printIfNotQuiet("Failed undelete object: %s%s/%s. Error: %s\n", BOS_PATH_PREFIX,
+				bucketName, key, getErrorMsg(err))
+			failed++
+			continue
+		}
+		printIfNotQuiet("Undelete object: %s%s/%s\n", BOS_PATH_PREFIX, bucketName, key)
+		undeleted++
+	}
+	printIfNotQuiet("[%d] objects undeleted, [%d] failed.\n", undeleted, failed)
+	if failed > 0 {
+		bcecliAbnormalExistCode(BOSCLI_EMPTY_CODE)
+	}
+}
+
+// List the delete markers which are the latest versions of objects, only the delete marker of
+// objectKey itself is returned when recursive is false.
+func listLatestDeleteMarkers(bosClient bosClientInterface, bucketName, objectKey string,
+	recursive bool) ([]*s3.DeleteMarkerEntry, error) {
+
+	var (
+		markers         []*s3.DeleteMarkerEntry
+		keyMarker       string
+		versionIdMarker string
+	)
+	for {
+		ret, err := bosClient.ListObjectVersions(bucketName, objectKey, "", keyMarker,
+			versionIdMarker, LIST_VERSIONS_MAX_NUM)
+		if err != nil {
+			return nil, err
+		}
+		for _, marker := range ret.DeleteMarkers {
+			if !aws.BoolValue(marker.IsLatest) {
+				continue
+			}
+			if recursive || aws.StringValue(marker.Key) == objectKey {
+				markers = append(markers, marker)
+			}
+		}
+		if !aws.BoolValue(ret.IsTruncated) {
+			return markers, nil
+		}
+		keyMarker = aws.StringValue(ret.NextKeyMarker)
+		versionIdMarker = aws.StringValue(ret.NextVersionIdMarker)
+	}
+}
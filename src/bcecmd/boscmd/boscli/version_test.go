@@ -0,0 +1,158 @@
+// Copyright 2017 Baidu, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file
+// except in compliance with the License. You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software distributed under the
+// License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing permissions
+// and limitations under the License.
+
+package boscli
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+import (
+	"utils/util"
+)
+
+// client which lists versions from memory, a page each time
+type fakeVersionListClient struct {
+	bosClientInterface
+	pages []*s3.ListObjectVersionsOutput
+}
+
+func (f *fakeVersionListClient) ListObjectVersions(bucket, prefix, delimiter, keyMarker,
+	versionIdMarker string, maxKeys int) (*s3.ListObjectVersionsOutput, error) {
+
+	for i, page := range f.pages {
+		if aws.StringValue(page.KeyMarker) == keyMarker {
+			page.IsTruncated = aws.Bool(i < len(f.pages)-1)
+			if i < len(f.pages)-1 {
+				page.NextKeyMarker = f.pages[i+1].KeyMarker
+				page.NextVersionIdMarker = aws.String("v")
+			}
+			return page, nil
+		}
+	}
+	return &s3.ListObjectVersionsOutput{IsTruncated: aws.Bool(false)}, nil
+}
+
+func newTestVersion(key, versionId string, mtime int64, isLatest bool) *s3.ObjectVersion {
+	return &s3.ObjectVersion{Key: aws.String(key), VersionId: aws.String(versionId),
+		LastModified: aws.Time(time.Unix(mtime, 0)), Size: aws.Int64(1),
+		IsLatest: aws.Bool(isLatest)}
+}
+
+func newTestDeleteMarker(key, versionId string, mtime int64,
+	isLatest bool) *s3.DeleteMarkerEntry {
+
+	return &s3.DeleteMarkerEntry{Key: aws.String(key), VersionId: aws.String(versionId),
+		LastModified: aws.Time(time.Unix(mtime, 0)), IsLatest: aws.Bool(isLatest)}
+}
+
+func TestSortVersions(t *testing.T) {
+	ret := &s3.ListObjectVersionsOutput{
+		Versions: []*s3.ObjectVersion{
+			newTestVersion("a", "a2", 200, true),
+			newTestVersion("a", "a1", 100, false),
+			newTestVersion("b", "b1", 100, false),
+		},
+		DeleteMarkers: []*s3.DeleteMarkerEntry{
+			newTestDeleteMarker("b", "b3", 300, true),
+			newTestDeleteMarker("b", "b2", 150, false),
+			newTestDeleteMarker("c", "c1", 100, true),
+		},
+	}
+	expected := []string{"a2", "a1", "b3", "b2", "b1", "c1"}
+	versions := sortVersions(ret)
+	util.ExpectEqual("sort versions", 1, t.Errorf, len(expected), len(versions))
+	for i, version := range versions {
+		util.ExpectEqual("sort versions", i+2, t.Errorf, expected[i], version.versionId)
+	}
+	util.ExpectEqual("sort versions", 8, t.Errorf, true, versions[2].isDeleteMarker)
+}
+
+func TestListLatestDeleteMarkers(t *testing.T) {
+	client := &fakeVersionListClient{pages: []*s3.ListObjectVersionsOutput{
+		&s3.ListObjectVersionsOutput{
+			KeyMarker: aws.String(""),
+			DeleteMarkers: []*s3.DeleteMarkerEntry{
+				newTestDeleteMarker("dir/a", "a2", 200, true),
+				newTestDeleteMarker("dir/a", "a1", 100, false),
+			},
+		},
+		&s3.ListObjectVersionsOutput{
+			KeyMarker: aws.String("dir/a"),
+			Versions:  []*s3.ObjectVersion{newTestVersion("dir/b", "b2", 200, true)},
+			DeleteMarkers: []*s3.DeleteMarkerEntry{
+				newTestDeleteMarker("dir/b", "b1", 100, false),
+				newTestDeleteMarker("dir/a.txt", "c1", 100, true),
+			},
+		},
+	}}
+
+	//1 recursive
+	markers, err := listLatestDeleteMarkers(client, "bucket", "dir/", true)
+	util.ExpectEqual("list delete markers", 1, t.Errorf, nil, err)
+	util.ExpectEqual("list delete markers", 1, t.Errorf, 2, len(markers))
+
+	//2 single object, the objects with the same prefix are excluded
+	markers, err = listLatestDeleteMarkers(client, "bucket", "dir/a", false)
+	util.ExpectEqual("list delete markers", 2, t.Errorf, nil, err)
+	util.ExpectEqual("list delete markers", 2, t.Errorf, 1, len(markers))
+	util.ExpectEqual("list delete markers", 2, t.Errorf, "a2",
+		aws.StringValue(markers[0].VersionId))
+}
+
+type bindVersionOfPathType struct {
+	versionId string
+	bosPath   string
+	recursive bool
+	retCode   BosCliErrorCode
+}
+
+func TestBindVersionOfPath(t *testing.T) {
+	defer func() { targetVersion = versionedObject{} }()
+
+	testCases := []bindVersionOfPathType{
+		//1 no version
+		bindVersionOfPathType{bosPath: "bos:/bucket/", recursive: true, retCode: BOSCLI_OK},
+		//2
+		bindVersionOfPathType{versionId: "v1", bosPath: "bos:/bucket/a", retCode: BOSCLI_OK},
+		//3 dir
+		bindVersionOfPathType{versionId: "v1", bosPath: "bos:/bucket/dir/",
+			retCode: BOSCLI_VERSION_ID_ONLY_FOR_OBJECT},
+		//4 recursive
+		bindVersionOfPathType{versionId: "v1", bosPath: "bos:/bucket/a", recursive: true,
+			retCode: BOSCLI_VERSION_ID_ONLY_FOR_OBJECT},
+		//5 local path
+		bindVersionOfPathType{versionId: "v1", bosPath: "./a",
+			retCode: BOSCLI_VERSION_ID_ONLY_FOR_OBJECT},
+	}
+	for i, tCase := range testCases {
+		testBosCliForVersion := &BosCli{}
+		testBosCliForVersion.SetVersionId(tCase.versionId)
+		retCode, _ := bindVersionOfPath(tCase.bosPath, tCase.recursive)
+		util.ExpectEqual("bind version", i+1, t.Errorf, tCase.retCode, retCode)
+	}
+
+	// the version is only used by the bound object
+	bindVersion("bucket", "a b", "v/1")
+	util.ExpectEqual("bind version", 6, t.Errorf, "v/1",
+		aws.StringValue(versionIdOf("bucket", "a b")))
+	util.ExpectEqual("bind version", 7, t.Errorf, true, versionIdOf("bucket", "a") == nil)
+	util.ExpectEqual("bind version", 8, t.Errorf, "bucket/a b?versionId=v%2F1",
+		copySourceOf("bucket", "a b"))
+	util.ExpectEqual("bind version", 9, t.Errorf, "bucket/a", copySourceOf("bucket", "a"))
+}
@@ -19,6 +19,10 @@ import (
     "time"
 )
 
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
 import (
 	"utils/util"
 )
@@ -33,6 +37,25 @@ func getObjectMeta(bosClient bosClientInterface, bucketName,
 	if err != nil {
 		return nil, err
 	}
+	return fileDetailOfObjectMeta(objectKey, getMetaRet)
+}
+
+// get the meta of the destination object of cp and sync
+func getDestinationObjectMeta(bosClient bosClientInterface, bucketName,
+	objectKey string) (*fileDetail, error) {
+	if bucketName == "" || objectKey == "" {
+		return nil, fmt.Errorf("bucket name and object name can not be empty!")
+	}
+
+	getMetaRet, err := bosClient.GetDestinationObjectMeta(bucketName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	return fileDetailOfObjectMeta(objectKey, getMetaRet)
+}
+
+func fileDetailOfObjectMeta(objectKey string, getMetaRet *s3.HeadObjectOutput) (*fileDetail,
+	error) {
 	// utc to timestamp
 	mtime, err := util.TranUTCTimeStringToTimeStamp(getMetaRet.LastModified, BOS_HTTP_TIME_FORMT)
 	if err != nil {
@@ -40,10 +63,14 @@ func getObjectMeta(bosClient bosClientInterface, bucketName,
 	}
 
 	fileInfo := &fileDetail{
-		path:  objectKey,
-		mtime: mtime,
-		gtime: time.Now().Unix(),
-		size:  *getMetaRet.ContentLength,
+		path:     objectKey,
+		mtime:    mtime,
+		gtime:    time.Now().Unix(),
+		size:     *getMetaRet.ContentLength,
+		metadata: getMetaRet.Metadata,
+	}
+	if getMetaRet.ETag != nil {
+		fileInfo.etag = *getMetaRet.ETag
 	}
 	if getMetaRet.StorageClass != nil {
 		fileInfo.storageClass = *getMetaRet.StorageClass
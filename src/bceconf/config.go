@@ -137,6 +137,7 @@ func ConfigInteractive(configDirPath string) {
 		newMultiUploadThreadNum     string
 		newSyncProcessingNum        string
 		newMultiUploadPartSize      string
+		newMultiUploadMaxMemory     string
 	)
 
 	// Init Configuration info
@@ -345,23 +346,49 @@ func ConfigInteractive(configDirPath string) {
 		propmtMultiUploadPartSize = EMPTY_STRING
 	}
 	fmt.Printf("Default multi upload part size [%s] MB (Must be positive integer and equal or "+
-		"greater than 1) : ", propmtMultiUploadPartSize)
+		"greater than %d) : ", propmtMultiUploadPartSize, MIN_PART_SIZE)
 	scanner.Scan()
 	newMultiUploadPartSize = strings.TrimSpace(scanner.Text())
 	if newMultiUploadPartSize != "" {
 		if strings.ToLower(newMultiUploadPartSize) == EMPTY_STRING {
 			newMultiUploadPartSize = ""
 		} else {
-			if val, ok := strconv.ParseInt(newMultiUploadPartSize, 10, 64); ok != nil || val < 1 {
+			val, ok := strconv.ParseInt(newMultiUploadPartSize, 10, 64)
+			if ok != nil || val < MIN_PART_SIZE {
 				fmt.Printf("Input multi upload part size must be a positive integer and equal or "+
-					"greater than 1, [%s] is not valid, default multi upload part size [%s] "+
-					"is used\n", newMultiUploadPartSize, propmtMultiUploadPartSize)
+					"greater than %d, [%s] is not valid, default multi upload part size [%s] "+
+					"is used\n", MIN_PART_SIZE, newMultiUploadPartSize, propmtMultiUploadPartSize)
 				newMultiUploadPartSize = ""
 			}
 		}
 		serverConfigFileProvider.SetMultiUploadPartSize(newMultiUploadPartSize)
 	}
 
+	// Config max memory of buffered parts
+	var propmtMultiUploadMaxMemory string
+	if multiUploadMaxMemory, ok := ServerConfigProvider.GetMultiUploadMaxMemory(); ok {
+		propmtMultiUploadMaxMemory = strconv.FormatInt(multiUploadMaxMemory, 10)
+	} else {
+		propmtMultiUploadMaxMemory = EMPTY_STRING
+	}
+	fmt.Printf("Default max memory of buffered parts [%s] MB (Must be integer and equal or "+
+		"greater than 0, 0 means no limit) : ", propmtMultiUploadMaxMemory)
+	scanner.Scan()
+	newMultiUploadMaxMemory = strings.TrimSpace(scanner.Text())
+	if newMultiUploadMaxMemory != "" {
+		if strings.ToLower(newMultiUploadMaxMemory) == EMPTY_STRING {
+			newMultiUploadMaxMemory = ""
+		} else {
+			if val, ok := strconv.ParseInt(newMultiUploadMaxMemory, 10, 64); ok != nil || val < 0 {
+				fmt.Printf("Input max memory of buffered parts must be integer and equal or "+
+					"greater than 0, [%s] is not valid, default max memory [%s] is used\n",
+					newMultiUploadMaxMemory, propmtMultiUploadMaxMemory)
+				newMultiUploadMaxMemory = ""
+			}
+		}
+		serverConfigFileProvider.SetMultiUploadMaxMemory(newMultiUploadMaxMemory)
+	}
+
 	credentialFileProvider.save()
 	serverConfigFileProvider.save()
 }
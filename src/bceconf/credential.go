@@ -34,14 +34,23 @@ type CredentialDefaultsCfg struct {
 	Sts string // Security Token
 }
 
+// Store remote name => the credential of remote
+type RemoteCredentialCfg struct {
+	Ak  string // access key
+	Sk  string // secret key
+	Sts string // Security Token
+}
+
 type CredentialCfg struct {
 	Defaults CredentialDefaultsCfg
+	Remotes  map[string]*RemoteCredentialCfg
 }
 
 type CredentialProviderInterface interface {
 	GetAccessKey() (string, bool)
 	GetSecretKey() (string, bool)
 	GetSecurityToken() (string, bool)
+	GetRemoteCredential(string) (*RemoteCredentialCfg, bool)
 }
 
 // New credential configuration provider
@@ -96,6 +105,18 @@ func (f *FileCredentialProvider) GetSecurityToken() (string, bool) {
 	return DEFAULT_STS, false
 }
 
+// Get the credential of remote, ak and sk of it must not be empty.
+func (f *FileCredentialProvider) GetRemoteCredential(name string) (*RemoteCredentialCfg, bool) {
+	if name == "" || len(f.cfg.Remotes) == 0 {
+		return nil, false
+	}
+	cred, ok := f.cfg.Remotes[name]
+	if ok && cred != nil && cred.Ak != "" && cred.Sk != "" {
+		return cred, true
+	}
+	return nil, false
+}
+
 // Set Access key.
 func (f *FileCredentialProvider) SetAccessKey(ak string) {
 	if ak != f.cfg.Defaults.Ak {
@@ -153,6 +174,10 @@ func (n *DefaultCredentialProvider) GetSecurityToken() (string, bool) {
 	return DEFAULT_STS, true
 }
 
+func (n *DefaultCredentialProvider) GetRemoteCredential(name string) (*RemoteCredentialCfg, bool) {
+	return nil, false
+}
+
 func NewChainCredentialProvider(chain []CredentialProviderInterface) *ChainCredentialProvider {
 	return &ChainCredentialProvider{chain: chain}
 }
@@ -190,3 +215,13 @@ func (c *ChainCredentialProvider) GetSecurityToken() (string, bool) {
 	panic("There is no security token found!")
 	return DEFAULT_STS, false
 }
+
+// remote credential is optional, so don't panic when there is no credential found
+func (c *ChainCredentialProvider) GetRemoteCredential(name string) (*RemoteCredentialCfg, bool) {
+	for _, provider := range c.chain {
+		if val, ok := provider.GetRemoteCredential(name); ok {
+			return val, true
+		}
+	}
+	return nil, false
+}
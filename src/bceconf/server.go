@@ -31,6 +31,14 @@ const (
 	BREAKPIONT_FILE_EXPIRATION_OPTION_NAME = "breakpoint_file_expiration"
 	USE_HTTPS_OPTION_NAME                  = "https"
 	MULTI_UPLOAD_THREAD_NUM_NAME           = "multi_upload_thread_num"
+	MULTI_UPLOAD_MAX_MEMORY_NAME           = "multi_upload_max_memory"
+	MULTI_UPLOAD_THRESHOLD_NAME            = "multi_upload_threshold"
+	MULTI_DOWNLOAD_THRESHOLD_NAME          = "multi_download_threshold"
+	MULTI_DOWNLOAD_PART_SIZE_NAME          = "multi_download_part_size"
+	MULTI_DOWNLOAD_THREAD_NUM_NAME         = "multi_download_thread_num"
+	MULTI_COPY_THRESHOLD_NAME              = "multi_copy_threshold"
+	MULTI_COPY_PART_SIZE_NAME              = "multi_copy_part_size"
+	MULTI_COPY_THREAD_NUM_NAME             = "multi_copy_thread_num"
 	DEFAULT_DOMAIN_SUFFIX                  = ".bcebos.com"
 	DEFAULT_REGION                         = "bj"
 	DEFAULT_USE_AUTO_SWITCH_DOMAIN         = "yes"
@@ -39,8 +47,20 @@ const (
 	DEFAULT_MULTI_UPLOAD_THREAD_NUM        = "10"
 	DEFAULT_MULTI_UPLOAD_PART_SIZE         = "10"
 	DEFAULT_SYNC_PROCESSING_NUM            = "10"
+	DEFAULT_MULTI_UPLOAD_MAX_MEMORY        = "512"
+	DEFAULT_MULTI_UPLOAD_THRESHOLD         = "32"
+	DEFAULT_MULTI_DOWNLOAD_THRESHOLD       = "100"
+	DEFAULT_MULTI_DOWNLOAD_PART_SIZE       = "10"
+	DEFAULT_MULTI_DOWNLOAD_THREAD_NUM      = "10"
+	DEFAULT_MULTI_COPY_THRESHOLD           = "100"
+	DEFAULT_MULTI_COPY_PART_SIZE           = "50"
+	DEFAULT_MULTI_COPY_THREAD_NUM          = "10"
+	MAX_PARTS                              = 10000   // max number of parts of an object
+	MAX_PART_SIZE                          = 5 << 10 // 5G, the unit is MB
+	MIN_PART_SIZE                          = 5       // 5M, the min part size of upload and copy
 	WILL_USE_AUTO_SWTICH_DOMAIN            = "yes"
 	DOMAINS_SECTION_NAME                   = "domains"
+	REMOTES_SECTION_NAME                   = "remotes"
 )
 
 var (
@@ -80,6 +100,14 @@ type ServerDefaultsCfg struct {
 	MultiUploadThreadNum     string
 	SyncProcessingNum        string
 	MultiUploadPartSize      string
+	MultiUploadMaxMemory     string
+	MultiUploadThreshold     string
+	MultiDownloadThreshold   string
+	MultiDownloadPartSize    string
+	MultiDownloadThreadNum   string
+	MultiCopyThreshold       string
+	MultiCopyPartSize        string
+	MultiCopyThreadNum       string
 }
 
 // Store region => domain
@@ -87,9 +115,58 @@ type EndpointCfg struct {
 	Endpoint string
 }
 
+// Store remote name => the server of remote
+// a remote is used in path like "remote:bucket/key"
+type RemoteCfg struct {
+	Endpoint string
+	Region   string
+	Https    string
+}
+
 type ServerConfig struct {
 	Defaults ServerDefaultsCfg
 	Domains  map[string]*EndpointCfg
+	Remotes  map[string]*RemoteCfg
+}
+
+// Parse an integer option which must be equal or greater than min
+func parseInt64Option(val string, min int64) (int64, bool) {
+	if val == "" {
+		return 0, false
+	}
+	ret, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || ret < min {
+		return 0, false
+	}
+	return ret, true
+}
+
+// Check the threshold and part size of multipart upload, download or copy, the default value is
+// used when the option is empty.
+// An object just larger than the threshold must be able to be split into at most MAX_PARTS parts.
+func checkMultipartConfig(name, threshold, partSize, defaultThreshold, defaultPartSize string,
+	minPartSize int64) error {
+
+	if threshold == "" {
+		threshold = defaultThreshold
+	}
+	if partSize == "" {
+		partSize = defaultPartSize
+	}
+	thresholdVal, ok := parseInt64Option(threshold, 1)
+	if !ok {
+		return fmt.Errorf("%s threshold must be integer and greater than zero!", name)
+	}
+	partSizeVal, ok := parseInt64Option(partSize, minPartSize)
+	if !ok || partSizeVal > MAX_PART_SIZE {
+		return fmt.Errorf("%s part size must be integer and between %d and %d!", name,
+			minPartSize, MAX_PART_SIZE)
+	}
+	if thresholdVal > partSizeVal*MAX_PARTS {
+		return fmt.Errorf("%s threshold must not be greater than %d times of part size!", name,
+			MAX_PARTS)
+	}
+	return nil
 }
 
 func checkConfig(cfg *ServerConfig) error {
@@ -121,6 +198,49 @@ func checkConfig(cfg *ServerConfig) error {
 			return fmt.Errorf("part size must greater than zero!")
 		}
 	}
+	if cfg.Defaults.MultiUploadMaxMemory != "" {
+		val, ok := strconv.Atoi(cfg.Defaults.MultiUploadMaxMemory)
+		if ok != nil || val < 0 {
+			return fmt.Errorf("multi upload max memory must be integer and equal or greater " +
+				"than zero!")
+		}
+	}
+	if err := checkMultipartConfig("multi upload", cfg.Defaults.MultiUploadThreshold,
+		cfg.Defaults.MultiUploadPartSize, DEFAULT_MULTI_UPLOAD_THRESHOLD,
+		DEFAULT_MULTI_UPLOAD_PART_SIZE, MIN_PART_SIZE); err != nil {
+		return err
+	}
+	if err := checkMultipartConfig("multi download", cfg.Defaults.MultiDownloadThreshold,
+		cfg.Defaults.MultiDownloadPartSize, DEFAULT_MULTI_DOWNLOAD_THRESHOLD,
+		DEFAULT_MULTI_DOWNLOAD_PART_SIZE, 1); err != nil {
+		return err
+	}
+	if err := checkMultipartConfig("multi copy", cfg.Defaults.MultiCopyThreshold,
+		cfg.Defaults.MultiCopyPartSize, DEFAULT_MULTI_COPY_THRESHOLD,
+		DEFAULT_MULTI_COPY_PART_SIZE, MIN_PART_SIZE); err != nil {
+		return err
+	}
+	if cfg.Defaults.MultiDownloadThreadNum != "" {
+		if _, ok := parseInt64Option(cfg.Defaults.MultiDownloadThreadNum, 1); !ok {
+			return fmt.Errorf("Multi download thread number must be integer and greater " +
+				"than zero!")
+		}
+	}
+	if cfg.Defaults.MultiCopyThreadNum != "" {
+		if _, ok := parseInt64Option(cfg.Defaults.MultiCopyThreadNum, 1); !ok {
+			return fmt.Errorf("Multi copy thread number must be integer and greater than zero!")
+		}
+	}
+	for name, remote := range cfg.Remotes {
+		if remote == nil || remote.Endpoint == "" {
+			return fmt.Errorf("the endpoint of remote %s is empty!", name)
+		}
+		if remote.Https != "" {
+			if _, ok := AOLLOWED_CONFIRM_OPTIONS[remote.Https]; !ok {
+				return fmt.Errorf("the https of remote %s must be 'yes' or 'no'!", name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -134,6 +254,15 @@ type ServerConfigProviderInterface interface {
 	GetMultiUploadThreadNum() (int64, bool)
 	GetSyncProcessingNum() (int, bool)
 	GetMultiUploadPartSize() (int64, bool)
+	GetMultiUploadMaxMemory() (int64, bool)
+	GetMultiUploadThreshold() (int64, bool)
+	GetMultiDownloadThreshold() (int64, bool)
+	GetMultiDownloadPartSize() (int64, bool)
+	GetMultiDownloadThreadNum() (int64, bool)
+	GetMultiCopyThreshold() (int64, bool)
+	GetMultiCopyPartSize() (int64, bool)
+	GetMultiCopyThreadNum() (int64, bool)
+	GetRemote(string) (*RemoteCfg, bool)
 }
 
 // New file configuration provider
@@ -273,6 +402,65 @@ func (f *FileServerConfigProvider) GetMultiUploadPartSize() (int64, bool) {
 	return 0, false
 }
 
+// Get max memory (MB) of buffered parts, 0 means no limit
+func (f *FileServerConfigProvider) GetMultiUploadMaxMemory() (int64, bool) {
+	if f.cfg.Defaults.MultiUploadMaxMemory != "" {
+		if val, ok := strconv.ParseInt(f.cfg.Defaults.MultiUploadMaxMemory, 10, 64); ok == nil {
+			if val >= 0 {
+				return val, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Get the threshold (MB) of multipart upload
+func (f *FileServerConfigProvider) GetMultiUploadThreshold() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiUploadThreshold, 1)
+}
+
+// Get the threshold (MB) of multipart download
+func (f *FileServerConfigProvider) GetMultiDownloadThreshold() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiDownloadThreshold, 1)
+}
+
+// Get the part size (MB) of multipart download
+func (f *FileServerConfigProvider) GetMultiDownloadPartSize() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiDownloadPartSize, 1)
+}
+
+// Get the thread num of multipart download
+func (f *FileServerConfigProvider) GetMultiDownloadThreadNum() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiDownloadThreadNum, 1)
+}
+
+// Get the threshold (MB) of multipart copy
+func (f *FileServerConfigProvider) GetMultiCopyThreshold() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiCopyThreshold, 1)
+}
+
+// Get the part size (MB) of multipart copy
+func (f *FileServerConfigProvider) GetMultiCopyPartSize() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiCopyPartSize, 1)
+}
+
+// Get the thread num of multipart copy
+func (f *FileServerConfigProvider) GetMultiCopyThreadNum() (int64, bool) {
+	return parseInt64Option(f.cfg.Defaults.MultiCopyThreadNum, 1)
+}
+
+// Get the server configuration of remote
+func (f *FileServerConfigProvider) GetRemote(name string) (*RemoteCfg, bool) {
+	if name == "" || len(f.cfg.Remotes) == 0 {
+		return nil, false
+	}
+	remote, ok := f.cfg.Remotes[name]
+	if ok && remote != nil && remote.Endpoint != "" {
+		return remote, true
+	}
+	return nil, false
+}
+
 // param domain: Set server domain address
 // domain can be empty
 func (f *FileServerConfigProvider) SetDomain(domain string) {
@@ -380,6 +568,15 @@ func (f *FileServerConfigProvider) SetMultiUploadPartSize(multiUploadPartSize st
 	return true
 }
 
+// set max memory of buffered parts
+func (f *FileServerConfigProvider) SetMultiUploadMaxMemory(multiUploadMaxMemory string) bool {
+	if multiUploadMaxMemory != f.cfg.Defaults.MultiUploadMaxMemory {
+		f.cfg.Defaults.MultiUploadMaxMemory = multiUploadMaxMemory
+		f.dirty = true
+	}
+	return true
+}
+
 // Save configuration into file
 func (f *FileServerConfigProvider) save() error {
 	if f.configFilePath == "" {
@@ -494,6 +691,58 @@ func (d *DefaultServerConfigProvider) GetSyncProcessingNum() (int, bool) {
 	return 0, false
 }
 
+// Get default max memory of buffered parts
+func (d *DefaultServerConfigProvider) GetMultiUploadMaxMemory() (int64, bool) {
+	if DEFAULT_MULTI_UPLOAD_MAX_MEMORY != "" {
+		if val, ok := strconv.ParseInt(DEFAULT_MULTI_UPLOAD_MAX_MEMORY, 10, 64); ok == nil {
+			if val >= 0 {
+				return val, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Get default threshold (MB) of multipart upload
+func (d *DefaultServerConfigProvider) GetMultiUploadThreshold() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_UPLOAD_THRESHOLD, 1)
+}
+
+// Get default threshold (MB) of multipart download
+func (d *DefaultServerConfigProvider) GetMultiDownloadThreshold() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_DOWNLOAD_THRESHOLD, 1)
+}
+
+// Get default part size (MB) of multipart download
+func (d *DefaultServerConfigProvider) GetMultiDownloadPartSize() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_DOWNLOAD_PART_SIZE, 1)
+}
+
+// Get default thread num of multipart download
+func (d *DefaultServerConfigProvider) GetMultiDownloadThreadNum() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_DOWNLOAD_THREAD_NUM, 1)
+}
+
+// Get default threshold (MB) of multipart copy
+func (d *DefaultServerConfigProvider) GetMultiCopyThreshold() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_COPY_THRESHOLD, 1)
+}
+
+// Get default part size (MB) of multipart copy
+func (d *DefaultServerConfigProvider) GetMultiCopyPartSize() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_COPY_PART_SIZE, 1)
+}
+
+// Get default thread num of multipart copy
+func (d *DefaultServerConfigProvider) GetMultiCopyThreadNum() (int64, bool) {
+	return parseInt64Option(DEFAULT_MULTI_COPY_THREAD_NUM, 1)
+}
+
+// There is no default remote
+func (d *DefaultServerConfigProvider) GetRemote(name string) (*RemoteCfg, bool) {
+	return nil, false
+}
+
 func NewChainServerConfigProvider(chain []ServerConfigProviderInterface) *ChainServerConfigProvider {
 	return &ChainServerConfigProvider{chain: chain}
 }
@@ -609,3 +858,111 @@ func (c *ChainServerConfigProvider) GetMultiUploadPartSize() (int64, bool) {
 	panic("There is no MultiUploadPartSize found!")
 	return 0, false
 }
+
+// Get max memory of buffered parts
+func (c *ChainServerConfigProvider) GetMultiUploadMaxMemory() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiUploadMaxMemory()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiUploadMaxMemory found!")
+	return 0, false
+}
+
+// Get the threshold (MB) of multipart upload
+func (c *ChainServerConfigProvider) GetMultiUploadThreshold() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiUploadThreshold()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiUploadThreshold found!")
+	return 0, false
+}
+
+// Get the threshold (MB) of multipart download
+func (c *ChainServerConfigProvider) GetMultiDownloadThreshold() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiDownloadThreshold()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiDownloadThreshold found!")
+	return 0, false
+}
+
+// Get the part size (MB) of multipart download
+func (c *ChainServerConfigProvider) GetMultiDownloadPartSize() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiDownloadPartSize()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiDownloadPartSize found!")
+	return 0, false
+}
+
+// Get the thread num of multipart download
+func (c *ChainServerConfigProvider) GetMultiDownloadThreadNum() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiDownloadThreadNum()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiDownloadThreadNum found!")
+	return 0, false
+}
+
+// Get the threshold (MB) of multipart copy
+func (c *ChainServerConfigProvider) GetMultiCopyThreshold() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiCopyThreshold()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiCopyThreshold found!")
+	return 0, false
+}
+
+// Get the part size (MB) of multipart copy
+func (c *ChainServerConfigProvider) GetMultiCopyPartSize() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiCopyPartSize()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiCopyPartSize found!")
+	return 0, false
+}
+
+// Get the thread num of multipart copy
+func (c *ChainServerConfigProvider) GetMultiCopyThreadNum() (int64, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetMultiCopyThreadNum()
+		if ok {
+			return val, true
+		}
+	}
+	panic("There is no MultiCopyThreadNum found!")
+	return 0, false
+}
+
+// Get the server configuration of remote
+// remote is optional, so don't panic when there is no remote found
+func (c *ChainServerConfigProvider) GetRemote(name string) (*RemoteCfg, bool) {
+	for _, provider := range c.chain {
+		val, ok := provider.GetRemote(name)
+		if ok {
+			return val, true
+		}
+	}
+	return nil, false
+}
@@ -232,6 +232,63 @@ func TestServerCheckConfig(t *testing.T) {
 			},
 			isErr: false,
 		},
+		serverCheckConfigType{
+			cfg: &ServerConfig{
+				Defaults: ServerDefaultsCfg{
+					MultiDownloadThreshold: "0",
+				},
+			},
+			isErr: true,
+			err:   fmt.Errorf("multi download threshold must be integer and greater than zero!"),
+		},
+		serverCheckConfigType{
+			cfg: &ServerConfig{
+				Defaults: ServerDefaultsCfg{
+					MultiCopyPartSize: "6000",
+				},
+			},
+			isErr: true,
+			err:   fmt.Errorf("multi copy part size must be integer and between 5 and 5120!"),
+		},
+		serverCheckConfigType{
+			cfg: &ServerConfig{
+				Defaults: ServerDefaultsCfg{
+					MultiUploadPartSize: "4",
+				},
+			},
+			isErr: true,
+			err:   fmt.Errorf("multi upload part size must be integer and between 5 and 5120!"),
+		},
+		serverCheckConfigType{
+			cfg: &ServerConfig{
+				Defaults: ServerDefaultsCfg{
+					MultiUploadThreshold: "100001",
+				},
+			},
+			isErr: true,
+			err: fmt.Errorf("multi upload threshold must not be greater than 10000 times of " +
+				"part size!"),
+		},
+		serverCheckConfigType{
+			cfg: &ServerConfig{
+				Defaults: ServerDefaultsCfg{
+					MultiCopyThreadNum: "0",
+				},
+			},
+			isErr: true,
+			err:   fmt.Errorf("Multi copy thread number must be integer and greater than zero!"),
+		},
+		serverCheckConfigType{
+			cfg: &ServerConfig{
+				Defaults: ServerDefaultsCfg{
+					MultiUploadThreshold:   "100000",
+					MultiDownloadPartSize:  "1",
+					MultiDownloadThreshold: "10000",
+					MultiDownloadThreadNum: "20",
+				},
+			},
+			isErr: false,
+		},
 	}
 	for i, tCase := range testCases {
 		err := checkConfig(tCase.cfg)
@@ -608,6 +665,57 @@ func TestGetSyncProcessingNum(t *testing.T) {
 	}
 }
 
+type getRemoteType struct {
+	provider *FileServerConfigProvider
+	name     string
+	endpoint string
+	isSuc    bool
+}
+
+func TestGetRemote(t *testing.T) {
+	remoteProvider := &FileServerConfigProvider{
+		cfg: &ServerConfig{
+			Remotes: map[string]*RemoteCfg{
+				"backup": &RemoteCfg{
+					Endpoint: "s3.example.com",
+					Region:   "us-east-1",
+				},
+				"empty": &RemoteCfg{},
+			},
+		},
+	}
+	testCases := []getRemoteType{
+		getRemoteType{
+			provider: remoteProvider,
+			name:     "backup",
+			endpoint: "s3.example.com",
+			isSuc:    true,
+		},
+		getRemoteType{
+			provider: remoteProvider,
+			name:     "empty",
+			isSuc:    false,
+		},
+		getRemoteType{
+			provider: remoteProvider,
+			name:     "",
+			isSuc:    false,
+		},
+		getRemoteType{
+			provider: fileServerProvider1,
+			name:     "backup",
+			isSuc:    false,
+		},
+	}
+	for i, tCase := range testCases {
+		ret, ok := tCase.provider.GetRemote(tCase.name)
+		util.ExpectEqual("server.go GetRemote I", i+1, t.Errorf, tCase.isSuc, ok)
+		if tCase.isSuc {
+			util.ExpectEqual("server.go GetRemote II", i+1, t.Errorf, tCase.endpoint, ret.Endpoint)
+		}
+	}
+}
+
 type setDomainType struct {
 	provider *FileServerConfigProvider
 	domain   string
@@ -1173,6 +1281,91 @@ func TestDGetMultiUploadPartSize(t *testing.T) {
 	}
 }
 
+func TestDGetMultiUploadMaxMemory(t *testing.T) {
+	ret, ok := defaultServerProvider.GetMultiUploadMaxMemory()
+	util.ExpectEqual("server.go DE GetMultiUploadMaxMemory I", 1, t.Errorf, true, ok)
+	util.ExpectEqual("server.go DE GetMultiUploadMaxMemory II", 1, t.Errorf, int64(512), ret)
+}
+
+func TestSetMultiUploadMaxMemory(t *testing.T) {
+	testCases := []setSetUseAutoSwitchDomain{
+		setSetUseAutoSwitchDomain{
+			provider: fileServerProvider4,
+			use:      "100",
+			dirty:    true,
+		},
+		setSetUseAutoSwitchDomain{
+			provider: fileServerProvider4,
+			use:      "100",
+			dirty:    false,
+		},
+	}
+	for i, tCase := range testCases {
+		tCase.provider.dirty = false
+		tCase.provider.SetMultiUploadMaxMemory(tCase.use)
+		util.ExpectEqual("server.go SetMultiUploadMaxMemory I", i+1, t.Errorf, tCase.dirty,
+			tCase.provider.dirty)
+		util.ExpectEqual("server.go SetMultiUploadMaxMemory II", i+1, t.Errorf, tCase.use,
+			tCase.provider.cfg.Defaults.MultiUploadMaxMemory)
+		ret, ok := tCase.provider.GetMultiUploadMaxMemory()
+		util.ExpectEqual("server.go SetMultiUploadMaxMemory III", i+1, t.Errorf, true, ok)
+		util.ExpectEqual("server.go SetMultiUploadMaxMemory IV", i+1, t.Errorf, int64(100), ret)
+	}
+}
+
+type getMultipartOptionType struct {
+	get   func() (int64, bool)
+	ret   int64
+	isSuc bool
+}
+
+func TestGetMultipartOptions(t *testing.T) {
+	provider := &FileServerConfigProvider{
+		cfg: &ServerConfig{
+			Defaults: ServerDefaultsCfg{
+				MultiUploadThreshold:   "64",
+				MultiDownloadThreshold: "0",
+				MultiDownloadPartSize:  "abc",
+				MultiCopyThreadNum:     "5",
+			},
+		},
+	}
+	chain := NewChainServerConfigProvider([]ServerConfigProviderInterface{provider,
+		defaultServerProvider})
+
+	testCases := []getMultipartOptionType{
+		//1
+		getMultipartOptionType{get: provider.GetMultiUploadThreshold, ret: 64, isSuc: true},
+		//2
+		getMultipartOptionType{get: provider.GetMultiDownloadThreshold, isSuc: false},
+		//3
+		getMultipartOptionType{get: provider.GetMultiDownloadPartSize, isSuc: false},
+		//4
+		getMultipartOptionType{get: provider.GetMultiCopyPartSize, isSuc: false},
+		//5
+		getMultipartOptionType{get: chain.GetMultiDownloadThreshold, ret: 100, isSuc: true},
+		//6
+		getMultipartOptionType{get: chain.GetMultiDownloadPartSize, ret: 10, isSuc: true},
+		//7
+		getMultipartOptionType{get: chain.GetMultiDownloadThreadNum, ret: 10, isSuc: true},
+		//8
+		getMultipartOptionType{get: chain.GetMultiCopyThreshold, ret: 100, isSuc: true},
+		//9
+		getMultipartOptionType{get: chain.GetMultiCopyPartSize, ret: 50, isSuc: true},
+		//10
+		getMultipartOptionType{get: chain.GetMultiCopyThreadNum, ret: 5, isSuc: true},
+		//11
+		getMultipartOptionType{get: chain.GetMultiUploadThreshold, ret: 64, isSuc: true},
+	}
+	for i, tCase := range testCases {
+		ret, ok := tCase.get()
+		util.ExpectEqual("server.go GetMultipartOptions I", i+1, t.Errorf, tCase.isSuc, ok)
+		if tCase.isSuc {
+			util.ExpectEqual("server.go GetMultipartOptions II", i+1, t.Errorf, tCase.ret, ret)
+		}
+	}
+}
+
 func TestDGetSyncProcessingNum(t *testing.T) {
 	testCases := []defaultGetType{
 		defaultGetType{
@@ -10,13 +10,32 @@ import (
 	"errors"
 	"os"
 	"runtime"
+	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
 const (
 	Separator = os.PathSeparator
+	Globstar  = "**"
 )
 
+// POSIX character classes, which can be used in character class like [[:digit:]]
+var posixClasses = map[string]func(rune) bool{
+	"alnum":  func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) },
+	"alpha":  unicode.IsLetter,
+	"blank":  func(r rune) bool { return r == ' ' || r == '\t' },
+	"cntrl":  unicode.IsControl,
+	"digit":  unicode.IsDigit,
+	"graph":  func(r rune) bool { return unicode.IsGraphic(r) && !unicode.IsSpace(r) },
+	"lower":  unicode.IsLower,
+	"print":  unicode.IsPrint,
+	"punct":  unicode.IsPunct,
+	"space":  unicode.IsSpace,
+	"upper":  unicode.IsUpper,
+	"xdigit": func(r rune) bool { return strings.ContainsRune("0123456789abcdefABCDEF", r) },
+}
+
 // ErrBadPattern indicates a globbing pattern was malformed.
 var ErrBadPattern = errors.New("syntax error in pattern")
 
@@ -26,9 +45,10 @@ var ErrBadPattern = errors.New("syntax error in pattern")
 //	pattern:
 //		{ term }
 //	term:
-//		'*'         matches any sequence
+//		'*'         matches any sequence, including path separator
+//		'**' '/'    matches zero or more directories
 //		'?'         matches any single character
-//		'[' [ '^' ] { character-range } ']'
+//		'[' [ '^' | '!' ] { character-range } ']'
 //		            character class (must be non-empty)
 //		c           matches character c (c != '*', '?', '\\', '[')
 //		'\\' c      matches character c
@@ -37,6 +57,8 @@ var ErrBadPattern = errors.New("syntax error in pattern")
 //		c           matches character c (c != '\\', '-', ']')
 //		'\\' c      matches character c
 //		lo '-' hi   matches character c for lo <= c <= hi
+//		'[:' name ':]'
+//		            matches POSIX character class, such as [:alpha:], [:digit:]
 //
 // Match requires pattern to match all of name, not just a substring.
 // The only possible returned error is ErrBadPattern, when pattern
@@ -46,6 +68,65 @@ var ErrBadPattern = errors.New("syntax error in pattern")
 // path separator.
 //
 func Match(pattern, name string) (matched bool, err error) {
+	if matched, err = matchPattern(pattern, name); matched || err != nil {
+		return
+	}
+
+	// "**/" matches one or more directories by '*', try to match zero directory.
+	for i := 0; i+len(Globstar) < len(pattern); i++ {
+		if !strings.HasPrefix(pattern[i:], Globstar) ||
+			!isPathSeparator(pattern[i+len(Globstar)]) ||
+			(i > 0 && !isPathSeparator(pattern[i-1])) {
+			continue
+		}
+		if matched, err = Match(pattern[:i]+pattern[i+len(Globstar)+1:], name); matched ||
+			err != nil {
+			return
+		}
+	}
+	return false, nil
+}
+
+// MatchPath reports whether name matches the pattern like Match, but '*' and '?' never
+// match '/', and the "**" between '/' matches zero or more directories, just like gitignore.
+// both pattern and name must use '/' as path separator.
+func MatchPath(pattern, name string) (bool, error) {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(patterns, names []string) (bool, error) {
+	for len(patterns) > 0 {
+		if patterns[0] == Globstar {
+			patterns = patterns[1:]
+			// trailing "**" matches everything inside
+			if len(patterns) == 0 {
+				return len(names) > 0, nil
+			}
+			for i := 0; i <= len(names); i++ {
+				if matched, err := matchSegments(patterns, names[i:]); matched || err != nil {
+					return matched, err
+				}
+			}
+			return false, nil
+		}
+		if len(names) == 0 {
+			return false, nil
+		}
+		if matched, err := matchPattern(patterns[0], names[0]); !matched || err != nil {
+			return false, err
+		}
+		patterns = patterns[1:]
+		names = names[1:]
+	}
+	return len(names) == 0, nil
+}
+
+func isPathSeparator(c byte) bool {
+	return c == '/' || c == Separator
+}
+
+// match pattern without globstar
+func matchPattern(pattern, name string) (matched bool, err error) {
 Pattern:
 	for len(pattern) > 0 {
 		var star bool
@@ -143,7 +224,7 @@ func matchChunk(chunk, s string) (rest string, ok bool, err error) {
 				return
 			}
 			// possibly negated
-			negated := chunk[0] == '^'
+			negated := chunk[0] == '^' || chunk[0] == '!'
 			if negated {
 				chunk = chunk[1:]
 			}
@@ -155,6 +236,18 @@ func matchChunk(chunk, s string) (rest string, ok bool, err error) {
 					chunk = chunk[1:]
 					break
 				}
+				// POSIX character class
+				if strings.HasPrefix(chunk, "[:") {
+					var isClass func(rune) bool
+					if isClass, chunk, err = getPosixClass(chunk); err != nil {
+						return
+					}
+					if isClass(r) {
+						match = true
+					}
+					nrange++
+					continue
+				}
 				var lo, hi rune
 				if lo, chunk, err = getEsc(chunk); err != nil {
 					return
@@ -205,6 +298,25 @@ func matchChunk(chunk, s string) (rest string, ok bool, err error) {
 	return s, true, nil
 }
 
+// getPosixClass gets a POSIX character class like [:alpha:] from chunk.
+func getPosixClass(chunk string) (isClass func(rune) bool, nchunk string, err error) {
+	end := strings.Index(chunk, ":]")
+	if end < 0 {
+		err = ErrBadPattern
+		return
+	}
+	isClass, ok := posixClasses[chunk[2:end]]
+	if !ok {
+		err = ErrBadPattern
+		return
+	}
+	nchunk = chunk[end+2:]
+	if len(nchunk) == 0 {
+		err = ErrBadPattern
+	}
+	return
+}
+
 // getEsc gets a possibly-escaped character from chunk, for a character class.
 func getEsc(chunk string) (r rune, nchunk string, err error) {
 	if len(chunk) == 0 || chunk[0] == '-' || chunk[0] == ']' {
@@ -109,6 +109,60 @@ func TestPatternFilter(t *testing.T) {
 			name:    "abs/liupeng/gz.jpg",
 			matched: false,
 		},
+		// 15 globstar matches zero directory
+		MatchType{
+			pattern: "abs/**/liupeng.jpg",
+			name:    "abs/liupeng.jpg",
+			matched: true,
+		},
+		// 16 globstar matches multiple directories
+		MatchType{
+			pattern: "abs/**/liupeng.jpg",
+			name:    "abs/a/b/c/liupeng.jpg",
+			matched: true,
+		},
+		// 17
+		MatchType{
+			pattern: "**/test/*.jpg",
+			name:    "test/liupeng.jpg",
+			matched: true,
+		},
+		// 18
+		MatchType{
+			pattern: "abs/**/liupeng.jpg",
+			name:    "abs/a/liupeng.png",
+			matched: false,
+		},
+		// 19 negated character class
+		MatchType{
+			pattern: "abs/[!a-z].jpg",
+			name:    "abs/1.jpg",
+			matched: true,
+		},
+		// 20
+		MatchType{
+			pattern: "abs/[!a-z].jpg",
+			name:    "abs/g.jpg",
+			matched: false,
+		},
+		// 21 POSIX character class
+		MatchType{
+			pattern: "abs/[[:digit:]][[:digit:]].jpg",
+			name:    "abs/12.jpg",
+			matched: true,
+		},
+		// 22
+		MatchType{
+			pattern: "abs/[[:upper:]_].jpg",
+			name:    "abs/g.jpg",
+			matched: false,
+		},
+		// 23
+		MatchType{
+			pattern: "abs/[^[:alpha:]].jpg",
+			name:    "abs/_.jpg",
+			matched: true,
+		},
 	}
 
 	for i, tCase := range testCases {
@@ -123,3 +177,71 @@ func TestPatternFilter(t *testing.T) {
 			matched)
 	}
 }
+
+func TestMatchBadPattern(t *testing.T) {
+	patterns := []string{"abs/[[:unknown:]].jpg", "abs/[[:digit:].jpg", "abs/[a-"}
+	for i, pattern := range patterns {
+		_, err := Match(pattern, "abs/1.jpg")
+		ExpectEqual("match.go Match bad pattern", i+1, t.Errorf, ErrBadPattern, err)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+
+	testCases := []MatchType{
+		// 1 '*' doesn't match '/'
+		MatchType{
+			pattern: "abs/*.jpg",
+			name:    "abs/adbc/liupeng.jpg",
+			matched: false,
+		},
+		// 2
+		MatchType{
+			pattern: "abs/*.jpg",
+			name:    "abs/liupeng.jpg",
+			matched: true,
+		},
+		// 3
+		MatchType{
+			pattern: "abs/**/*.jpg",
+			name:    "abs/liupeng.jpg",
+			matched: true,
+		},
+		// 4
+		MatchType{
+			pattern: "abs/**/*.jpg",
+			name:    "abs/a/b/liupeng.jpg",
+			matched: true,
+		},
+		// 5
+		MatchType{
+			pattern: "**/build",
+			name:    "a/b/build",
+			matched: true,
+		},
+		// 6 trailing globstar matches everything inside
+		MatchType{
+			pattern: "abs/**",
+			name:    "abs/a/b",
+			matched: true,
+		},
+		// 7
+		MatchType{
+			pattern: "abs/**",
+			name:    "abs",
+			matched: false,
+		},
+		// 8
+		MatchType{
+			pattern: "abs/[[:digit:]]*/x",
+			name:    "abs/1a/x",
+			matched: true,
+		},
+	}
+
+	for i, tCase := range testCases {
+		matched, err := MatchPath(tCase.pattern, tCase.name)
+		ExpectEqual("match.go MatchPath I", i+1, t.Errorf, true, err == nil)
+		ExpectEqual("match.go MatchPath II", i+1, t.Errorf, tCase.matched, matched)
+	}
+}